@@ -0,0 +1,111 @@
+// Package admin implements HTTP API to manage the gateway. It's served on a
+// separate listener and isn't a part of the S3 API.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"go.uber.org/zap"
+)
+
+type (
+	// Handler serves admin API requests.
+	Handler struct {
+		log *zap.Logger
+		obj layer.Client
+		cfg *Config
+	}
+
+	// Config contains parameters of the admin API.
+	Config struct {
+		// Token authorizes admin API requests.
+		Token string
+		// PoolStatistic returns statistic of the NeoFS connection pool.
+		PoolStatistic func() pool.Statistic
+		// PoolErrorThreshold is a number of the current errors after which the pool
+		// considers the node unhealthy.
+		PoolErrorThreshold uint32
+		// TreeStatistic returns statistic of the tree service endpoints, it's optional.
+		TreeStatistic func() []neofs.TreeEndpointStatistic
+		// Settings returns effective gateway settings with secrets redacted.
+		Settings func() map[string]interface{}
+		// Resolver resolves bucket names to container IDs.
+		Resolver *resolver.BucketResolver
+		// LogLevel is a level of the gateway logger changeable at runtime.
+		LogLevel zap.AtomicLevel
+	}
+
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+)
+
+const bearerPrefix = "Bearer "
+
+// NewRouter creates a router with admin API routes. All requests must be
+// authorized with the configured token in the Authorization header.
+func NewRouter(log *zap.Logger, obj layer.Client, cfg *Config) http.Handler {
+	h := &Handler{log: log, obj: obj, cfg: cfg}
+
+	router := mux.NewRouter()
+	router.Use(authMiddleware(cfg.Token))
+
+	v1 := router.PathPrefix("/api/v1").Subrouter()
+	v1.Methods(http.MethodGet).Path("/buckets/{bucket}/quota").HandlerFunc(h.GetBucketQuotaHandler)
+	v1.Methods(http.MethodPut).Path("/buckets/{bucket}/quota").HandlerFunc(h.PutBucketQuotaHandler)
+	v1.Methods(http.MethodDelete).Path("/buckets/{bucket}/quota").HandlerFunc(h.DeleteBucketQuotaHandler)
+	v1.Methods(http.MethodGet).Path("/buckets/{bucket}/usage").HandlerFunc(h.GetBucketUsageHandler)
+	v1.Methods(http.MethodPut).Path("/buckets/{bucket}/usage").HandlerFunc(h.PutBucketUsageHandler)
+	v1.Methods(http.MethodDelete).Path("/buckets/{bucket}/usage").HandlerFunc(h.DeleteBucketUsageHandler)
+	v1.Methods(http.MethodGet).Path("/buckets/{bucket}/fsck").HandlerFunc(h.GetBucketCheckHandler)
+	v1.Methods(http.MethodPost).Path("/buckets/{bucket}/fsck").HandlerFunc(h.PostBucketCheckHandler)
+	v1.Methods(http.MethodPost).Path("/buckets/{bucket}/rebuild").HandlerFunc(h.PostBucketRebuildHandler)
+	v1.Methods(http.MethodGet).Path("/pool").HandlerFunc(h.GetPoolHandler)
+	v1.Methods(http.MethodGet).Path("/config").HandlerFunc(h.GetConfigHandler)
+	v1.Methods(http.MethodGet).Path("/log/level").HandlerFunc(h.GetLogLevelHandler)
+	v1.Methods(http.MethodPut).Path("/log/level").HandlerFunc(h.PutLogLevelHandler)
+	v1.Methods(http.MethodGet).Path("/caches").HandlerFunc(h.GetCachesHandler)
+	v1.Methods(http.MethodDelete).Path("/caches/{kind}").HandlerFunc(h.DeleteCacheHandler)
+	v1.Methods(http.MethodGet).Path("/resolve/{bucket}").HandlerFunc(h.GetResolveHandler)
+	v1.Methods(http.MethodGet).Path("/multipart").HandlerFunc(h.GetStaleMultipartHandler)
+	v1.Methods(http.MethodDelete).Path("/multipart").HandlerFunc(h.DeleteStaleMultipartHandler)
+
+	return router
+}
+
+func authMiddleware(token string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if !strings.HasPrefix(header, bearerPrefix) ||
+				subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, bearerPrefix)), []byte(token)) != 1 {
+				writeError(w, http.StatusUnauthorized, "invalid or missing token")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		h.log.Error("couldn't write response", zap.Error(err))
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{Error: msg})
+}
@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := authMiddleware("secret")(next)
+
+	for _, tc := range []struct {
+		name   string
+		header string
+		status int
+	}{
+		{name: "valid token", header: "Bearer secret", status: http.StatusOK},
+		{name: "invalid token", header: "Bearer invalid", status: http.StatusUnauthorized},
+		{name: "missing prefix", header: "secret", status: http.StatusUnauthorized},
+		{name: "missing header", status: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/buckets/bucket/quota", nil)
+			if len(tc.header) > 0 {
+				r.Header.Set("Authorization", tc.header)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, r)
+			require.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	const token = "secret"
+	lvl := zap.NewAtomicLevelAt(zap.InfoLevel)
+	router := NewRouter(zap.NewNop(), nil, &Config{Token: token, LogLevel: lvl})
+
+	do := func(method, body string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/api/v1/log/level", strings.NewReader(body))
+		r.Header.Set("Authorization", bearerPrefix+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do(http.MethodPut, `{"level":"debug"}`)
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, zapcore.DebugLevel, lvl.Level())
+
+	w = do(http.MethodGet, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp LogLevelRequest
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "debug", resp.Level)
+
+	w = do(http.MethodPut, `{"level":"verbose"}`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Equal(t, zapcore.DebugLevel, lvl.Level())
+}
+
+func TestRebuildInvalidParams(t *testing.T) {
+	const token = "secret"
+	router := NewRouter(zap.NewNop(), nil, &Config{Token: token})
+
+	for _, query := range []string{"dry_run=maybe", "external_only=maybe", "min_age=hour", "min_age=-1h"} {
+		t.Run(query, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/buckets/bucket/rebuild?"+query, nil)
+			r.Header.Set("Authorization", bearerPrefix+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, r)
+			require.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+// CacheResponse contains statistic of the cache.
+type CacheResponse struct {
+	Kind   string `json:"kind"`
+	Size   int    `json:"size"`
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// GetCachesHandler returns statistic of the gateway caches.
+func (h *Handler) GetCachesHandler(w http.ResponseWriter, _ *http.Request) {
+	stats := h.obj.CacheStats()
+	res := make([]CacheResponse, len(stats))
+	for i, stat := range stats {
+		res[i] = CacheResponse{
+			Kind:   stat.Kind,
+			Size:   stat.Size,
+			Hits:   stat.Hits,
+			Misses: stat.Misses,
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, res)
+}
+
+// DeleteCacheHandler removes all entries from the cache of the kind. Caches of other
+// gateway instances aren't affected.
+func (h *Handler) DeleteCacheHandler(w http.ResponseWriter, r *http.Request) {
+	kind := mux.Vars(r)["kind"]
+	if err := h.obj.FlushCache(kind); err != nil {
+		if errors.Is(err, layer.ErrUnknownCacheKind) {
+			writeError(w, http.StatusNotFound, "unknown cache kind: "+kind)
+			return
+		}
+		h.internalError(w, "couldn't flush cache", err)
+		return
+	}
+
+	h.log.Info("cache flushed", zap.String("kind", kind))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,165 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+const (
+	queryMinAge = "min_age"
+	queryDelete = "delete"
+
+	defaultOrphanMinAge = time.Hour
+)
+
+type (
+	// BucketCheckResponse is a response of the bucket consistency check requests.
+	BucketCheckResponse struct {
+		CheckedNodes   int                    `json:"checked_nodes"`
+		CheckedObjects int                    `json:"checked_objects"`
+		DanglingNodes  []DanglingNodeResponse `json:"dangling_nodes"`
+		OrphanObjects  []OrphanObjectResponse `json:"orphan_objects"`
+	}
+
+	// DanglingNodeResponse describes a tree node referencing a missing object.
+	DanglingNodeResponse struct {
+		Type     string `json:"type"`
+		NodeID   uint64 `json:"node_id"`
+		Key      string `json:"key,omitempty"`
+		UploadID string `json:"upload_id,omitempty"`
+		OID      string `json:"oid"`
+		Repaired bool   `json:"repaired"`
+	}
+
+	// OrphanObjectResponse describes an object not referenced by the tree.
+	OrphanObjectResponse struct {
+		OID      string     `json:"oid"`
+		FilePath string     `json:"file_path,omitempty"`
+		UploadID string     `json:"upload_id,omitempty"`
+		Size     uint64     `json:"size"`
+		Created  *time.Time `json:"created,omitempty"`
+		Gateway  bool       `json:"gateway"`
+		Repaired bool       `json:"repaired"`
+	}
+)
+
+func newBucketCheckResponse(res *layer.BucketCheckResult) *BucketCheckResponse {
+	resp := &BucketCheckResponse{
+		CheckedNodes:   res.CheckedNodes,
+		CheckedObjects: res.CheckedObjects,
+		DanglingNodes:  make([]DanglingNodeResponse, 0, len(res.DanglingNodes)),
+		OrphanObjects:  make([]OrphanObjectResponse, 0, len(res.OrphanObjects)),
+	}
+
+	for _, node := range res.DanglingNodes {
+		resp.DanglingNodes = append(resp.DanglingNodes, DanglingNodeResponse{
+			Type:     node.Type,
+			NodeID:   node.NodeID,
+			Key:      node.Key,
+			UploadID: node.UploadID,
+			OID:      node.OID.EncodeToString(),
+			Repaired: node.Repaired,
+		})
+	}
+
+	for _, obj := range res.OrphanObjects {
+		orphan := OrphanObjectResponse{
+			OID:      obj.OID.EncodeToString(),
+			FilePath: obj.FilePath,
+			UploadID: obj.UploadID,
+			Size:     obj.Size,
+			Gateway:  obj.Gateway,
+			Repaired: obj.Repaired,
+		}
+		if !obj.Created.IsZero() {
+			created := obj.Created
+			orphan.Created = &created
+		}
+		resp.OrphanObjects = append(resp.OrphanObjects, orphan)
+	}
+
+	return resp
+}
+
+// GetBucketCheckHandler checks consistency of the bucket tree and NeoFS objects
+// without any modifications.
+func (h *Handler) GetBucketCheckHandler(w http.ResponseWriter, r *http.Request) {
+	h.checkBucket(w, r, false)
+}
+
+// PostBucketCheckHandler checks consistency of the bucket tree and NeoFS objects,
+// removes dangling version and CORS nodes and orphan objects created by the gateway
+// or listed in delete query parameters.
+func (h *Handler) PostBucketCheckHandler(w http.ResponseWriter, r *http.Request) {
+	h.checkBucket(w, r, true)
+}
+
+func (h *Handler) checkBucket(w http.ResponseWriter, r *http.Request, repair bool) {
+	minAge, err := parseMinAge(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	toDelete, err := parseObjectIDs(r.URL.Query()[queryDelete])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !repair && len(toDelete) > 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("%s is allowed for repair only", queryDelete))
+		return
+	}
+
+	bktInfo, ok := h.getBucketInfo(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.obj.CheckBucket(r.Context(), &layer.CheckBucketParams{
+		BktInfo:       bktInfo,
+		Repair:        repair,
+		OrphanMinAge:  minAge,
+		DeleteObjects: toDelete,
+	})
+	if err != nil {
+		h.internalError(w, "couldn't check bucket", err)
+		return
+	}
+
+	h.log.Info("bucket consistency checked", zap.String("bucket", bktInfo.Name), zap.Bool("repair", repair),
+		zap.Int("dangling_nodes", len(res.DanglingNodes)), zap.Int("orphan_objects", len(res.OrphanObjects)))
+	h.writeJSON(w, http.StatusOK, newBucketCheckResponse(res))
+}
+
+func parseMinAge(r *http.Request) (time.Duration, error) {
+	value := r.URL.Query().Get(queryMinAge)
+	if value == "" {
+		return defaultOrphanMinAge, nil
+	}
+
+	minAge, err := time.ParseDuration(value)
+	if err != nil || minAge < 0 {
+		return 0, fmt.Errorf("invalid %s: %s", queryMinAge, value)
+	}
+
+	return minAge, nil
+}
+
+func parseObjectIDs(values []string) ([]oid.ID, error) {
+	ids := make([]oid.ID, 0, len(values))
+	for _, value := range values {
+		var id oid.ID
+		if err := id.DecodeString(value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", queryDelete, value)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
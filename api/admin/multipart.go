@@ -0,0 +1,178 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"go.uber.org/zap"
+)
+
+const (
+	queryOlderThan = "older_than"
+	queryBucket    = "bucket"
+	queryOwner     = "owner"
+)
+
+type (
+	// StaleMultipartResponse is a response of the stale multipart uploads requests.
+	StaleMultipartResponse struct {
+		Uploads []MultipartUploadResponse `json:"uploads"`
+		// Errors contains uploads which couldn't be aborted.
+		Errors []MultipartUploadResponse `json:"errors,omitempty"`
+	}
+
+	// MultipartUploadResponse describes the multipart upload.
+	MultipartUploadResponse struct {
+		Bucket   string    `json:"bucket"`
+		Key      string    `json:"key"`
+		UploadID string    `json:"upload_id"`
+		Owner    string    `json:"owner"`
+		Created  time.Time `json:"created"`
+		Error    string    `json:"error,omitempty"`
+	}
+
+	staleUpload struct {
+		bktInfo *data.BucketInfo
+		info    *layer.UploadInfo
+	}
+)
+
+func (u staleUpload) response() MultipartUploadResponse {
+	return MultipartUploadResponse{
+		Bucket:   u.bktInfo.Name,
+		Key:      u.info.Key,
+		UploadID: u.info.UploadID,
+		Owner:    u.info.Owner.EncodeToString(),
+		Created:  u.info.Created,
+	}
+}
+
+// GetStaleMultipartHandler lists multipart uploads initiated earlier than the
+// older_than duration ago in the buckets specified by names or by the owner.
+func (h *Handler) GetStaleMultipartHandler(w http.ResponseWriter, r *http.Request) {
+	uploads, ok := h.getStaleUploads(w, r)
+	if !ok {
+		return
+	}
+
+	res := &StaleMultipartResponse{Uploads: make([]MultipartUploadResponse, len(uploads))}
+	for i, upload := range uploads {
+		res.Uploads[i] = upload.response()
+	}
+
+	h.writeJSON(w, http.StatusOK, res)
+}
+
+// DeleteStaleMultipartHandler aborts multipart uploads selected the same way as
+// GetStaleMultipartHandler does. Failed uploads are reported in errors.
+func (h *Handler) DeleteStaleMultipartHandler(w http.ResponseWriter, r *http.Request) {
+	uploads, ok := h.getStaleUploads(w, r)
+	if !ok {
+		return
+	}
+
+	res := &StaleMultipartResponse{Uploads: make([]MultipartUploadResponse, 0, len(uploads))}
+	for _, upload := range uploads {
+		err := h.obj.AbortMultipartUpload(r.Context(), &layer.UploadInfoParams{
+			UploadID: upload.info.UploadID,
+			Bkt:      upload.bktInfo,
+			Key:      upload.info.Key,
+		})
+		if err != nil {
+			h.log.Error("couldn't abort multipart upload", zap.String("bucket", upload.bktInfo.Name),
+				zap.String("key", upload.info.Key), zap.String("upload id", upload.info.UploadID), zap.Error(err))
+			resp := upload.response()
+			resp.Error = err.Error()
+			res.Errors = append(res.Errors, resp)
+			continue
+		}
+		res.Uploads = append(res.Uploads, upload.response())
+	}
+
+	h.log.Info("stale multipart uploads aborted", zap.Int("aborted", len(res.Uploads)),
+		zap.Int("failed", len(res.Errors)))
+	h.writeJSON(w, http.StatusOK, res)
+}
+
+func (h *Handler) getStaleUploads(w http.ResponseWriter, r *http.Request) ([]staleUpload, bool) {
+	query := r.URL.Query()
+
+	olderThan, err := time.ParseDuration(query.Get(queryOlderThan))
+	if err != nil || olderThan <= 0 {
+		writeError(w, http.StatusBadRequest, "invalid "+queryOlderThan+": "+query.Get(queryOlderThan))
+		return nil, false
+	}
+
+	buckets, ok := h.getBuckets(w, r, query[queryBucket], query.Get(queryOwner))
+	if !ok {
+		return nil, false
+	}
+
+	createdBefore := time.Now().Add(-olderThan)
+	var res []staleUpload
+	for _, bktInfo := range buckets {
+		uploads, err := h.obj.ListStaleMultipartUploads(r.Context(), bktInfo, createdBefore)
+		if err != nil {
+			h.internalError(w, "couldn't list multipart uploads of the bucket "+bktInfo.Name, err)
+			return nil, false
+		}
+		for _, info := range uploads {
+			res = append(res, staleUpload{bktInfo: bktInfo, info: info})
+		}
+	}
+
+	return res, true
+}
+
+// getBuckets returns buckets with the names and buckets of the owner without duplicates.
+func (h *Handler) getBuckets(w http.ResponseWriter, r *http.Request, names []string, owner string) ([]*data.BucketInfo, bool) {
+	if len(names) == 0 && owner == "" {
+		writeError(w, http.StatusBadRequest, queryBucket+" or "+queryOwner+" must be specified")
+		return nil, false
+	}
+
+	var res []*data.BucketInfo
+	seen := make(map[string]struct{})
+	add := func(bktInfo *data.BucketInfo) {
+		if _, ok := seen[bktInfo.Name]; !ok {
+			seen[bktInfo.Name] = struct{}{}
+			res = append(res, bktInfo)
+		}
+	}
+
+	for _, name := range names {
+		bktInfo, err := h.obj.GetBucketInfo(r.Context(), name)
+		if err != nil {
+			if apiErrors.IsS3Error(err, apiErrors.ErrNoSuchBucket) {
+				writeError(w, http.StatusNotFound, "bucket not found: "+name)
+				return nil, false
+			}
+			h.internalError(w, "couldn't get bucket info", err)
+			return nil, false
+		}
+		add(bktInfo)
+	}
+
+	if owner != "" {
+		var ownerID user.ID
+		if err := ownerID.DecodeString(owner); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid "+queryOwner+": "+owner)
+			return nil, false
+		}
+
+		buckets, err := h.obj.ListUserBuckets(r.Context(), ownerID)
+		if err != nil {
+			h.internalError(w, "couldn't list buckets of the owner", err)
+			return nil, false
+		}
+		for _, bktInfo := range buckets {
+			add(bktInfo)
+		}
+	}
+
+	return res, true
+}
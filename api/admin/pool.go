@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"net/http"
+)
+
+type (
+	// PoolResponse is a response of the connection pool status request.
+	PoolResponse struct {
+		OverallErrors uint64             `json:"overall_errors"`
+		Nodes         []PoolNodeResponse `json:"nodes"`
+		TreeEndpoints []TreeNodeResponse `json:"tree_endpoints,omitempty"`
+	}
+
+	// PoolNodeResponse contains statistic of the NeoFS node.
+	PoolNodeResponse struct {
+		Address       string `json:"address"`
+		Healthy       bool   `json:"healthy"`
+		Requests      uint64 `json:"requests"`
+		OverallErrors uint64 `json:"overall_errors"`
+		CurrentErrors uint32 `json:"current_errors"`
+	}
+
+	// TreeNodeResponse contains statistic of the tree service endpoint.
+	TreeNodeResponse struct {
+		Address  string `json:"address"`
+		Healthy  bool   `json:"healthy"`
+		Requests uint64 `json:"requests"`
+		Errors   uint64 `json:"errors"`
+	}
+)
+
+// GetPoolHandler returns health and statistic of the NeoFS nodes and the tree service endpoints.
+func (h *Handler) GetPoolHandler(w http.ResponseWriter, _ *http.Request) {
+	if h.cfg.PoolStatistic == nil {
+		writeError(w, http.StatusNotImplemented, "pool statistic isn't available")
+		return
+	}
+
+	stat := h.cfg.PoolStatistic()
+	res := &PoolResponse{
+		OverallErrors: stat.OverallErrors(),
+		Nodes:         make([]PoolNodeResponse, 0, len(stat.Nodes())),
+	}
+
+	for _, node := range stat.Nodes() {
+		res.Nodes = append(res.Nodes, PoolNodeResponse{
+			Address: node.Address(),
+			// the pool marks the node unhealthy when the current errors reach the threshold
+			Healthy:       h.cfg.PoolErrorThreshold == 0 || node.CurrentErrors() < h.cfg.PoolErrorThreshold,
+			Requests:      node.Requests(),
+			OverallErrors: node.OverallErrors(),
+			CurrentErrors: node.CurrentErrors(),
+		})
+	}
+
+	if h.cfg.TreeStatistic != nil {
+		for _, endpoint := range h.cfg.TreeStatistic() {
+			res.TreeEndpoints = append(res.TreeEndpoints, TreeNodeResponse{
+				Address:  endpoint.Address,
+				Healthy:  endpoint.Healthy,
+				Requests: endpoint.Requests,
+				Errors:   endpoint.Errors,
+			})
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+// BucketQuotaResponse is a response of the GetBucketQuota request.
+type BucketQuotaResponse struct {
+	Quota *data.BucketQuota    `json:"quota"`
+	Usage *BucketUsageResponse `json:"usage"`
+}
+
+// GetBucketQuotaHandler returns quota and current usage of the bucket.
+func (h *Handler) GetBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	bktInfo, ok := h.getBucketInfo(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.internalError(w, "couldn't get bucket settings", err)
+		return
+	}
+
+	resp := BucketQuotaResponse{Quota: settings.Quota}
+	if h.obj.BucketUsageTracked(settings) {
+		usage, err := h.obj.GetBucketUsage(r.Context(), bktInfo)
+		if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+			h.internalError(w, "couldn't get bucket usage", err)
+			return
+		}
+		resp.Usage = newBucketUsageResponse(usage, true)
+	}
+
+	h.writeJSON(w, http.StatusOK, resp)
+}
+
+// PutBucketQuotaHandler sets quota of the bucket. Zero value of the limit means no limit.
+func (h *Handler) PutBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	bktInfo, ok := h.getBucketInfo(w, r)
+	if !ok {
+		return
+	}
+
+	quota := new(data.BucketQuota)
+	if err := json.NewDecoder(r.Body).Decode(quota); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid quota: "+err.Error())
+		return
+	}
+
+	if err := h.obj.PutBucketQuota(r.Context(), &layer.PutBucketQuotaParams{BktInfo: bktInfo, Quota: quota}); err != nil {
+		h.internalError(w, "couldn't put bucket quota", err)
+		return
+	}
+
+	h.log.Info("bucket quota updated", zap.String("bucket", bktInfo.Name), zap.Uint64("max_size", quota.MaxSize),
+		zap.Uint64("max_objects", quota.MaxObjects))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteBucketQuotaHandler removes quota of the bucket.
+func (h *Handler) DeleteBucketQuotaHandler(w http.ResponseWriter, r *http.Request) {
+	bktInfo, ok := h.getBucketInfo(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.obj.PutBucketQuota(r.Context(), &layer.PutBucketQuotaParams{BktInfo: bktInfo}); err != nil {
+		h.internalError(w, "couldn't delete bucket quota", err)
+		return
+	}
+
+	h.log.Info("bucket quota removed", zap.String("bucket", bktInfo.Name))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) getBucketInfo(w http.ResponseWriter, r *http.Request) (*data.BucketInfo, bool) {
+	bktInfo, err := h.obj.GetBucketInfo(r.Context(), mux.Vars(r)["bucket"])
+	if err != nil {
+		if apiErrors.IsS3Error(err, apiErrors.ErrNoSuchBucket) {
+			writeError(w, http.StatusNotFound, "bucket not found")
+			return nil, false
+		}
+		h.internalError(w, "couldn't get bucket info", err)
+		return nil, false
+	}
+
+	return bktInfo, true
+}
+
+func (h *Handler) internalError(w http.ResponseWriter, msg string, err error) {
+	h.log.Error(msg, zap.Error(err))
+	writeError(w, http.StatusInternalServerError, msg)
+}
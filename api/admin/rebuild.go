@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+const (
+	queryDryRun       = "dry_run"
+	queryExternalOnly = "external_only"
+)
+
+// BucketRebuildResponse is a response of the bucket tree rebuild request.
+type BucketRebuildResponse struct {
+	DryRun                bool `json:"dry_run"`
+	Keys                  int  `json:"keys"`
+	Versions              int  `json:"versions"`
+	Skipped               int  `json:"skipped"`
+	Postponed             int  `json:"postponed"`
+	SettingsRestored      bool `json:"settings_restored"`
+	CORSRestored          bool `json:"cors_restored"`
+	NotificationsRestored bool `json:"notifications_restored"`
+}
+
+// PostBucketRebuildHandler restores tree nodes of the bucket from the attributes of NeoFS objects.
+// Objects younger than min_age query parameter are postponed, since they can belong to
+// the writes in progress which tree nodes aren't added yet.
+func (h *Handler) PostBucketRebuildHandler(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseBool(r, queryDryRun)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	externalOnly, err := parseBool(r, queryExternalOnly)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	minAge, err := parseMinAge(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	bktInfo, ok := h.getBucketInfo(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.obj.RebuildBucketTree(r.Context(), &layer.RebuildBucketParams{
+		BktInfo:      bktInfo,
+		DryRun:       dryRun,
+		MinAge:       minAge,
+		ExternalOnly: externalOnly,
+	})
+	if err != nil {
+		h.internalError(w, "couldn't rebuild bucket tree", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, &BucketRebuildResponse{
+		DryRun:                dryRun,
+		Keys:                  res.Keys,
+		Versions:              res.Versions,
+		Skipped:               res.Skipped,
+		Postponed:             res.Postponed,
+		SettingsRestored:      res.SettingsRestored,
+		CORSRestored:          res.CORSRestored,
+		NotificationsRestored: res.NotificationsRestored,
+	})
+}
+
+func parseBool(r *http.Request, key string) (bool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	res, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %s", key, value)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,35 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// ResolveResponse contains result of the bucket name resolving by the resolver.
+type ResolveResponse struct {
+	Resolver    string `json:"resolver"`
+	ContainerID string `json:"container_id,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// GetResolveHandler resolves the bucket name by every configured resolver.
+func (h *Handler) GetResolveHandler(w http.ResponseWriter, r *http.Request) {
+	if h.cfg.Resolver == nil {
+		writeError(w, http.StatusNotImplemented, "resolver isn't available")
+		return
+	}
+
+	results := h.cfg.Resolver.ResolveAll(r.Context(), mux.Vars(r)["bucket"])
+	res := make([]ResolveResponse, len(results))
+	for i, result := range results {
+		res[i].Resolver = result.Resolver
+		if result.Err != nil {
+			res[i].Error = result.Err.Error()
+		} else {
+			res[i].ContainerID = result.CID.EncodeToString()
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogLevelRequest is a request and a response of the log level requests.
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// GetConfigHandler returns effective gateway settings. Secrets are redacted.
+func (h *Handler) GetConfigHandler(w http.ResponseWriter, _ *http.Request) {
+	if h.cfg.Settings == nil {
+		writeError(w, http.StatusNotImplemented, "settings aren't available")
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, h.cfg.Settings())
+}
+
+// GetLogLevelHandler returns the current log level.
+func (h *Handler) GetLogLevelHandler(w http.ResponseWriter, _ *http.Request) {
+	h.writeJSON(w, http.StatusOK, &LogLevelRequest{Level: h.cfg.LogLevel.Level().String()})
+}
+
+// PutLogLevelHandler changes the log level until the next configuration reload.
+func (h *Handler) PutLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	req := new(LogLevelRequest)
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid log level request: "+err.Error())
+		return
+	}
+
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(req.Level)); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid log level: "+req.Level)
+		return
+	}
+
+	h.cfg.LogLevel.SetLevel(lvl)
+	h.log.Info("log level changed", zap.Stringer("level", lvl))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+)
+
+// BucketUsageResponse is a response of the bucket usage requests.
+type BucketUsageResponse struct {
+	Size           uint64 `json:"size"`
+	Objects        uint64 `json:"objects"`
+	CurrentObjects uint64 `json:"current_objects"`
+	Versions       uint64 `json:"versions"`
+	DeleteMarkers  uint64 `json:"delete_markers"`
+	MultipartSize  uint64 `json:"multipart_size"`
+	// Approximate is set if the usage is updated incrementally since the last recalculation.
+	// Every gateway instance updates it without coordination with other instances,
+	// so it drifts if the bucket is modified through several instances simultaneously.
+	Approximate bool `json:"approximate"`
+}
+
+func newBucketUsageResponse(usage *data.BucketUsage, approximate bool) *BucketUsageResponse {
+	if usage == nil {
+		return nil
+	}
+
+	return &BucketUsageResponse{
+		Size:           usage.Size,
+		Objects:        usage.Objects,
+		CurrentObjects: usage.CurrentObjects,
+		Versions:       usage.Versions(),
+		DeleteMarkers:  usage.DeleteMarkers,
+		MultipartSize:  usage.MultipartSize,
+		Approximate:    approximate,
+	}
+}
+
+// GetBucketUsageHandler returns usage of the bucket with usage tracking.
+func (h *Handler) GetBucketUsageHandler(w http.ResponseWriter, r *http.Request) {
+	bktInfo, ok := h.getBucketInfo(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
+	if err != nil {
+		h.internalError(w, "couldn't get bucket settings", err)
+		return
+	}
+
+	if !h.obj.BucketUsageTracked(settings) {
+		writeError(w, http.StatusNotFound, "usage isn't tracked for the bucket")
+		return
+	}
+
+	usage, err := h.obj.GetBucketUsage(r.Context(), bktInfo)
+	if err != nil {
+		if errors.Is(err, layer.ErrNodeNotFound) {
+			writeError(w, http.StatusNotFound, "usage of the bucket isn't calculated yet")
+			return
+		}
+		h.internalError(w, "couldn't get bucket usage", err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, newBucketUsageResponse(usage, true))
+}
+
+// PutBucketUsageHandler enables usage tracking for the bucket. Usage is recalculated
+// from scratch, so the request can take a long time for big buckets.
+func (h *Handler) PutBucketUsageHandler(w http.ResponseWriter, r *http.Request) {
+	bktInfo, ok := h.getBucketInfo(w, r)
+	if !ok {
+		return
+	}
+
+	usage, err := h.obj.SetBucketUsageTracking(r.Context(), bktInfo, true)
+	if err != nil {
+		h.internalError(w, "couldn't enable usage tracking", err)
+		return
+	}
+
+	h.log.Info("bucket usage tracking enabled", zap.String("bucket", bktInfo.Name))
+	h.writeJSON(w, http.StatusOK, newBucketUsageResponse(usage, false))
+}
+
+// DeleteBucketUsageHandler disables usage tracking for the bucket.
+func (h *Handler) DeleteBucketUsageHandler(w http.ResponseWriter, r *http.Request) {
+	bktInfo, ok := h.getBucketInfo(w, r)
+	if !ok {
+		return
+	}
+
+	if _, err := h.obj.SetBucketUsageTracking(r.Context(), bktInfo, false); err != nil {
+		if errors.Is(err, layer.ErrUsageRequired) || errors.Is(err, layer.ErrUsageTrackedForAll) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		h.internalError(w, "couldn't disable usage tracking", err)
+		return
+	}
+
+	h.log.Info("bucket usage tracking disabled", zap.String("bucket", bktInfo.Name))
+	w.WriteHeader(http.StatusNoContent)
+}
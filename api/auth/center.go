@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/credentials"
@@ -21,6 +22,7 @@ import (
 	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/tracing"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 )
 
@@ -30,17 +32,36 @@ var authorizationFieldRegexp = regexp.MustCompile(`AWS4-HMAC-SHA256 Credential=(
 // postPolicyCredentialRegexp -- is regexp for credentials when uploading file using POST with policy.
 var postPolicyCredentialRegexp = regexp.MustCompile(`(?P<access_key_id>[^/]+)/(?P<date>[^/]+)/(?P<region>[^/]*)/(?P<service>[^/]+)/aws4_request`)
 
+// credentialRegexp -- is regexp for access key id of the credential in authorization header.
+var credentialRegexp = regexp.MustCompile(`Credential=([^/,\s]+)/`)
+
 type (
 	// Center is a user authentication interface.
 	Center interface {
 		Authenticate(request *http.Request) (*accessbox.Box, error)
+		// SetAllowedAccessKeyIDPrefixes replaces prefixes of the allowed access key ids.
+		SetAllowedAccessKeyIDPrefixes(prefixes []string)
+		// SetFormLimits replaces the max size of the file uploaded with POST form and the max size
+		// of the form kept in memory while it's parsed. The file size isn't limited if it's zero.
+		SetFormLimits(maxFileSize, maxMemory int64)
 	}
 
 	center struct {
-		reg                        *RegexpSubmatcher
-		postReg                    *RegexpSubmatcher
-		cli                        tokens.Credentials
+		reg     *RegexpSubmatcher
+		postReg *RegexpSubmatcher
+		cli     tokens.Credentials
+
+		mu                         sync.RWMutex
 		allowedAccessKeyIDPrefixes []string // empty slice means all access key ids are allowed
+		maxFormFileSize            int64
+		maxFormMemory              int64
+	}
+
+	// limitedForm fails reading of the POST form body exceeding the limit.
+	limitedForm struct {
+		io.ReadCloser
+		n        int64
+		exceeded bool
 	}
 
 	prs int
@@ -60,7 +81,12 @@ type (
 const (
 	accessKeyPartsNum  = 2
 	authHeaderPartsNum = 6
-	maxFormSizeMemory  = 50 * 1048576 // 50 MB
+
+	// DefaultMaxFormMemory is a default max size of POST form kept in memory while it's parsed,
+	// the rest of the form is stored in temporary files.
+	DefaultMaxFormMemory = 50 * 1048576 // 50 MB
+	// maxFormFieldsSize is a max size of POST form fields besides the file including headers of parts.
+	maxFormFieldsSize = 1048576 // 1 MB
 
 	AmzAlgorithm     = "X-Amz-Algorithm"
 	AmzCredential    = "X-Amz-Credential"
@@ -75,6 +101,8 @@ const (
 // ErrNoAuthorizationHeader is returned for unauthenticated requests.
 var ErrNoAuthorizationHeader = errors.New("no authorization header")
 
+var errFormTooLarge = errors.New("form is too large")
+
 func (p prs) Read(_ []byte) (n int, err error) {
 	panic("implement me")
 }
@@ -92,6 +120,7 @@ func New(neoFS tokens.NeoFS, key *keys.PrivateKey, prefixes []string, config *ca
 		reg:                        NewRegexpMatcher(authorizationFieldRegexp),
 		postReg:                    NewRegexpMatcher(postPolicyCredentialRegexp),
 		allowedAccessKeyIDPrefixes: prefixes,
+		maxFormMemory:              DefaultMaxFormMemory,
 	}
 }
 
@@ -157,7 +186,13 @@ func (c *center) Authenticate(r *http.Request) (*accessbox.Box, error) {
 		authHeaderField := r.Header[AuthorizationHdr]
 		if len(authHeaderField) != 1 {
 			if strings.HasPrefix(r.Header.Get(ContentTypeHdr), "multipart/form-data") {
-				return c.checkFormData(r)
+				box, err := c.checkFormData(r)
+				if err != ErrNoAuthorizationHeader {
+					return box, err
+				}
+			}
+			if accessKeyID := CertAccessKeyID(r.Context()); accessKeyID != "" {
+				return c.authenticateCert(r.Context(), accessKeyID)
 			}
 			return nil, ErrNoAuthorizationHeader
 		}
@@ -182,20 +217,74 @@ func (c *center) Authenticate(r *http.Request) (*accessbox.Box, error) {
 		return nil, err
 	}
 
-	box, err := c.cli.GetBox(r.Context(), addr)
+	box, err := c.getBox(r.Context(), addr, authHdr.AccessKeyID)
 	if err != nil {
-		return nil, fmt.Errorf("get box: %w", err)
+		return nil, err
 	}
 
 	clonedRequest := cloneRequest(r, authHdr)
-	if err = c.checkSign(authHdr, box, clonedRequest, signatureDateTime); err != nil {
+	_, span := tracing.StartSpan(r.Context(), "auth.CheckSignature", tracing.AttrAccessKeyID.String(authHdr.AccessKeyID))
+	err = c.checkSign(authHdr, box, clonedRequest, signatureDateTime)
+	tracing.EndSpan(span, err)
+	if err != nil {
 		return nil, err
 	}
 
 	return box, nil
 }
 
-func (c center) checkAccessKeyID(accessKeyID string) error {
+// SetAllowedAccessKeyIDPrefixes implements Center interface method.
+func (c *center) SetAllowedAccessKeyIDPrefixes(prefixes []string) {
+	c.mu.Lock()
+	c.allowedAccessKeyIDPrefixes = prefixes
+	c.mu.Unlock()
+}
+
+// SetFormLimits implements Center interface method.
+func (c *center) SetFormLimits(maxFileSize, maxMemory int64) {
+	c.mu.Lock()
+	c.maxFormFileSize = maxFileSize
+	c.maxFormMemory = maxMemory
+	c.mu.Unlock()
+}
+
+func (c *center) formLimits() (int64, int64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	maxMemory := c.maxFormMemory
+	if maxMemory <= 0 {
+		maxMemory = DefaultMaxFormMemory
+	}
+
+	if c.maxFormFileSize <= 0 {
+		return 0, maxMemory
+	}
+	return c.maxFormFileSize + maxFormFieldsSize, maxMemory
+}
+
+func (l *limitedForm) Read(p []byte) (int, error) {
+	if l.exceeded {
+		return 0, errFormTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.ReadCloser.Read(p)
+	if int64(n) > l.n {
+		l.exceeded = true
+		return int(l.n), errFormTooLarge
+	}
+	l.n -= int64(n)
+
+	return n, err
+}
+
+func (c *center) checkAccessKeyID(accessKeyID string) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	if len(c.allowedAccessKeyIDPrefixes) == 0 {
 		return nil
 	}
@@ -209,8 +298,34 @@ func (c center) checkAccessKeyID(accessKeyID string) error {
 	return apiErrors.GetAPIError(apiErrors.ErrAccessDenied)
 }
 
+// getBox fetches the access box of the access key from the credentials cache or NeoFS.
+func (c *center) getBox(ctx context.Context, addr oid.Address, accessKeyID string) (*accessbox.Box, error) {
+	ctx, span := tracing.StartSpan(ctx, "auth.GetBox", tracing.AttrAccessKeyID.String(accessKeyID))
+	box, err := c.cli.GetBox(ctx, addr)
+	if err != nil {
+		err = fmt.Errorf("get box: %w", err)
+	}
+	tracing.EndSpan(span, err)
+
+	return box, err
+}
+
 func (c *center) checkFormData(r *http.Request) (*accessbox.Box, error) {
-	if err := r.ParseMultipartForm(maxFormSizeMemory); err != nil {
+	// the form is limited before it's parsed, since the file is spooled to the disk by the parser
+	maxSize, maxMemory := c.formLimits()
+	var body *limitedForm
+	if maxSize > 0 {
+		if r.ContentLength > maxSize {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrEntityTooLarge)
+		}
+		body = &limitedForm{ReadCloser: r.Body, n: maxSize}
+		r.Body = body
+	}
+
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		if body != nil && body.exceeded {
+			return nil, apiErrors.GetAPIError(apiErrors.ErrEntityTooLarge)
+		}
 		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidArgument)
 	}
 
@@ -238,9 +353,9 @@ func (c *center) checkFormData(r *http.Request) (*accessbox.Box, error) {
 		return nil, apiErrors.GetAPIError(apiErrors.ErrInvalidAccessKeyID)
 	}
 
-	box, err := c.cli.GetBox(r.Context(), addr)
+	box, err := c.getBox(r.Context(), addr, submatches["access_key_id"])
 	if err != nil {
-		return nil, fmt.Errorf("get box: %w", err)
+		return nil, err
 	}
 
 	secret := box.Gate.AccessKey
@@ -326,6 +441,26 @@ func hmacSHA256(key []byte, data []byte) []byte {
 	return hash.Sum(nil)
 }
 
+// AccessKeyID returns the access key id of the credentials the request is signed with,
+// the access key id its client certificate is mapped to or an empty string if the request
+// isn't authenticated. Credentials of the multipart form are available only after the form
+// is parsed by Authenticate.
+func AccessKeyID(r *http.Request) string {
+	if credential := r.URL.Query().Get(AmzCredential); credential != "" {
+		return strings.SplitN(credential, "/", 2)[0]
+	}
+
+	if match := credentialRegexp.FindStringSubmatch(r.Header.Get(AuthorizationHdr)); match != nil {
+		return match[1]
+	}
+
+	if credential := MultipartFormValue(r, "x-amz-credential"); credential != "" {
+		return strings.SplitN(credential, "/", 2)[0]
+	}
+
+	return CertAccessKeyID(r.Context())
+}
+
 // MultipartFormValue gets value by key from multipart form.
 func MultipartFormValue(r *http.Request, key string) string {
 	if r.MultipartForm == nil {
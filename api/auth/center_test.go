@@ -1,11 +1,22 @@
 package auth
 
 import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"github.com/stretchr/testify/require"
 )
 
@@ -50,6 +61,23 @@ func TestAuthHeaderParse(t *testing.T) {
 	}
 }
 
+func TestAccessKeyID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/bucket/object", nil)
+	require.Empty(t, AccessKeyID(r))
+
+	r.Header.Set(AuthorizationHdr, "AWS4-HMAC-SHA256 Credential=oid0cid/20210809/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=2811ccb9e242f41426738fb1f")
+	require.Equal(t, "oid0cid", AccessKeyID(r))
+
+	r = httptest.NewRequest(http.MethodGet, "/bucket/object?X-Amz-Credential=oid1cid%2F20210809%2Fus-east-1%2Fs3%2Faws4_request", nil)
+	require.Equal(t, "oid1cid", AccessKeyID(r))
+
+	r = httptest.NewRequest(http.MethodPost, "/bucket", nil)
+	r.MultipartForm = &multipart.Form{Value: map[string][]string{
+		"x-amz-credential": {"oid2cid/20210809/us-east-1/s3/aws4_request"},
+	}}
+	require.Equal(t, "oid2cid", AccessKeyID(r))
+}
+
 func TestAuthHeaderGetAddress(t *testing.T) {
 	defaulErr := errors.GetAPIError(errors.ErrInvalidAccessKeyID)
 
@@ -99,3 +127,82 @@ func TestSignature(t *testing.T) {
 	signature := signStr(secret, "s3", "us-east-1", signTime, strToSign)
 	require.Equal(t, "dfbe886241d9e369cf4b329ca0f15eb27306c97aa1022cc0bb5a914c4ef87634", signature)
 }
+
+type credentialsMock struct {
+	boxes map[oid.Address]*accessbox.Box
+}
+
+func (c credentialsMock) GetBox(_ context.Context, addr oid.Address) (*accessbox.Box, error) {
+	box, ok := c.boxes[addr]
+	if !ok {
+		return nil, errors.GetAPIError(errors.ErrNoSuchKey)
+	}
+	return box, nil
+}
+
+func (c credentialsMock) Put(context.Context, cid.ID, user.ID, *accessbox.AccessBox, uint64, ...*keys.PublicKey) (oid.Address, error) {
+	return oid.Address{}, nil
+}
+
+func TestAuthenticateCert(t *testing.T) {
+	addr := oidtest.Address()
+	accessKeyID := strings.ReplaceAll(addr.EncodeToString(), "/", "0")
+	box := &accessbox.Box{Gate: &accessbox.GateData{AccessKey: "secret"}}
+
+	c := &center{
+		reg: NewRegexpMatcher(authorizationFieldRegexp),
+		cli: credentialsMock{boxes: map[oid.Address]*accessbox.Box{addr: box}},
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/bucket/object", nil)
+	_, err := c.Authenticate(r)
+	require.ErrorIs(t, err, ErrNoAuthorizationHeader)
+
+	r = r.WithContext(ContextWithCertAccessKeyID(r.Context(), accessKeyID))
+	require.Equal(t, accessKeyID, AccessKeyID(r))
+	res, err := c.Authenticate(r)
+	require.NoError(t, err)
+	require.Equal(t, box, res)
+
+	c.SetAllowedAccessKeyIDPrefixes([]string{"other"})
+	_, err = c.Authenticate(r)
+	require.Equal(t, errors.GetAPIError(errors.ErrAccessDenied), err)
+
+	// signed requests are checked with the signature
+	r.Header.Set(AuthorizationHdr, "AWS4-HMAC-SHA256 Credential=oid0cid/20210809/us-east-1/s3/aws4_request, SignedHeaders=host, Signature=2811ccb9e242f41426738fb1f")
+	require.Equal(t, "oid0cid", AccessKeyID(r))
+}
+
+func TestCheckFormDataLimits(t *testing.T) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", "object")
+	require.NoError(t, err)
+	_, err = file.Write(make([]byte, 2*maxFormFieldsSize))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	newRequest := func(contentLength int64) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/bucket", bytes.NewReader(body.Bytes()))
+		r.Header.Set(ContentTypeHdr, form.FormDataContentType())
+		r.ContentLength = contentLength
+		return r
+	}
+
+	c := &center{postReg: NewRegexpMatcher(postPolicyCredentialRegexp)}
+	c.SetFormLimits(maxFormFieldsSize, 1024)
+
+	// the form is rejected before it's read if its size is known
+	_, err = c.checkFormData(newRequest(int64(body.Len())))
+	require.Equal(t, errors.GetAPIError(errors.ErrEntityTooLarge), err)
+
+	r := newRequest(-1)
+	_, err = c.checkFormData(r)
+	require.Equal(t, errors.GetAPIError(errors.ErrEntityTooLarge), err)
+	require.Nil(t, r.MultipartForm)
+
+	// the file fits the limit
+	c.SetFormLimits(2*maxFormFieldsSize, 1024)
+	_, err = c.checkFormData(newRequest(-1))
+	require.ErrorIs(t, err, ErrNoAuthorizationHeader)
+}
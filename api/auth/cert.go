@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+)
+
+type certAccessKeyIDKey struct{}
+
+// ContextWithCertAccessKeyID returns a copy of ctx with the access key id the verified client
+// certificate of the request is mapped to. Authenticate uses it for requests which aren't signed.
+func ContextWithCertAccessKeyID(ctx context.Context, accessKeyID string) context.Context {
+	return context.WithValue(ctx, certAccessKeyIDKey{}, accessKeyID)
+}
+
+// CertAccessKeyID returns the access key id set by ContextWithCertAccessKeyID or an empty string.
+func CertAccessKeyID(ctx context.Context) string {
+	accessKeyID, _ := ctx.Value(certAccessKeyIDKey{}).(string)
+	return accessKeyID
+}
+
+// CertPublicKeyHash returns the hex encoded SHA-256 hash of the certificate subject public key info
+// which client certificates are identified with along with the subject.
+func CertPublicKeyHash(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(hash[:])
+}
+
+// authenticateCert returns the access box of the access key id the client certificate is mapped to.
+// The certificate is verified by TLS, so the request signature isn't checked.
+func (c *center) authenticateCert(ctx context.Context, accessKeyID string) (*accessbox.Box, error) {
+	if err := c.checkAccessKeyID(accessKeyID); err != nil {
+		return nil, err
+	}
+
+	authHdr := &authHeader{AccessKeyID: accessKeyID}
+	addr, err := authHdr.getAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.getBox(ctx, addr, accessKeyID)
+}
@@ -0,0 +1,76 @@
+package cache
+
+import "sync"
+
+// InvalidationKind defines which cache entries are evicted by the invalidation event.
+type InvalidationKind string
+
+const (
+	// InvalidateBucket evicts bucket info by the bucket name.
+	InvalidateBucket InvalidationKind = "bucket"
+	// InvalidateList evicts lists of objects of the container which contain the object key.
+	InvalidateList InvalidationKind = "list"
+	// InvalidateName evicts the latest object address of the key and lists containing the key.
+	InvalidateName InvalidationKind = "name"
+	// InvalidateObject evicts the object header by the object address.
+	InvalidateObject InvalidationKind = "object"
+	// InvalidateSystem evicts the system cache entry (bucket settings, CORS, tags, locks etc.) by its key.
+	InvalidateSystem InvalidationKind = "system"
+)
+
+type (
+	// InvalidationEvent describes cache entries changed by the gateway instance.
+	InvalidationEvent struct {
+		// Source is an identifier of the gateway instance which published the event.
+		Source string           `json:"source"`
+		Kind   InvalidationKind `json:"kind"`
+		Bucket string           `json:"bucket,omitempty"`
+		CID    string           `json:"cid,omitempty"`
+		// Key is an object key, an object address or a system cache key depending on the event kind.
+		Key string `json:"key,omitempty"`
+	}
+
+	// InvalidationHandler is called for every event received from the invalidation bus.
+	InvalidationHandler func(InvalidationEvent)
+
+	// InvalidationBus delivers cache invalidation events between gateway instances.
+	// Events are delivered to all subscribers including the publisher.
+	InvalidationBus interface {
+		Publish(InvalidationEvent) error
+		Subscribe(InvalidationHandler) error
+	}
+
+	// MemoryBus is an in-memory InvalidationBus which delivers events synchronously.
+	// It's used to connect several caches within one process, e.g. in tests.
+	MemoryBus struct {
+		mu       sync.RWMutex
+		handlers []InvalidationHandler
+	}
+)
+
+// NewMemoryBus creates an object of MemoryBus.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{}
+}
+
+// Publish calls all subscribed handlers with the event.
+func (b *MemoryBus) Publish(e InvalidationEvent) error {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(e)
+	}
+
+	return nil
+}
+
+// Subscribe adds a handler of the published events.
+func (b *MemoryBus) Subscribe(h InvalidationHandler) error {
+	b.mu.Lock()
+	b.handlers = append(b.handlers, h)
+	b.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package cache
+
+import "github.com/bluele/gcache"
+
+// Stats contains statistics of the cache.
+type Stats struct {
+	// Size is the number of not expired entries.
+	Size   int
+	Hits   uint64
+	Misses uint64
+}
+
+func newStats(c gcache.Cache) Stats {
+	return Stats{
+		Size:   c.Len(true),
+		Hits:   c.HitCount(),
+		Misses: c.MissCount(),
+	}
+}
+
+// Stats returns statistics of the cache.
+func (o *ObjectsCache) Stats() Stats {
+	return newStats(o.cache)
+}
+
+// Purge removes all entries from the cache.
+func (o *ObjectsCache) Purge() {
+	o.cache.Purge()
+}
+
+// Stats returns statistics of the cache.
+func (l *ObjectsListCache) Stats() Stats {
+	return newStats(l.cache)
+}
+
+// Purge removes all entries from the cache.
+func (l *ObjectsListCache) Purge() {
+	l.cache.Purge()
+}
+
+// Stats returns statistics of the cache.
+func (o *ObjectsNameCache) Stats() Stats {
+	return newStats(o.cache)
+}
+
+// Purge removes all entries from the cache.
+func (o *ObjectsNameCache) Purge() {
+	o.cache.Purge()
+}
+
+// Stats returns statistics of the cache.
+func (o *BucketCache) Stats() Stats {
+	return newStats(o.cache)
+}
+
+// Purge removes all entries from the cache.
+func (o *BucketCache) Purge() {
+	o.cache.Purge()
+}
+
+// Stats returns statistics of the cache.
+func (o *SystemCache) Stats() Stats {
+	return newStats(o.cache)
+}
+
+// Purge removes all entries from the cache.
+func (o *SystemCache) Purge() {
+	o.cache.Purge()
+}
+
+// Stats returns statistics of the cache.
+func (o *AccessControlCache) Stats() Stats {
+	return newStats(o.cache)
+}
+
+// Purge removes all entries from the cache.
+func (o *AccessControlCache) Purge() {
+	o.cache.Purge()
+}
+
+// Stats returns statistics of the cache.
+func (o *AccessBoxCache) Stats() Stats {
+	return newStats(o.cache)
+}
+
+// Purge removes all entries from the cache.
+func (o *AccessBoxCache) Purge() {
+	o.cache.Purge()
+}
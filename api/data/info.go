@@ -59,6 +59,28 @@ type (
 	BucketSettings struct {
 		Versioning        string                   `json:"versioning"`
 		LockConfiguration *ObjectLockConfiguration `json:"lock_configuration"`
+		Quota             *BucketQuota             `json:"quota"`
+		TrackUsage        bool                     `json:"track_usage"`
+	}
+
+	// BucketQuota stores limits of the bucket usage, zero value means no limit.
+	// Writes that exceed hard limits are rejected, exceeding of soft limits is only logged.
+	BucketQuota struct {
+		MaxSize        uint64 `json:"max_size"`
+		MaxObjects     uint64 `json:"max_objects"`
+		SoftMaxSize    uint64 `json:"soft_max_size"`
+		SoftMaxObjects uint64 `json:"soft_max_objects"`
+	}
+
+	// BucketUsage stores statistics of a bucket. Size and Objects count all versions
+	// except delete markers, CurrentObjects counts latest versions that aren't delete markers.
+	// MultipartSize is a total size of parts of in-progress multipart uploads.
+	BucketUsage struct {
+		Size           uint64 `json:"size"`
+		Objects        uint64 `json:"objects"`
+		CurrentObjects uint64 `json:"current_objects"`
+		DeleteMarkers  uint64 `json:"delete_markers"`
+		MultipartSize  uint64 `json:"multipart_size"`
 	}
 
 	// CORSConfiguration stores CORS configuration of a request.
@@ -124,3 +146,13 @@ func (b BucketSettings) VersioningEnabled() bool {
 func (b BucketSettings) VersioningSuspended() bool {
 	return b.Versioning == VersioningSuspended
 }
+
+// UsageTracked checks if the bucket usage is maintained in the tree service.
+func (b BucketSettings) UsageTracked() bool {
+	return b.Quota != nil || b.TrackUsage
+}
+
+// Versions returns number of all versions in the bucket including delete markers.
+func (u BucketUsage) Versions() uint64 {
+	return u.Objects + u.DeleteMarkers
+}
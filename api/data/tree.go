@@ -18,6 +18,7 @@ type NodeVersion struct {
 	BaseNodeVersion
 	DeleteMarker  *DeleteMarkerInfo
 	IsUnversioned bool
+	StorageClass  string
 }
 
 func (v NodeVersion) IsDeleteMarker() bool {
@@ -28,6 +28,7 @@ const (
 	ErrEntityTooSmall
 	ErrEntityTooLarge
 	ErrPolicyTooLarge
+	ErrMaxMessageLengthExceeded
 	ErrIllegalVersioningConfigurationException
 	ErrIncompleteBody
 	ErrInternalError
@@ -177,6 +178,7 @@ const (
 	ErrOperationMaxedOut
 	ErrInvalidRequest
 	ErrInvalidStorageClass
+	ErrQuotaExceeded
 
 	ErrMalformedJSON
 	ErrInsecureClientRequest
@@ -386,6 +388,12 @@ var errorCodes = errorCodeMap{
 		Description:    "Policy exceeds the maximum allowed document size.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrMaxMessageLengthExceeded: {
+		ErrCode:        ErrMaxMessageLengthExceeded,
+		Code:           "MaxMessageLengthExceeded",
+		Description:    "Your request was too big.",
+		HTTPStatusCode: http.StatusBadRequest,
+	},
 	ErrIllegalVersioningConfigurationException: {
 		ErrCode:        ErrIllegalVersioningConfigurationException,
 		Code:           "IllegalVersioningConfigurationException",
@@ -973,7 +981,7 @@ var errorCodes = errorCodeMap{
 	},
 	ErrMetadataTooLarge: {
 		ErrCode:        ErrMetadataTooLarge,
-		Code:           "InvalidArgument",
+		Code:           "MetadataTooLarge",
 		Description:    "Your metadata headers exceed the maximum allowed metadata size.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
@@ -1099,6 +1107,12 @@ var errorCodes = errorCodeMap{
 		Description:    "Object name contains unsupported characters.",
 		HTTPStatusCode: http.StatusBadRequest,
 	},
+	ErrQuotaExceeded: {
+		ErrCode:        ErrQuotaExceeded,
+		Code:           "QuotaExceeded",
+		Description:    "Bucket quota exceeded.",
+		HTTPStatusCode: http.StatusForbidden,
+	},
 	ErrMalformedJSON: {
 		ErrCode:        ErrMalformedJSON,
 		Code:           "MalformedJSON",
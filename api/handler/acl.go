@@ -6,7 +6,6 @@ import (
 	"crypto/elliptic"
 	"encoding/hex"
 	"encoding/json"
-	"encoding/xml"
 	stderrors "errors"
 	"fmt"
 	"net/http"
@@ -217,8 +216,8 @@ func (h *handler) PutBucketACLHandler(w http.ResponseWriter, r *http.Request) {
 			h.logAndSendError(w, "could not parse bucket acl", reqInfo, err)
 			return
 		}
-	} else if err = xml.NewDecoder(r.Body).Decode(list); err != nil {
-		h.logAndSendError(w, "could not parse bucket acl", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+	} else if err = h.decodeXML(r, list, errors.ErrMalformedXML); err != nil {
+		h.logAndSendError(w, "could not parse bucket acl", reqInfo, err)
 		return
 	}
 
@@ -277,6 +276,8 @@ func (h *handler) updateBucketACL(r *http.Request, astChild *ast, bktInfo *data.
 		return false, fmt.Errorf("could not put bucket acl: %w", err)
 	}
 
+	h.audit(r, eaclAuditInfo(bucketACL.EACL), eaclAuditInfo(table))
+
 	return true, nil
 }
 
@@ -352,8 +353,8 @@ func (h *handler) PutObjectACLHandler(w http.ResponseWriter, r *http.Request) {
 			h.logAndSendError(w, "could not parse bucket acl", reqInfo, err)
 			return
 		}
-	} else if err = xml.NewDecoder(r.Body).Decode(list); err != nil {
-		h.logAndSendError(w, "could not parse bucket acl", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+	} else if err = h.decodeXML(r, list, errors.ErrMalformedXML); err != nil {
+		h.logAndSendError(w, "could not parse bucket acl", reqInfo, err)
 		return
 	}
 
@@ -442,7 +443,8 @@ func (h *handler) PutBucketPolicyHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	bktPolicy := &bucketPolicy{Bucket: reqInfo.BucketName}
-	if err = json.NewDecoder(r.Body).Decode(bktPolicy); err != nil {
+	body := limitBody(r, h.config().Limits.MaxPolicySize, errors.ErrPolicyTooLarge)
+	if err = json.NewDecoder(body).Decode(bktPolicy); err != nil {
 		h.logAndSendError(w, "could not parse bucket policy", reqInfo, err)
 		return
 	}
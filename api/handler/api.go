@@ -2,9 +2,11 @@ package handler
 
 import (
 	"errors"
+	"sync"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/audit"
 	"github.com/nspcc-dev/neofs-sdk-go/netmap"
 	"go.uber.org/zap"
 )
@@ -14,7 +16,10 @@ type (
 		log         *zap.Logger
 		obj         layer.Client
 		notificator Notificator
-		cfg         *Config
+		auditor     Auditor
+
+		mu  sync.RWMutex
+		cfg *Config
 	}
 
 	Notificator interface {
@@ -22,6 +27,12 @@ type (
 		SendTestNotification(topic, bucketName, requestID, HostID string) error
 	}
 
+	// Auditor records changes of bucket and object settings.
+	Auditor interface {
+		Enabled() bool
+		Audit(*audit.Event)
+	}
+
 	// Config contains data which handler needs to keep.
 	Config struct {
 		DefaultPolicy      netmap.PlacementPolicy
@@ -29,6 +40,27 @@ type (
 		NotificatorEnabled bool
 		TLSEnabled         bool
 		CopiesNumber       uint32
+		StorageClasses     map[string]uint32
+		Limits             Limits
+	}
+
+	// Limits contains limits of request sizes. Requests exceeding them are rejected
+	// with the corresponding S3 errors.
+	Limits struct {
+		// MaxBodySize is a max size of XML bodies of configuration, delete and complete
+		// multipart upload requests.
+		MaxBodySize int64
+		// MaxPolicySize is a max size of JSON bucket policy.
+		MaxPolicySize int64
+		// MaxPutSize is a max size of an object uploaded with a single PUT or POST request
+		// and of an uploaded part.
+		MaxPutSize int64
+		// MaxMetadataSize is a max total size of user metadata keys and values.
+		MaxMetadataSize int
+		// MaxDeleteObjects is a max number of keys deleted with a single request.
+		MaxDeleteObjects int
+		// MaxParts is a max number of parts of multipart upload.
+		MaxParts int
 	}
 )
 
@@ -37,12 +69,15 @@ const (
 	DefaultPolicy = "REP 3"
 	// DefaultCopiesNumber is a default number of object copies that is enough to consider put successful if it's not set in config.
 	DefaultCopiesNumber uint32 = 0
+	// StandardStorageClass is a storage class of objects which are stored according to the default settings.
+	StandardStorageClass = "STANDARD"
 )
 
 var _ api.Handler = (*handler)(nil)
 
-// New creates new api.Handler using given logger and client.
-func New(log *zap.Logger, obj layer.Client, notificator Notificator, cfg *Config) (api.Handler, error) {
+// New creates new api.Handler using given logger and client. Auditor can be nil,
+// the changes of settings aren't recorded then.
+func New(log *zap.Logger, obj layer.Client, notificator Notificator, auditor Auditor, cfg *Config) (api.Handler, error) {
 	switch {
 	case obj == nil:
 		return nil, errors.New("empty NeoFS Object Layer")
@@ -61,5 +96,22 @@ func New(log *zap.Logger, obj layer.Client, notificator Notificator, cfg *Config
 		obj:         obj,
 		cfg:         cfg,
 		notificator: notificator,
+		auditor:     auditor,
 	}, nil
 }
+
+// UpdateConfig replaces the handler config. Requests in progress keep using the previous one.
+// NotificatorEnabled can't be changed since the notificator is set at the handler creation.
+func (h *handler) UpdateConfig(cfg *Config) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cfg.NotificatorEnabled = h.cfg.NotificatorEnabled
+	h.cfg = cfg
+}
+
+func (h *handler) config() *Config {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.cfg
+}
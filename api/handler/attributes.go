@@ -186,7 +186,7 @@ func encodeToObjectAttributesResponse(info *data.ObjectInfo, p *GetObjectAttribu
 		case eTag:
 			resp.ETag = info.HashSum
 		case storageClass:
-			resp.StorageClass = "STANDARD"
+			resp.StorageClass = objectStorageClass(info)
 		case objectSize:
 			resp.ObjectSize = info.Size
 		case checksum:
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"crypto/elliptic"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	v2acl "github.com/nspcc-dev/neofs-api-go/v2/acl"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/audit"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+)
+
+// bucketAuditInfo describes the created or deleted bucket in audit events.
+type bucketAuditInfo struct {
+	ContainerID        string               `json:"container_id"`
+	LocationConstraint string               `json:"location_constraint,omitempty"`
+	ObjectLockEnabled  bool                 `json:"object_lock_enabled,omitempty"`
+	ACL                *AccessControlPolicy `json:"acl,omitempty"`
+}
+
+// eaclAuditInfo returns the eACL table in NeoFS JSON format to be recorded in audit events.
+func eaclAuditInfo(table *eacl.Table) interface{} {
+	if table == nil {
+		return nil
+	}
+
+	data, err := table.MarshalJSON()
+	if err != nil {
+		return nil
+	}
+
+	return json.RawMessage(data)
+}
+
+// auditEnabled checks if the changes of settings are recorded. Handlers fetch the settings
+// before the change only if it's true.
+func (h *handler) auditEnabled() bool {
+	return h.auditor != nil && h.auditor.Enabled()
+}
+
+// audit records the successful change of bucket or object settings made by the request.
+func (h *handler) audit(r *http.Request, before, after interface{}) {
+	if !h.auditEnabled() {
+		return
+	}
+
+	reqInfo := api.GetReqInfo(r.Context())
+	e := &audit.Event{
+		Time:        time.Now().UTC(),
+		RequestID:   reqInfo.RequestID,
+		Action:      reqInfo.API,
+		Bucket:      reqInfo.BucketName,
+		Object:      reqInfo.ObjectName,
+		AccessKeyID: reqInfo.AccessKeyID,
+		RemoteIP:    reqInfo.RemoteHost,
+		Before:      before,
+		After:       after,
+	}
+	if reqInfo.URL != nil {
+		e.VersionID = reqInfo.URL.Query().Get(api.QueryVersionID)
+	}
+
+	if box, err := layer.GetBoxData(r.Context()); err == nil && box.Gate.BearerToken != nil {
+		e.Owner = bearer.ResolveIssuer(*box.Gate.BearerToken).EncodeToString()
+
+		var btoken v2acl.BearerToken
+		box.Gate.BearerToken.WriteToV2(&btoken)
+		if key, err := keys.NewPublicKeyFromBytes(btoken.GetSignature().GetKey(), elliptic.P256()); err == nil {
+			e.PublicKey = hex.EncodeToString(key.Bytes())
+		}
+	}
+
+	h.auditor.Audit(e)
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/audit"
+	"github.com/stretchr/testify/require"
+)
+
+type auditorMock struct {
+	enabled bool
+	events  []*audit.Event
+}
+
+func (a *auditorMock) Enabled() bool {
+	return a.enabled
+}
+
+func (a *auditorMock) Audit(e *audit.Event) {
+	a.events = append(a.events, e)
+}
+
+func TestAudit(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	auditor := &auditorMock{}
+	hc.h.auditor = auditor
+
+	bktName, objName := "bucket-for-audit", "object-for-audit"
+	bktInfo := createTestBucketWithLock(hc, bktName, nil)
+	createTestObject(hc, bktInfo, objName)
+
+	putBucketVersioning(t, hc, bktName, true)
+	require.Empty(t, auditor.events)
+
+	auditor.enabled = true
+
+	putBucketVersioning(t, hc, bktName, true)
+	require.Len(t, auditor.events, 1)
+	e := auditor.events[0]
+	require.Equal(t, bktName, e.Bucket)
+	require.Equal(t, hc.owner.EncodeToString(), e.Owner)
+
+	key, err := hc.h.bearerTokenIssuerKey(hc.Context())
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(key.Bytes()), e.PublicKey)
+
+	require.Equal(t, data.VersioningEnabled, e.Before.(*VersioningConfiguration).Status)
+	require.Equal(t, data.VersioningEnabled, e.After.(*VersioningConfiguration).Status)
+
+	putObjectLegalHold(hc, bktName, objName, legalHoldOn)
+	require.Len(t, auditor.events, 2)
+	e = auditor.events[1]
+	require.Equal(t, objName, e.Object)
+	require.Equal(t, legalHoldOff, e.Before.(*data.LegalHold).Status)
+	require.Equal(t, legalHoldOn, e.After.(*data.LegalHold).Status)
+
+	// failed changes aren't recorded
+	w, r := prepareTestRequest(hc, bktName, objName, &data.LegalHold{Status: "invalid"})
+	hc.Handler().PutObjectLegalHoldHandler(w, r)
+	require.Len(t, auditor.events, 2)
+}
+
+func TestAuditPutObjectACL(t *testing.T) {
+	hc := prepareHandlerContext(t)
+	auditor := &auditorMock{enabled: true}
+	hc.h.auditor = auditor
+
+	bktName, objName := "bucket-for-audit", "object-for-audit"
+	box := createAccessBox(t)
+	createBucket(t, hc, bktName, box)
+	events := len(auditor.events)
+
+	w, r := prepareTestPayloadRequest(hc, bktName, objName, bytes.NewReader([]byte("content")))
+	r.Header.Set(api.AmzACL, "public-read")
+	r = r.WithContext(context.WithValue(r.Context(), api.BoxData, box))
+	hc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	// the object ACL is stored in the bucket eACL, so the change is recorded as well
+	require.Len(t, auditor.events, events+1)
+	e := auditor.events[events]
+	require.Equal(t, bktName, e.Bucket)
+	require.Equal(t, objName, e.Object)
+	require.NotNil(t, e.Before)
+	require.NotNil(t, e.After)
+	require.NotEqual(t, e.Before, e.After)
+}
@@ -19,6 +19,7 @@ type copyObjectArgs struct {
 	Conditional       *conditionalArgs
 	MetadataDirective string
 	TaggingDirective  string
+	StorageClass      string
 }
 
 const (
@@ -117,6 +118,10 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 
 	if args.MetadataDirective == replaceDirective {
 		metadata = parseMetadata(r)
+		if err = checkMetadataSize(metadata, h.config().Limits.MaxMetadataSize); err != nil {
+			h.logAndSendError(w, "invalid metadata", reqInfo, err)
+			return
+		}
 	}
 
 	if args.TaggingDirective == replaceDirective {
@@ -159,29 +164,36 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if metadata == nil {
+		// copy headers to avoid modification of cached source object info
+		metadata = make(map[string]string, len(srcObjInfo.Headers)+1)
+		for key, val := range srcObjInfo.Headers {
+			metadata[key] = val
+		}
 		if len(srcObjInfo.ContentType) > 0 {
-			srcObjInfo.Headers[api.ContentType] = srcObjInfo.ContentType
+			metadata[api.ContentType] = srcObjInfo.ContentType
 		}
-		metadata = srcObjInfo.Headers
 	} else if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
 		metadata[api.ContentType] = contentType
 	}
 
-	copiesNumber, err := getCopiesNumberOrDefault(metadata, h.cfg.CopiesNumber)
+	storageClass, copiesNumber, err := h.getStorageClassAndCopiesNumber(r.Header, metadata)
 	if err != nil {
-		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		h.logAndSendError(w, "invalid storage class or copies number", reqInfo, err)
 		return
 	}
 
 	params := &layer.CopyObjectParams{
-		SrcObject:   srcObjInfo,
-		ScrBktInfo:  srcObjPrm.BktInfo,
-		DstBktInfo:  dstBktInfo,
-		DstObject:   reqInfo.ObjectName,
-		SrcSize:     srcObjInfo.Size,
-		Header:      metadata,
-		Encryption:  encryptionParams,
-		CopiesNuber: copiesNumber,
+		SrcObject:    srcObjInfo,
+		ScrBktInfo:   srcObjPrm.BktInfo,
+		DstBktInfo:   dstBktInfo,
+		DstObject:    reqInfo.ObjectName,
+		SrcSize:      srcObjInfo.Size,
+		Header:       metadata,
+		Encryption:   encryptionParams,
+		CopiesNuber:  copiesNumber,
+		StorageClass: storageClass,
+		Conditions:   parsePutConditionalHeaders(r.Header),
+		TagSet:       tagSet,
 	}
 
 	params.Lock, err = formObjectLock(dstBktInfo, settings.LockConfiguration, r.Header)
@@ -204,7 +216,7 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if containsACL {
-		newEaclTable, err := h.getNewEAclTable(r, dstBktInfo, dstObjInfo)
+		newEaclTable, prevEaclTable, err := h.getNewEAclTable(r, dstBktInfo, dstObjInfo)
 		if err != nil {
 			h.logAndSendError(w, "could not get new eacl table", reqInfo, err)
 			return
@@ -220,22 +232,8 @@ func (h *handler) CopyObjectHandler(w http.ResponseWriter, r *http.Request) {
 			h.logAndSendError(w, "could not put bucket acl", reqInfo, err)
 			return
 		}
-	}
 
-	if tagSet != nil {
-		tagPrm := &layer.PutObjectTaggingParams{
-			ObjectVersion: &layer.ObjectVersion{
-				BktInfo:    dstBktInfo,
-				ObjectName: reqInfo.ObjectName,
-				VersionID:  dstObjInfo.VersionID(),
-			},
-			TagSet:      tagSet,
-			NodeVersion: extendedDstObjInfo.NodeVersion,
-		}
-		if _, err = h.obj.PutObjectTagging(r.Context(), tagPrm); err != nil {
-			h.logAndSendError(w, "could not upload object tagging", reqInfo, err)
-			return
-		}
+		h.audit(r, eaclAuditInfo(prevEaclTable), eaclAuditInfo(newEaclTable))
 	}
 
 	h.log.Info("object is copied",
@@ -267,7 +265,7 @@ func isCopyingToItselfForbidden(reqInfo *api.ReqInfo, srcBucket string, srcObjec
 		return false
 	}
 
-	return args.MetadataDirective != replaceDirective
+	return args.MetadataDirective != replaceDirective && len(args.StorageClass) == 0
 }
 
 func parseCopyObjectArgs(headers http.Header) (*copyObjectArgs, error) {
@@ -284,7 +282,10 @@ func parseCopyObjectArgs(headers http.Header) (*copyObjectArgs, error) {
 		return nil, err
 	}
 
-	copyArgs := &copyObjectArgs{Conditional: args}
+	copyArgs := &copyObjectArgs{
+		Conditional:  args,
+		StorageClass: headers.Get(api.AmzStorageClass),
+	}
 
 	copyArgs.MetadataDirective = headers.Get(api.AmzMetadataDirective)
 	if !isValidDirective(copyArgs.MetadataDirective) {
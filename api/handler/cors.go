@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	"go.uber.org/zap"
@@ -47,10 +48,15 @@ func (h *handler) PutBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var before *data.CORSConfiguration
+	if h.auditEnabled() {
+		before, _ = h.obj.GetBucketCORS(r.Context(), bktInfo)
+	}
+
 	p := &layer.PutCORSParams{
 		BktInfo:      bktInfo,
-		Reader:       r.Body,
-		CopiesNumber: h.cfg.CopiesNumber,
+		Reader:       limitBody(r, h.config().Limits.MaxBodySize, errors.ErrMaxMessageLengthExceeded),
+		CopiesNumber: h.config().CopiesNumber,
 	}
 
 	if err = h.obj.PutBucketCORS(r.Context(), p); err != nil {
@@ -58,6 +64,11 @@ func (h *handler) PutBucketCorsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if h.auditEnabled() {
+		after, _ := h.obj.GetBucketCORS(r.Context(), bktInfo)
+		h.audit(r, before, after)
+	}
+
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
@@ -70,10 +81,18 @@ func (h *handler) DeleteBucketCorsHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	var before *data.CORSConfiguration
+	if h.auditEnabled() {
+		before, _ = h.obj.GetBucketCORS(r.Context(), bktInfo)
+	}
+
 	if err = h.obj.DeleteBucketCORS(r.Context(), bktInfo); err != nil {
 		h.logAndSendError(w, "could not delete cors", reqInfo, err)
+		return
 	}
 
+	h.audit(r, before, nil)
+
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -185,7 +204,7 @@ func (h *handler) Preflight(w http.ResponseWriter, r *http.Request) {
 						if rule.MaxAgeSeconds > 0 || rule.MaxAgeSeconds == -1 {
 							w.Header().Set(api.AccessControlMaxAge, strconv.Itoa(rule.MaxAgeSeconds))
 						} else {
-							w.Header().Set(api.AccessControlMaxAge, strconv.Itoa(h.cfg.DefaultMaxAge))
+							w.Header().Set(api.AccessControlMaxAge, strconv.Itoa(h.config().DefaultMaxAge))
 						}
 						if o != wildcard {
 							w.Header().Set(api.AccessControlAllowCredentials, "true")
@@ -29,6 +29,7 @@ type DeleteObjectsRequest struct {
 type ObjectIdentifier struct {
 	ObjectName string `xml:"Key"`
 	VersionID  string `xml:"VersionId,omitempty"`
+	ETag       string `xml:"ETag,omitempty"`
 }
 
 // DeletedObject carries the key name for the object to delete.
@@ -63,6 +64,7 @@ func (h *handler) DeleteObjectHandler(w http.ResponseWriter, r *http.Request) {
 	versionedObject := []*layer.VersionedObject{{
 		Name:      reqInfo.ObjectName,
 		VersionID: versionID,
+		IfMatch:   r.Header.Get(api.IfMatch),
 	}}
 
 	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
@@ -171,8 +173,12 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 
 	// Unmarshal list of keys to be deleted.
 	requested := &DeleteObjectsRequest{}
-	if err := xml.NewDecoder(r.Body).Decode(requested); err != nil {
-		h.logAndSendError(w, "couldn't decode body", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+	if err := h.decodeXML(r, requested, errors.ErrMalformedXML); err != nil {
+		h.logAndSendError(w, "couldn't decode body", reqInfo, err)
+		return
+	}
+	if len(requested.Objects) > h.config().Limits.MaxDeleteObjects {
+		h.logAndSendError(w, "too many objects to delete", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
 		return
 	}
 
@@ -182,6 +188,7 @@ func (h *handler) DeleteMultipleObjectsHandler(w http.ResponseWriter, r *http.Re
 		versionedObj := &layer.VersionedObject{
 			Name:      obj.ObjectName,
 			VersionID: obj.VersionID,
+			IfMatch:   obj.ETag,
 		}
 		toRemove = append(toRemove, versionedObj)
 		removed[versionedObj.String()] = versionedObj
@@ -280,6 +287,14 @@ func (h *handler) DeleteBucketHandler(w http.ResponseWriter, r *http.Request) {
 		SessionToken: sessionToken,
 	}); err != nil {
 		h.logAndSendError(w, "couldn't delete bucket", reqInfo, err)
+		return
 	}
+
+	h.audit(r, &bucketAuditInfo{
+		ContainerID:        bktInfo.CID.EncodeToString(),
+		LocationConstraint: bktInfo.LocationConstraint,
+		ObjectLockEnabled:  bktInfo.ObjectLockEnabled,
+	}, nil)
+
 	w.WriteHeader(http.StatusNoContent)
 }
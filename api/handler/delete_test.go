@@ -243,6 +243,53 @@ func TestDeleteObjectCheckMarkerReturn(t *testing.T) {
 	require.Equal(t, deleteMarkerVersion, deleteMarkerVersion2)
 }
 
+func TestDeleteObjectWithIfMatch(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-removal", "object-to-delete"
+	_, objInfo := createBucketAndObject(tc, bktName, objName)
+
+	w, r := prepareTestRequest(tc, bktName, objName, nil)
+	r.Header.Set(api.IfMatch, "wrong-etag")
+	tc.Handler().DeleteObjectHandler(w, r)
+	assertStatus(t, w, http.StatusPreconditionFailed)
+	checkFound(t, tc, bktName, objName, emptyVersion)
+
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	r.Header.Set(api.IfMatch, objInfo.HashSum)
+	tc.Handler().DeleteObjectHandler(w, r)
+	assertStatus(t, w, http.StatusNoContent)
+	checkNotFound(t, tc, bktName, objName, emptyVersion)
+}
+
+func TestDeleteMultipleObjectsWithETag(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName, objName2 := "bucket-for-removal", "object-to-delete", "object-to-delete-2"
+	bktInfo, objInfo := createBucketAndObject(tc, bktName, objName)
+	createTestObject(tc, bktInfo, objName2)
+
+	req := &DeleteObjectsRequest{Objects: []ObjectIdentifier{
+		{ObjectName: objName, ETag: objInfo.HashSum},
+		{ObjectName: objName2, ETag: "wrong-etag"},
+	}}
+
+	w, r := prepareTestRequest(tc, bktName, "", req)
+	r.Header.Set(api.ContentMD5, "")
+	tc.Handler().DeleteMultipleObjectsHandler(w, r)
+
+	resp := &DeleteObjectsResponse{}
+	readResponse(t, w, http.StatusOK, resp)
+	require.Len(t, resp.DeletedObjects, 1)
+	require.Equal(t, objName, resp.DeletedObjects[0].ObjectName)
+	require.Len(t, resp.Errors, 1)
+	require.Equal(t, objName2, resp.Errors[0].Key)
+	require.Equal(t, "PreconditionFailed", resp.Errors[0].Code)
+
+	checkNotFound(t, tc, bktName, objName, emptyVersion)
+	checkFound(t, tc, bktName, objName2, emptyVersion)
+}
+
 func createBucketAndObject(tc *handlerContext, bktName, objName string) (*data.BucketInfo, *data.ObjectInfo) {
 	bktInfo := createTestBucket(tc, bktName)
 
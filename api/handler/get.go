@@ -104,6 +104,9 @@ func writeHeaders(h http.Header, requestHeader http.Header, extendedInfo *data.E
 	if expires := info.Headers[api.Expires]; expires != "" {
 		h.Set(api.Expires, expires)
 	}
+	if storageClass := info.Headers[layer.AttributeStorageClass]; storageClass != "" {
+		h.Set(api.AmzStorageClass, storageClass)
+	}
 
 	for key, val := range info.Headers {
 		if layer.IsSystemHeader(key) {
@@ -113,6 +116,15 @@ func writeHeaders(h http.Header, requestHeader http.Header, extendedInfo *data.E
 	}
 }
 
+// objectStorageClass returns storage class of the object, STANDARD if it's not set.
+func objectStorageClass(info *data.ObjectInfo) string {
+	if storageClass := info.Headers[layer.AttributeStorageClass]; storageClass != "" {
+		return storageClass
+	}
+
+	return StandardStorageClass
+}
+
 func (h *handler) GetObjectHandler(w http.ResponseWriter, r *http.Request) {
 	var (
 		params *layer.RangeParams
@@ -77,6 +77,7 @@ func prepareHandlerContext(t *testing.T) *handlerContext {
 		obj: layer.NewLayer(l, tp, layerCfg),
 		cfg: &Config{
 			TLSEnabled: true,
+			Limits:     DefaultLimits(),
 		},
 	}
 
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+)
+
+// Default limits of request sizes, they match the limits of AWS S3.
+const (
+	DefaultMaxBodySize      = 2 * 1048576    // 2MB
+	DefaultMaxPolicySize    = 20 * 1024      // 20KB
+	DefaultMaxPutSize       = 5 * 1073741824 // 5GB
+	DefaultMaxMetadataSize  = 2 * 1024       // 2KB
+	DefaultMaxDeleteObjects = 1000
+	DefaultMaxParts         = layer.UploadMaxPartNumber
+)
+
+// bodyTooLargeError is returned on reading of the request body exceeding the limit.
+// It's sent to the client as the S3 error even if it's wrapped.
+type bodyTooLargeError struct {
+	err errors.Error
+}
+
+// limitedBody returns the error as soon as more than n bytes are read.
+type limitedBody struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+// DefaultLimits returns limits of request sizes compatible with AWS S3.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxBodySize:      DefaultMaxBodySize,
+		MaxPolicySize:    DefaultMaxPolicySize,
+		MaxPutSize:       DefaultMaxPutSize,
+		MaxMetadataSize:  DefaultMaxMetadataSize,
+		MaxDeleteObjects: DefaultMaxDeleteObjects,
+		MaxParts:         DefaultMaxParts,
+	}
+}
+
+func (e bodyTooLargeError) Error() string {
+	return e.err.Error()
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, l.err
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	if int64(n) > l.n {
+		n, l.n = int(l.n), -1
+		return n, l.err
+	}
+	l.n -= int64(n)
+
+	return n, err
+}
+
+// limitBody returns the request body which can't be read beyond the limit. The body
+// exceeding the limit is failed with the specified S3 error.
+func limitBody(r *http.Request, limit int64, code errors.ErrorCode) io.Reader {
+	err := bodyTooLargeError{err: errors.GetAPIError(code)}
+	if r.ContentLength > limit {
+		return &limitedBody{n: -1, err: err}
+	}
+	return &limitedBody{r: r.Body, n: limit, err: err}
+}
+
+// decodeXML decodes the XML body of the request limited by the max body size. It returns
+// MaxMessageLengthExceeded error if the body is too large and the specified S3 error if
+// the body can't be decoded.
+func (h *handler) decodeXML(r *http.Request, v interface{}, code errors.ErrorCode) error {
+	body := limitBody(r, h.config().Limits.MaxBodySize, errors.ErrMaxMessageLengthExceeded)
+	if err := xml.NewDecoder(body).Decode(v); err != nil {
+		if tooLarge, ok := err.(bodyTooLargeError); ok {
+			return tooLarge.err
+		}
+		return errors.GetAPIError(code)
+	}
+	return nil
+}
+
+// checkMetadataSize checks that the total size of user metadata keys and values doesn't
+// exceed the limit. Content-Type which is set from POST form isn't user metadata.
+func checkMetadataSize(metadata map[string]string, limit int) error {
+	var size int
+	for k, v := range metadata {
+		if k != api.ContentType {
+			size += len(k) + len(v)
+		}
+	}
+	if size > limit {
+		return errors.GetAPIError(errors.ErrMetadataTooLarge)
+	}
+	return nil
+}
+
+// checkPutSize checks the size of the uploaded object or part. The body of unknown size
+// is limited while it's read.
+func checkPutSize(r *http.Request, limit int64) error {
+	if r.ContentLength > limit {
+		return errors.GetAPIError(errors.ErrEntityTooLarge)
+	}
+	if r.ContentLength < 0 {
+		r.Body = io.NopCloser(limitBody(r, limit, errors.ErrEntityTooLarge))
+	}
+	return nil
+}
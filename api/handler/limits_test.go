@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimitedBody(t *testing.T) {
+	tooLarge := bodyTooLargeError{err: apiErrors.GetAPIError(apiErrors.ErrEntityTooLarge)}
+
+	data, err := io.ReadAll(&limitedBody{r: strings.NewReader("content"), n: 7, err: tooLarge})
+	require.NoError(t, err)
+	require.Equal(t, "content", string(data))
+
+	data, err = io.ReadAll(&limitedBody{r: strings.NewReader("content"), n: 6, err: tooLarge})
+	require.ErrorIs(t, err, tooLarge)
+	require.Equal(t, "conten", string(data))
+}
+
+func TestRequestLimits(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-limits", "object-for-limits"
+	createTestBucket(tc, bktName)
+
+	tc.Handler().cfg.Limits = Limits{
+		MaxBodySize:      256,
+		MaxPolicySize:    DefaultMaxPolicySize,
+		MaxPutSize:       16,
+		MaxMetadataSize:  16,
+		MaxDeleteObjects: 2,
+		MaxParts:         100,
+	}
+
+	t.Run("object size", func(t *testing.T) {
+		w, r := prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content larger than limit"))
+		tc.Handler().PutObjectHandler(w, r)
+		assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrEntityTooLarge))
+
+		w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+		tc.Handler().PutObjectHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+	})
+
+	t.Run("metadata size", func(t *testing.T) {
+		w, r := prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+		r.Header.Set(api.MetadataPrefix+"Key", "value larger than limit")
+		tc.Handler().PutObjectHandler(w, r)
+		assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrMetadataTooLarge))
+
+		w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+		r.Header.Set(api.MetadataPrefix+"Key", "value")
+		tc.Handler().PutObjectHandler(w, r)
+		assertStatus(t, w, http.StatusOK)
+	})
+
+	t.Run("delete objects", func(t *testing.T) {
+		req := &DeleteObjectsRequest{Objects: []ObjectIdentifier{{ObjectName: "a"}, {ObjectName: "b"}, {ObjectName: "c"}}}
+		w, r := prepareTestRequest(tc, bktName, "", req)
+		r.Header.Set(api.ContentMD5, "")
+		tc.Handler().DeleteMultipleObjectsHandler(w, r)
+		assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrMalformedXML))
+	})
+
+	t.Run("tagging body", func(t *testing.T) {
+		tagging := &Tagging{TagSet: []Tag{{Key: "key", Value: strings.Repeat("v", 256)}}}
+		w, r := prepareTestRequest(tc, bktName, objName, tagging)
+		tc.Handler().PutObjectTaggingHandler(w, r)
+		assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrMaxMessageLengthExceeded))
+	})
+
+	t.Run("cors body of unknown size", func(t *testing.T) {
+		cors := &data.CORSConfiguration{CORSRules: []data.CORSRule{{
+			AllowedHeaders: []string{strings.Repeat("h", 256)},
+			AllowedMethods: []string{http.MethodGet},
+			AllowedOrigins: []string{"*"},
+		}}}
+		w, r := prepareTestRequest(tc, bktName, "", cors)
+		r.ContentLength = -1
+		tc.Handler().PutBucketCorsHandler(w, r)
+		assertS3Error(t, w, apiErrors.GetAPIError(apiErrors.ErrMaxMessageLengthExceeded))
+	})
+}
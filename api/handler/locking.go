@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/xml"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -40,7 +39,7 @@ func (h *handler) PutBucketObjectLockConfigHandler(w http.ResponseWriter, r *htt
 	}
 
 	lockingConf := &data.ObjectLockConfiguration{}
-	if err = xml.NewDecoder(r.Body).Decode(lockingConf); err != nil {
+	if err = h.decodeXML(r, lockingConf, apiErrors.ErrMalformedXML); err != nil {
 		h.logAndSendError(w, "couldn't parse locking configuration", reqInfo, err)
 		return
 	}
@@ -69,6 +68,8 @@ func (h *handler) PutBucketObjectLockConfigHandler(w http.ResponseWriter, r *htt
 		h.logAndSendError(w, "couldn't put bucket settings", reqInfo, err)
 		return
 	}
+
+	h.audit(r, settings.LockConfiguration, lockingConf)
 }
 
 func (h *handler) GetBucketObjectLockConfigHandler(w http.ResponseWriter, r *http.Request) {
@@ -120,7 +121,7 @@ func (h *handler) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	legalHold := &data.LegalHold{}
-	if err = xml.NewDecoder(r.Body).Decode(legalHold); err != nil {
+	if err = h.decodeXML(r, legalHold, apiErrors.ErrMalformedXML); err != nil {
 		h.logAndSendError(w, "couldn't parse legal hold configuration", reqInfo, err)
 		return
 	}
@@ -131,24 +132,35 @@ func (h *handler) PutObjectLegalHoldHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	objVersion := &layer.ObjectVersion{
+		BktInfo:    bktInfo,
+		ObjectName: reqInfo.ObjectName,
+		VersionID:  reqInfo.URL.Query().Get(api.QueryVersionID),
+	}
+
+	var before *data.LegalHold
+	if h.auditEnabled() {
+		if lockInfo, err := h.obj.GetLockInfo(r.Context(), objVersion); err == nil {
+			before = formLegalHold(lockInfo)
+		}
+	}
+
 	p := &layer.PutLockInfoParams{
-		ObjVersion: &layer.ObjectVersion{
-			BktInfo:    bktInfo,
-			ObjectName: reqInfo.ObjectName,
-			VersionID:  reqInfo.URL.Query().Get(api.QueryVersionID),
-		},
+		ObjVersion: objVersion,
 		NewLock: &data.ObjectLock{
 			LegalHold: &data.LegalHoldLock{
 				Enabled: legalHold.Status == legalHoldOn,
 			},
 		},
-		CopiesNumber: h.cfg.CopiesNumber,
+		CopiesNumber: h.config().CopiesNumber,
 	}
 
 	if err = h.obj.PutLockInfo(r.Context(), p); err != nil {
 		h.logAndSendError(w, "couldn't head put legal hold", reqInfo, err)
 		return
 	}
+
+	h.audit(r, before, legalHold)
 }
 
 func (h *handler) GetObjectLegalHoldHandler(w http.ResponseWriter, r *http.Request) {
@@ -178,12 +190,7 @@ func (h *handler) GetObjectLegalHoldHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	legalHold := &data.LegalHold{Status: legalHoldOff}
-	if lockInfo.IsLegalHoldSet() {
-		legalHold.Status = legalHoldOn
-	}
-
-	if err = api.EncodeToResponse(w, legalHold); err != nil {
+	if err = api.EncodeToResponse(w, formLegalHold(lockInfo)); err != nil {
 		h.logAndSendError(w, "something went wrong", reqInfo, err)
 	}
 }
@@ -203,7 +210,7 @@ func (h *handler) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	retention := &data.Retention{}
-	if err = xml.NewDecoder(r.Body).Decode(retention); err != nil {
+	if err = h.decodeXML(r, retention, apiErrors.ErrMalformedXML); err != nil {
 		h.logAndSendError(w, "couldn't parse object retention", reqInfo, err)
 		return
 	}
@@ -214,20 +221,31 @@ func (h *handler) PutObjectRetentionHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	objVersion := &layer.ObjectVersion{
+		BktInfo:    bktInfo,
+		ObjectName: reqInfo.ObjectName,
+		VersionID:  reqInfo.URL.Query().Get(api.QueryVersionID),
+	}
+
+	var before *data.Retention
+	if h.auditEnabled() {
+		if lockInfo, err := h.obj.GetLockInfo(r.Context(), objVersion); err == nil && lockInfo.IsRetentionSet() {
+			before = formRetention(lockInfo)
+		}
+	}
+
 	p := &layer.PutLockInfoParams{
-		ObjVersion: &layer.ObjectVersion{
-			BktInfo:    bktInfo,
-			ObjectName: reqInfo.ObjectName,
-			VersionID:  reqInfo.URL.Query().Get(api.QueryVersionID),
-		},
+		ObjVersion:   objVersion,
 		NewLock:      lock,
-		CopiesNumber: h.cfg.CopiesNumber,
+		CopiesNumber: h.config().CopiesNumber,
 	}
 
 	if err = h.obj.PutLockInfo(r.Context(), p); err != nil {
 		h.logAndSendError(w, "couldn't put legal hold", reqInfo, err)
 		return
 	}
+
+	h.audit(r, before, retention)
 }
 
 func (h *handler) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Request) {
@@ -262,6 +280,21 @@ func (h *handler) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if err = api.EncodeToResponse(w, formRetention(lockInfo)); err != nil {
+		h.logAndSendError(w, "something went wrong", reqInfo, err)
+	}
+}
+
+func formLegalHold(lockInfo *data.LockInfo) *data.LegalHold {
+	legalHold := &data.LegalHold{Status: legalHoldOff}
+	if lockInfo.IsLegalHoldSet() {
+		legalHold.Status = legalHoldOn
+	}
+
+	return legalHold
+}
+
+func formRetention(lockInfo *data.LockInfo) *data.Retention {
 	retention := &data.Retention{
 		Mode:            governanceMode,
 		RetainUntilDate: lockInfo.UntilDate(),
@@ -270,9 +303,7 @@ func (h *handler) GetObjectRetentionHandler(w http.ResponseWriter, r *http.Reque
 		retention.Mode = complianceMode
 	}
 
-	if err = api.EncodeToResponse(w, retention); err != nil {
-		h.logAndSendError(w, "something went wrong", reqInfo, err)
-	}
+	return retention
 }
 
 func checkLockConfiguration(conf *data.ObjectLockConfiguration) error {
@@ -144,13 +144,17 @@ func (h *handler) CreateMultipartUploadHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	p.Header = parseMetadata(r)
+	if err = checkMetadataSize(p.Header, h.config().Limits.MaxMetadataSize); err != nil {
+		h.logAndSendError(w, "invalid metadata", reqInfo, err)
+		return
+	}
 	if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
 		p.Header[api.ContentType] = contentType
 	}
 
-	p.CopiesNumber, err = getCopiesNumberOrDefault(p.Header, h.cfg.CopiesNumber)
+	p.StorageClass, p.CopiesNumber, err = h.getStorageClassAndCopiesNumber(r.Header, p.Header)
 	if err != nil {
-		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		h.logAndSendError(w, "invalid storage class or copies number", reqInfo, err)
 		return
 	}
 
@@ -210,11 +214,16 @@ func (h *handler) UploadPartHandler(w http.ResponseWriter, r *http.Request) {
 	)
 
 	partNumber, err := strconv.Atoi(queryValues.Get(partNumberHeaderName))
-	if err != nil || partNumber < layer.UploadMinPartNumber || partNumber > layer.UploadMaxPartNumber {
+	if err != nil || partNumber < layer.UploadMinPartNumber || partNumber > h.config().Limits.MaxParts {
 		h.logAndSendError(w, "invalid part number", reqInfo, errors.GetAPIError(errors.ErrInvalidPartNumber))
 		return
 	}
 
+	if err = checkPutSize(r, h.config().Limits.MaxPutSize); err != nil {
+		h.logAndSendError(w, "invalid part size", reqInfo, err, additional...)
+		return
+	}
+
 	p := &layer.UploadPartParams{
 		Info: &layer.UploadInfoParams{
 			UploadID: uploadID,
@@ -256,7 +265,7 @@ func (h *handler) UploadPartCopy(w http.ResponseWriter, r *http.Request) {
 	)
 
 	partNumber, err := strconv.Atoi(queryValues.Get(partNumberHeaderName))
-	if err != nil || partNumber < layer.UploadMinPartNumber || partNumber > layer.UploadMaxPartNumber {
+	if err != nil || partNumber < layer.UploadMinPartNumber || partNumber > h.config().Limits.MaxParts {
 		h.logAndSendError(w, "invalid part number", reqInfo, errors.GetAPIError(errors.ErrInvalidPartNumber))
 		return
 	}
@@ -384,19 +393,19 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 	)
 
 	reqBody := new(CompleteMultipartUpload)
-	if err = xml.NewDecoder(r.Body).Decode(reqBody); err != nil {
-		h.logAndSendError(w, "could not read complete multipart upload xml", reqInfo,
-			errors.GetAPIError(errors.ErrMalformedXML), additional...)
+	if err = h.decodeXML(r, reqBody, errors.ErrMalformedXML); err != nil {
+		h.logAndSendError(w, "could not read complete multipart upload xml", reqInfo, err, additional...)
 		return
 	}
-	if len(reqBody.Parts) == 0 {
+	if len(reqBody.Parts) == 0 || len(reqBody.Parts) > h.config().Limits.MaxParts {
 		h.logAndSendError(w, "invalid xml with parts", reqInfo, errors.GetAPIError(errors.ErrMalformedXML), additional...)
 		return
 	}
 
 	c := &layer.CompleteMultipartParams{
-		Info:  uploadInfo,
-		Parts: reqBody.Parts,
+		Info:       uploadInfo,
+		Parts:      reqBody.Parts,
+		Conditions: parsePutConditionalHeaders(r.Header),
 	}
 
 	uploadData, extendedObjInfo, err := h.obj.CompleteMultipartUpload(r.Context(), c)
@@ -406,22 +415,6 @@ func (h *handler) CompleteMultipartUploadHandler(w http.ResponseWriter, r *http.
 	}
 	objInfo := extendedObjInfo.ObjectInfo
 
-	if len(uploadData.TagSet) != 0 {
-		tagPrm := &layer.PutObjectTaggingParams{
-			ObjectVersion: &layer.ObjectVersion{
-				BktInfo:    bktInfo,
-				ObjectName: objInfo.Name,
-				VersionID:  objInfo.VersionID(),
-			},
-			TagSet:      uploadData.TagSet,
-			NodeVersion: extendedObjInfo.NodeVersion,
-		}
-		if _, err = h.obj.PutObjectTagging(r.Context(), tagPrm); err != nil {
-			h.logAndSendError(w, "could not put tagging file of completed multipart upload", reqInfo, err, additional...)
-			return
-		}
-	}
-
 	if len(uploadData.ACLHeaders) != 0 {
 		key, err := h.bearerTokenIssuerKey(r.Context())
 		if err != nil {
@@ -102,8 +102,8 @@ func (h *handler) PutBucketNotificationHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	conf := &data.NotificationConfiguration{}
-	if err = xml.NewDecoder(r.Body).Decode(conf); err != nil {
-		h.logAndSendError(w, "couldn't decode notification configuration", reqInfo, errors.GetAPIError(errors.ErrMalformedXML))
+	if err = h.decodeXML(r, conf, errors.ErrMalformedXML); err != nil {
+		h.logAndSendError(w, "couldn't decode notification configuration", reqInfo, err)
 		return
 	}
 
@@ -112,17 +112,24 @@ func (h *handler) PutBucketNotificationHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	var before *data.NotificationConfiguration
+	if h.auditEnabled() {
+		before, _ = h.obj.GetBucketNotificationConfiguration(r.Context(), bktInfo)
+	}
+
 	p := &layer.PutBucketNotificationConfigurationParams{
 		RequestInfo:   reqInfo,
 		BktInfo:       bktInfo,
 		Configuration: conf,
-		CopiesNumber:  h.cfg.CopiesNumber,
+		CopiesNumber:  h.config().CopiesNumber,
 	}
 
 	if err = h.obj.PutBucketNotificationConfiguration(r.Context(), p); err != nil {
 		h.logAndSendError(w, "couldn't put bucket configuration", reqInfo, err)
 		return
 	}
+
+	h.audit(r, before, conf)
 }
 
 func (h *handler) GetBucketNotificationHandler(w http.ResponseWriter, r *http.Request) {
@@ -147,7 +154,7 @@ func (h *handler) GetBucketNotificationHandler(w http.ResponseWriter, r *http.Re
 }
 
 func (h *handler) sendNotifications(ctx context.Context, p *SendNotificationParams) error {
-	if !h.cfg.NotificatorEnabled {
+	if !h.config().NotificatorEnabled {
 		return nil
 	}
 
@@ -188,7 +195,7 @@ func (h *handler) checkBucketConfiguration(conf *data.NotificationConfiguration,
 			return
 		}
 
-		if h.cfg.NotificatorEnabled {
+		if h.config().NotificatorEnabled {
 			if err = h.notificator.SendTestNotification(q.QueueArn, r.BucketName, r.RequestID, r.Host); err != nil {
 				return
 			}
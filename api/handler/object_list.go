@@ -196,6 +196,7 @@ func fillContents(src []*data.ObjectInfo, encode string, fetchOwner bool) []Obje
 			Size:         obj.Size,
 			LastModified: obj.Created.UTC().Format(time.RFC3339),
 			ETag:         obj.HashSum,
+			StorageClass: objectStorageClass(obj),
 		}
 
 		if fetchOwner {
@@ -280,9 +281,10 @@ func encodeListObjectVersionsToResponse(info *layer.ListObjectVersionsInfo, buck
 				ID:          ver.ObjectInfo.Owner.String(),
 				DisplayName: ver.ObjectInfo.Owner.String(),
 			},
-			Size:      ver.ObjectInfo.Size,
-			VersionID: ver.Version(),
-			ETag:      ver.ObjectInfo.HashSum,
+			Size:         ver.ObjectInfo.Size,
+			VersionID:    ver.Version(),
+			ETag:         ver.ObjectInfo.HashSum,
+			StorageClass: objectStorageClass(ver.ObjectInfo),
 		})
 	}
 	// this loop is not starting till versioning is not implemented
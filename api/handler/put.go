@@ -177,6 +177,7 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 	var (
 		err              error
 		newEaclTable     *eacl.Table
+		prevEaclTable    *eacl.Table
 		sessionTokenEACL *session.Container
 		containsACL      = containsACLHeaders(r)
 		reqInfo          = api.GetReqInfo(r.Context())
@@ -195,13 +196,24 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limits := h.config().Limits
+	if err = checkPutSize(r, limits.MaxPutSize); err != nil {
+		h.logAndSendError(w, "invalid object size", reqInfo, err)
+		return
+	}
+
+	metadata := parseMetadata(r)
+	if err = checkMetadataSize(metadata, limits.MaxMetadataSize); err != nil {
+		h.logAndSendError(w, "invalid metadata", reqInfo, err)
+		return
+	}
+
 	bktInfo, err := h.getBucketAndCheckOwner(r, reqInfo.BucketName)
 	if err != nil {
 		h.logAndSendError(w, "could not get bucket objInfo", reqInfo, err)
 		return
 	}
 
-	metadata := parseMetadata(r)
 	if contentType := r.Header.Get(api.ContentType); len(contentType) > 0 {
 		metadata[api.ContentType] = contentType
 	}
@@ -212,9 +224,9 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		metadata[api.Expires] = expires
 	}
 
-	copiesNumber, err := getCopiesNumberOrDefault(metadata, h.cfg.CopiesNumber)
+	storageClass, copiesNumber, err := h.getStorageClassAndCopiesNumber(r.Header, metadata)
 	if err != nil {
-		h.logAndSendError(w, "invalid copies number", reqInfo, err)
+		h.logAndSendError(w, "invalid storage class or copies number", reqInfo, err)
 		return
 	}
 
@@ -232,6 +244,9 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 		Header:       metadata,
 		Encryption:   encryption,
 		CopiesNumber: copiesNumber,
+		StorageClass: storageClass,
+		Conditions:   parsePutConditionalHeaders(r.Header),
+		TagSet:       tagSet,
 	}
 
 	settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo)
@@ -266,28 +281,12 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if containsACL {
-		if newEaclTable, err = h.getNewEAclTable(r, bktInfo, objInfo); err != nil {
+		if newEaclTable, prevEaclTable, err = h.getNewEAclTable(r, bktInfo, objInfo); err != nil {
 			h.logAndSendError(w, "could not get new eacl table", reqInfo, err)
 			return
 		}
 	}
 
-	if tagSet != nil {
-		tagPrm := &layer.PutObjectTaggingParams{
-			ObjectVersion: &layer.ObjectVersion{
-				BktInfo:    bktInfo,
-				ObjectName: objInfo.Name,
-				VersionID:  objInfo.VersionID(),
-			},
-			TagSet:      tagSet,
-			NodeVersion: extendedObjInfo.NodeVersion,
-		}
-		if _, err = h.obj.PutObjectTagging(r.Context(), tagPrm); err != nil {
-			h.logAndSendError(w, "could not upload object tagging", reqInfo, err)
-			return
-		}
-	}
-
 	if newEaclTable != nil {
 		p := &layer.PutBucketACLParams{
 			BktInfo:      bktInfo,
@@ -299,6 +298,8 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 			h.logAndSendError(w, "could not put bucket acl", reqInfo, err)
 			return
 		}
+
+		h.audit(r, eaclAuditInfo(prevEaclTable), eaclAuditInfo(newEaclTable))
 	}
 
 	if settings.VersioningEnabled() {
@@ -312,6 +313,21 @@ func (h *handler) PutObjectHandler(w http.ResponseWriter, r *http.Request) {
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
+// parsePutConditionalHeaders forms conditions to overwrite an object
+// from If-Match and If-None-Match headers. Returns nil if headers are not set.
+func parsePutConditionalHeaders(headers http.Header) *layer.PutConditions {
+	cond := &layer.PutConditions{
+		IfMatch:     headers.Get(api.IfMatch),
+		IfNoneMatch: headers.Get(api.IfNoneMatch),
+	}
+
+	if len(cond.IfMatch) == 0 && len(cond.IfNoneMatch) == 0 {
+		return nil
+	}
+
+	return cond
+}
+
 func getCopiesNumberOrDefault(metadata map[string]string, defaultCopiesNumber uint32) (uint32, error) {
 	copiesNumberStr, ok := metadata[layer.AttributeNeofsCopiesNumber]
 	if !ok {
@@ -326,7 +342,38 @@ func getCopiesNumberOrDefault(metadata map[string]string, defaultCopiesNumber ui
 	return uint32(copiesNumber), nil
 }
 
-func (h handler) formEncryptionParams(header http.Header) (enc encryption.Params, err error) {
+// getStorageClassAndCopiesNumber returns storage class from X-Amz-Storage-Class header
+// and the number of copies configured for it. Copies number set in metadata overrides
+// the storage class one. Empty storage class is returned for the STANDARD class.
+func (h *handler) getStorageClassAndCopiesNumber(headers http.Header, metadata map[string]string) (string, uint32, error) {
+	storageClass := headers.Get(api.AmzStorageClass)
+	if len(storageClass) == 0 {
+		storageClass = StandardStorageClass
+	}
+
+	cfg := h.config()
+	copiesNumber := cfg.CopiesNumber
+	classCopiesNumber, ok := cfg.StorageClasses[storageClass]
+	if !ok && storageClass != StandardStorageClass {
+		return "", 0, errors.GetAPIError(errors.ErrInvalidStorageClass)
+	}
+	if classCopiesNumber > 0 {
+		copiesNumber = classCopiesNumber
+	}
+
+	copiesNumber, err := getCopiesNumberOrDefault(metadata, copiesNumber)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if storageClass == StandardStorageClass {
+		storageClass = ""
+	}
+
+	return storageClass, copiesNumber, nil
+}
+
+func (h *handler) formEncryptionParams(header http.Header) (enc encryption.Params, err error) {
 	sseCustomerAlgorithm := header.Get(api.AmzServerSideEncryptionCustomerAlgorithm)
 	sseCustomerKey := header.Get(api.AmzServerSideEncryptionCustomerKey)
 	sseCustomerKeyMD5 := header.Get(api.AmzServerSideEncryptionCustomerKeyMD5)
@@ -335,7 +382,7 @@ func (h handler) formEncryptionParams(header http.Header) (enc encryption.Params
 		return
 	}
 
-	if !h.cfg.TLSEnabled {
+	if !h.config().TLSEnabled {
 		return enc, errorsStd.New("encryption available only when TLS is enabled")
 	}
 
@@ -373,6 +420,7 @@ func (h handler) formEncryptionParams(header http.Header) (enc encryption.Params
 func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 	var (
 		newEaclTable     *eacl.Table
+		prevEaclTable    *eacl.Table
 		tagSet           map[string]string
 		sessionTokenEACL *session.Container
 		reqInfo          = api.GetReqInfo(r.Context())
@@ -422,6 +470,16 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	limits := h.config().Limits
+	if size > limits.MaxPutSize {
+		h.logAndSendError(w, "invalid object size", reqInfo, errors.GetAPIError(errors.ErrEntityTooLarge))
+		return
+	}
+	if err = checkMetadataSize(metadata, limits.MaxMetadataSize); err != nil {
+		h.logAndSendError(w, "invalid metadata", reqInfo, err)
+		return
+	}
+
 	bktInfo, err := h.obj.GetBucketInfo(r.Context(), reqInfo.BucketName)
 	if err != nil {
 		h.logAndSendError(w, "could not get bucket info", reqInfo, err)
@@ -434,6 +492,7 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		Reader:  contentReader,
 		Size:    size,
 		Header:  metadata,
+		TagSet:  tagSet,
 	}
 
 	extendedObjInfo, err := h.obj.PutObject(r.Context(), params)
@@ -459,28 +518,12 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 		r.Header.Set(api.AmzGrantWrite, "")
 		r.Header.Set(api.AmzGrantRead, "")
 
-		if newEaclTable, err = h.getNewEAclTable(r, bktInfo, objInfo); err != nil {
+		if newEaclTable, prevEaclTable, err = h.getNewEAclTable(r, bktInfo, objInfo); err != nil {
 			h.logAndSendError(w, "could not get new eacl table", reqInfo, err)
 			return
 		}
 	}
 
-	if tagSet != nil {
-		tagPrm := &layer.PutObjectTaggingParams{
-			ObjectVersion: &layer.ObjectVersion{
-				BktInfo:    bktInfo,
-				ObjectName: objInfo.Name,
-				VersionID:  objInfo.VersionID(),
-			},
-			NodeVersion: extendedObjInfo.NodeVersion,
-		}
-
-		if _, err = h.obj.PutObjectTagging(r.Context(), tagPrm); err != nil {
-			h.logAndSendError(w, "could not upload object tagging", reqInfo, err)
-			return
-		}
-	}
-
 	if newEaclTable != nil {
 		p := &layer.PutBucketACLParams{
 			BktInfo:      bktInfo,
@@ -492,6 +535,8 @@ func (h *handler) PostObject(w http.ResponseWriter, r *http.Request) {
 			h.logAndSendError(w, "could not put bucket acl", reqInfo, err)
 			return
 		}
+
+		h.audit(r, eaclAuditInfo(prevEaclTable), eaclAuditInfo(newEaclTable))
 	}
 
 	if settings, err := h.obj.GetBucketSettings(r.Context(), bktInfo); err != nil {
@@ -583,15 +628,17 @@ func containsACLHeaders(r *http.Request) bool {
 		r.Header.Get(api.AmzGrantFullControl) != "" || r.Header.Get(api.AmzGrantWrite) != ""
 }
 
-func (h *handler) getNewEAclTable(r *http.Request, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) (*eacl.Table, error) {
+// getNewEAclTable returns the bucket eACL with the grants of the object ACL headers and the current
+// bucket eACL to audit the change. The new table is nil if the grants are already in the bucket eACL.
+func (h *handler) getNewEAclTable(r *http.Request, bktInfo *data.BucketInfo, objInfo *data.ObjectInfo) (*eacl.Table, *eacl.Table, error) {
 	var newEaclTable *eacl.Table
 	key, err := h.bearerTokenIssuerKey(r.Context())
 	if err != nil {
-		return nil, fmt.Errorf("get bearer token issuer: %w", err)
+		return nil, nil, fmt.Errorf("get bearer token issuer: %w", err)
 	}
 	objectACL, err := parseACLHeaders(r.Header, key)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse object acl: %w", err)
+		return nil, nil, fmt.Errorf("could not parse object acl: %w", err)
 	}
 
 	resInfo := &resourceInfo{
@@ -602,17 +649,17 @@ func (h *handler) getNewEAclTable(r *http.Request, bktInfo *data.BucketInfo, obj
 
 	bktPolicy, err := aclToPolicy(objectACL, resInfo)
 	if err != nil {
-		return nil, fmt.Errorf("could not translate object acl to bucket policy: %w", err)
+		return nil, nil, fmt.Errorf("could not translate object acl to bucket policy: %w", err)
 	}
 
 	astChild, err := policyToAst(bktPolicy)
 	if err != nil {
-		return nil, fmt.Errorf("could not translate policy to ast: %w", err)
+		return nil, nil, fmt.Errorf("could not translate policy to ast: %w", err)
 	}
 
 	bacl, err := h.obj.GetBucketACL(r.Context(), bktInfo)
 	if err != nil {
-		return nil, fmt.Errorf("could not get bucket eacl: %w", err)
+		return nil, nil, fmt.Errorf("could not get bucket eacl: %w", err)
 	}
 
 	parentAst := tableToAst(bacl.EACL, objInfo.Bucket)
@@ -626,11 +673,11 @@ func (h *handler) getNewEAclTable(r *http.Request, bktInfo *data.BucketInfo, obj
 
 	if resAst, updated := mergeAst(parentAst, astChild); updated {
 		if newEaclTable, err = astToTable(resAst); err != nil {
-			return nil, fmt.Errorf("could not translate ast to table: %w", err)
+			return nil, nil, fmt.Errorf("could not translate ast to table: %w", err)
 		}
 	}
 
-	return newEaclTable, nil
+	return newEaclTable, bacl.EACL, nil
 }
 
 func parseTaggingHeader(header http.Header) (map[string]string, error) {
@@ -698,7 +745,7 @@ func (h *handler) CreateBucketHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createParams, err := parseLocationConstraint(r)
+	createParams, err := h.parseLocationConstraint(r)
 	if err != nil {
 		h.logAndSendError(w, "could not parse body", reqInfo, err)
 		return
@@ -734,7 +781,7 @@ func (h *handler) CreateBucketHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	if useDefaultPolicy {
-		p.Policy = h.cfg.DefaultPolicy
+		p.Policy = h.config().DefaultPolicy
 	}
 
 	p.ObjectLockEnabled = isLockEnabled(r.Header)
@@ -759,6 +806,13 @@ func (h *handler) CreateBucketHandler(w http.ResponseWriter, r *http.Request) {
 
 	h.log.Info("bucket is created", zap.Stringer("container_id", bktInfo.CID))
 
+	h.audit(r, nil, &bucketAuditInfo{
+		ContainerID:        bktInfo.CID.EncodeToString(),
+		LocationConstraint: bktInfo.LocationConstraint,
+		ObjectLockEnabled:  bktInfo.ObjectLockEnabled,
+		ACL:                bktACL,
+	})
+
 	api.WriteSuccessResponseHeadersOnly(w)
 }
 
@@ -802,14 +856,14 @@ func isAlphaNum(char int32) bool {
 	return 'a' <= char && char <= 'z' || '0' <= char && char <= '9'
 }
 
-func parseLocationConstraint(r *http.Request) (*createBucketParams, error) {
+func (h *handler) parseLocationConstraint(r *http.Request) (*createBucketParams, error) {
 	if r.ContentLength == 0 {
 		return new(createBucketParams), nil
 	}
 
 	params := new(createBucketParams)
-	if err := xml.NewDecoder(r.Body).Decode(params); err != nil {
-		return nil, errors.GetAPIError(errors.ErrMalformedXML)
+	if err := h.decodeXML(r, params, errors.ErrMalformedXML); err != nil {
+		return nil, err
 	}
 	return params, nil
 }
@@ -126,3 +126,121 @@ func TestPutObjectOverrideCopiesNumber(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, "1", objInfo.Headers[layer.AttributeNeofsCopiesNumber])
 }
+
+func TestPutObjectWithConditions(t *testing.T) {
+	tc := prepareHandlerContext(t)
+
+	bktName, objName := "bucket-for-conditions", "object-for-conditions"
+	bktInfo := createTestBucket(tc, bktName)
+
+	w, r := prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.IfMatch, layer.AnyETag)
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusPreconditionFailed)
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.IfNoneMatch, layer.AnyETag)
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	etag := w.Header().Get(api.ETag)
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content2"))
+	r.Header.Set(api.IfNoneMatch, layer.AnyETag)
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusPreconditionFailed)
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content2"))
+	r.Header.Set(api.IfMatch, "wrong-etag")
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusPreconditionFailed)
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content2"))
+	r.Header.Set(api.IfMatch, etag)
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.NotEqual(t, etag, w.Header().Get(api.ETag))
+
+	require.Len(t, tc.MockedPool().AllObjects(bktInfo.CID), 2)
+}
+
+func TestGetStorageClassAndCopiesNumber(t *testing.T) {
+	h := &handler{cfg: &Config{
+		CopiesNumber:   2,
+		StorageClasses: map[string]uint32{"REDUCED_REDUNDANCY": 1, "ARCHIVE": 0},
+	}}
+
+	for _, tc := range []struct {
+		name         string
+		header       string
+		metadata     map[string]string
+		storageClass string
+		copiesNumber uint32
+		err          bool
+	}{
+		{name: "default", copiesNumber: 2},
+		{name: "standard", header: StandardStorageClass, copiesNumber: 2},
+		{name: "configured", header: "REDUCED_REDUNDANCY", storageClass: "REDUCED_REDUNDANCY", copiesNumber: 1},
+		{name: "configured without copies number", header: "ARCHIVE", storageClass: "ARCHIVE", copiesNumber: 2},
+		{
+			name:         "override copies number",
+			header:       "REDUCED_REDUNDANCY",
+			metadata:     map[string]string{layer.AttributeNeofsCopiesNumber: "3"},
+			storageClass: "REDUCED_REDUNDANCY",
+			copiesNumber: 3,
+		},
+		{name: "unknown", header: "GLACIER", err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			headers := make(http.Header)
+			if len(tc.header) > 0 {
+				headers.Set(api.AmzStorageClass, tc.header)
+			}
+
+			storageClass, copiesNumber, err := h.getStorageClassAndCopiesNumber(headers, tc.metadata)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.storageClass, storageClass)
+			require.Equal(t, tc.copiesNumber, copiesNumber)
+		})
+	}
+}
+
+func TestPutObjectWithStorageClass(t *testing.T) {
+	tc := prepareHandlerContext(t)
+	tc.Handler().cfg.StorageClasses = map[string]uint32{"REDUCED_REDUNDANCY": 1}
+
+	bktName, objName, objName2 := "bucket-for-storage-class", "object-rr", "object-standard"
+	createTestBucket(tc, bktName)
+
+	w, r := prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.AmzStorageClass, "GLACIER")
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusBadRequest)
+
+	w, r = prepareTestPayloadRequest(tc, bktName, objName, strings.NewReader("content"))
+	r.Header.Set(api.AmzStorageClass, "REDUCED_REDUNDANCY")
+	tc.Handler().PutObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+
+	putObject(t, tc, bktName, objName2)
+
+	w, r = prepareTestRequest(tc, bktName, objName, nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Equal(t, "REDUCED_REDUNDANCY", w.Header().Get(api.AmzStorageClass))
+
+	w, r = prepareTestRequest(tc, bktName, objName2, nil)
+	tc.Handler().HeadObjectHandler(w, r)
+	assertStatus(t, w, http.StatusOK)
+	require.Empty(t, w.Header().Get(api.AmzStorageClass))
+
+	list := listObjectsV1(t, tc, bktName, "", "", "", -1)
+	require.Len(t, list.Contents, 2)
+	require.Equal(t, objName, list.Contents[0].Key)
+	require.Equal(t, "REDUCED_REDUNDANCY", list.Contents[0].StorageClass)
+	require.Equal(t, objName2, list.Contents[1].Key)
+	require.Equal(t, StandardStorageClass, list.Contents[1].StorageClass)
+}
@@ -70,7 +70,7 @@ type Grant struct {
 
 // Grantee is info about access rights of some actor.
 type Grantee struct {
-	XMLName      xml.Name    `xml:"Grantee"`
+	XMLName      xml.Name    `xml:"Grantee" json:"-"`
 	XMLNS        string      `xml:"xmlns:xsi,attr"`
 	ID           string      `xml:"ID,omitempty"`
 	DisplayName  string      `xml:"DisplayName,omitempty"`
@@ -121,7 +121,7 @@ type ObjectVersionResponse struct {
 	LastModified string `xml:"LastModified"`
 	Owner        Owner  `xml:"Owner"`
 	Size         int64  `xml:"Size"`
-	StorageClass string `xml:"StorageClass,omitempty"` // is empty!!
+	StorageClass string `xml:"StorageClass,omitempty"`
 	VersionID    string `xml:"VersionId"`
 }
 
@@ -167,7 +167,7 @@ type ListObjectsVersionsResponse struct {
 
 // VersioningConfiguration contains VersioningConfiguration XML representation.
 type VersioningConfiguration struct {
-	XMLName   xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration"`
+	XMLName   xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ VersioningConfiguration" json:"-"`
 	Status    string   `xml:"Status,omitempty"`
 	MfaDelete string   `xml:"MfaDelete,omitempty"`
 }
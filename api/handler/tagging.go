@@ -26,7 +26,7 @@ const (
 func (h *handler) PutObjectTaggingHandler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
 
-	tagSet, err := readTagSet(r.Body)
+	tagSet, err := readTagSet(limitBody(r, h.config().Limits.MaxBodySize, errors.ErrMaxMessageLengthExceeded))
 	if err != nil {
 		h.logAndSendError(w, "could not read tag set", reqInfo, err)
 		return
@@ -149,7 +149,7 @@ func (h *handler) DeleteObjectTaggingHandler(w http.ResponseWriter, r *http.Requ
 func (h *handler) PutBucketTaggingHandler(w http.ResponseWriter, r *http.Request) {
 	reqInfo := api.GetReqInfo(r.Context())
 
-	tagSet, err := readTagSet(r.Body)
+	tagSet, err := readTagSet(limitBody(r, h.config().Limits.MaxBodySize, errors.ErrMaxMessageLengthExceeded))
 	if err != nil {
 		h.logAndSendError(w, "could not read tag set", reqInfo, err)
 		return
@@ -207,6 +207,9 @@ func (h *handler) DeleteBucketTaggingHandler(w http.ResponseWriter, r *http.Requ
 func readTagSet(reader io.Reader) (map[string]string, error) {
 	tagging := new(Tagging)
 	if err := xml.NewDecoder(reader).Decode(tagging); err != nil {
+		if tooLarge, ok := err.(bodyTooLargeError); ok {
+			return nil, tooLarge.err
+		}
 		return nil, errors.GetAPIError(errors.ErrMalformedXML)
 	}
 
@@ -34,6 +34,11 @@ func transformToS3Error(err error) error {
 		return err
 	}
 
+	var tooLarge bodyTooLargeError
+	if errorsStd.As(err, &tooLarge) {
+		return tooLarge.err
+	}
+
 	if errorsStd.Is(err, layer.ErrAccessDenied) ||
 		errorsStd.Is(err, layer.ErrNodeAccessDenied) {
 		return errors.GetAPIError(errors.ErrAccessDenied)
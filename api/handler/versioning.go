@@ -1,7 +1,6 @@
 package handler
 
 import (
-	"encoding/xml"
 	"net/http"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api"
@@ -14,8 +13,8 @@ func (h *handler) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Requ
 	reqInfo := api.GetReqInfo(r.Context())
 
 	configuration := new(VersioningConfiguration)
-	if err := xml.NewDecoder(r.Body).Decode(configuration); err != nil {
-		h.logAndSendError(w, "couldn't decode versioning configuration", reqInfo, errors.GetAPIError(errors.ErrIllegalVersioningConfigurationException))
+	if err := h.decodeXML(r, configuration, errors.ErrIllegalVersioningConfigurationException); err != nil {
+		h.logAndSendError(w, "couldn't decode versioning configuration", reqInfo, err)
 		return
 	}
 
@@ -52,7 +51,10 @@ func (h *handler) PutBucketVersioningHandler(w http.ResponseWriter, r *http.Requ
 
 	if err = h.obj.PutBucketSettings(r.Context(), p); err != nil {
 		h.logAndSendError(w, "couldn't put update versioning settings", reqInfo, err)
+		return
 	}
+
+	h.audit(r, formVersioningConfiguration(settings), configuration)
 }
 
 // GetBucketVersioningHandler implements bucket versioning getter handler.
@@ -1,16 +1,55 @@
 package layer
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/tracing"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"go.uber.org/zap"
 )
 
+// Kinds of the layer caches, they match names of the cache configuration sections.
+const (
+	CacheObjects       = "objects"
+	CacheList          = "list"
+	CacheNames         = "names"
+	CacheBuckets       = "buckets"
+	CacheSystem        = "system"
+	CacheAccessControl = "accesscontrol"
+)
+
+// ErrUnknownCacheKind is returned when the cache of the requested kind doesn't exist.
+var ErrUnknownCacheKind = errors.New("unknown cache kind")
+
+// CacheStat contains statistics of the cache of the kind.
+type CacheStat struct {
+	Kind string
+	cache.Stats
+}
+
 type Cache struct {
-	logger      *zap.Logger
+	logger *zap.Logger
+
+	// mu protects caches and their configs replaced on configuration reload.
+	mu  sync.RWMutex
+	set *cacheSet
+	cfg *CachesConfig
+
+	// bus delivers invalidation events to caches of other gateway instances,
+	// events are marked with source to ignore own ones.
+	bus    cache.InvalidationBus
+	source string
+}
+
+type cacheSet struct {
 	listsCache  *cache.ObjectsListCache
 	objCache    *cache.ObjectsCache
 	namesCache  *cache.ObjectsNameCache
@@ -28,6 +67,8 @@ type CachesConfig struct {
 	Buckets       *cache.Config
 	System        *cache.Config
 	AccessControl *cache.Config
+	// Invalidation is an optional bus to share cache invalidation events with other gateway instances.
+	Invalidation cache.InvalidationBus
 }
 
 // DefaultCachesConfigs returns filled configs.
@@ -44,23 +85,143 @@ func DefaultCachesConfigs(logger *zap.Logger) *CachesConfig {
 }
 
 func NewCache(cfg *CachesConfig) *Cache {
-	return &Cache{
-		logger:      cfg.Logger,
-		listsCache:  cache.NewObjectsListCache(cfg.ObjectsList),
-		objCache:    cache.New(cfg.Objects),
-		namesCache:  cache.NewObjectsNameCache(cfg.Names),
-		bucketCache: cache.NewBucketCache(cfg.Buckets),
-		systemCache: cache.NewSystemCache(cfg.System),
-		accessCache: cache.NewAccessControlCache(cfg.AccessControl),
+	c := &Cache{
+		logger: cfg.Logger,
+		set: &cacheSet{
+			listsCache:  cache.NewObjectsListCache(cfg.ObjectsList),
+			objCache:    cache.New(cfg.Objects),
+			namesCache:  cache.NewObjectsNameCache(cfg.Names),
+			bucketCache: cache.NewBucketCache(cfg.Buckets),
+			systemCache: cache.NewSystemCache(cfg.System),
+			accessCache: cache.NewAccessControlCache(cfg.AccessControl),
+		},
+		cfg:    cfg,
+		bus:    cfg.Invalidation,
+		source: uuid.NewString(),
+	}
+
+	if c.bus != nil {
+		if err := c.bus.Subscribe(c.handleInvalidation); err != nil {
+			c.logger.Error("couldn't subscribe to cache invalidation events", zap.Error(err))
+		}
+	}
+
+	return c
+}
+
+func (c *Cache) load() *cacheSet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.set
+}
+
+// Update recreates caches which size or lifetime are changed, entries of these caches are lost.
+// The invalidation bus can't be changed.
+func (c *Cache) Update(cfg *CachesConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	set := *c.set
+	if configChanged(c.cfg.ObjectsList, cfg.ObjectsList) {
+		set.listsCache = cache.NewObjectsListCache(cfg.ObjectsList)
+	}
+	if configChanged(c.cfg.Objects, cfg.Objects) {
+		set.objCache = cache.New(cfg.Objects)
+	}
+	if configChanged(c.cfg.Names, cfg.Names) {
+		set.namesCache = cache.NewObjectsNameCache(cfg.Names)
+	}
+	if configChanged(c.cfg.Buckets, cfg.Buckets) {
+		set.bucketCache = cache.NewBucketCache(cfg.Buckets)
+	}
+	if configChanged(c.cfg.System, cfg.System) {
+		set.systemCache = cache.NewSystemCache(cfg.System)
+	}
+	if configChanged(c.cfg.AccessControl, cfg.AccessControl) {
+		set.accessCache = cache.NewAccessControlCache(cfg.AccessControl)
+	}
+
+	cfg.Invalidation = c.cfg.Invalidation
+	c.set, c.cfg = &set, cfg
+}
+
+func configChanged(prev, cur *cache.Config) bool {
+	return prev.Size != cur.Size || prev.Lifetime != cur.Lifetime
+}
+
+func settingsCacheKey(bktInfo *data.BucketInfo) string {
+	return bktInfo.Name + bktInfo.SettingsObjectName()
+}
+
+func corsCacheKey(bktInfo *data.BucketInfo) string {
+	return bktInfo.Name + bktInfo.CORSObjectName()
+}
+
+func notificationConfigurationCacheKey(bktInfo *data.BucketInfo) string {
+	return bktInfo.Name + bktInfo.NotificationConfigurationObjectName()
+}
+
+// publish sends the invalidation event to other gateway instances if the bus is set.
+func (c *Cache) publish(e cache.InvalidationEvent) {
+	if c.bus == nil {
+		return
+	}
+
+	e.Source = c.source
+	if err := c.bus.Publish(e); err != nil {
+		c.logger.Warn("couldn't publish cache invalidation event", zap.String("kind", string(e.Kind)),
+			zap.String("bucket", e.Bucket), zap.String("key", e.Key), zap.Error(err))
+	}
+}
+
+// publishSystem notifies other gateway instances that the system cache entry has been changed
+// by this instance, so they evict it.
+func (c *Cache) publishSystem(key string) {
+	c.publish(cache.InvalidationEvent{Kind: cache.InvalidateSystem, Key: key})
+}
+
+// handleInvalidation evicts entries changed by other gateway instances.
+func (c *Cache) handleInvalidation(e cache.InvalidationEvent) {
+	if e.Source == c.source {
+		return
+	}
+
+	switch e.Kind {
+	case cache.InvalidateBucket:
+		c.load().bucketCache.Delete(e.Bucket)
+	case cache.InvalidateList, cache.InvalidateName:
+		var cnrID cid.ID
+		if err := cnrID.DecodeString(e.CID); err != nil {
+			c.logger.Warn("invalid container id in cache invalidation event", zap.String("cid", e.CID), zap.Error(err))
+			return
+		}
+		if e.Kind == cache.InvalidateName {
+			c.load().namesCache.Delete(e.Bucket + "/" + e.Key)
+		}
+		c.load().listsCache.CleanCacheEntriesContainingObject(e.Key, cnrID)
+	case cache.InvalidateObject:
+		var addr oid.Address
+		if err := addr.DecodeString(e.Key); err != nil {
+			c.logger.Warn("invalid object address in cache invalidation event", zap.String("address", e.Key), zap.Error(err))
+			return
+		}
+		c.load().objCache.Delete(addr)
+	case cache.InvalidateSystem:
+		c.load().systemCache.Delete(e.Key)
+	default:
+		c.logger.Warn("unknown cache invalidation event", zap.String("kind", string(e.Kind)))
 	}
 }
 
-func (c *Cache) GetBucket(name string) *data.BucketInfo {
-	return c.bucketCache.Get(name)
+func (c *Cache) GetBucket(ctx context.Context, name string) *data.BucketInfo {
+	bktInfo := c.load().bucketCache.Get(name)
+	tracing.TraceCacheLookup(ctx, CacheBuckets, bktInfo != nil)
+
+	return bktInfo
 }
 
 func (c *Cache) PutBucket(bktInfo *data.BucketInfo) {
-	if err := c.bucketCache.Put(bktInfo); err != nil {
+	if err := c.load().bucketCache.Put(bktInfo); err != nil {
 		c.logger.Warn("couldn't put bucket info into cache",
 			zap.String("bucket name", bktInfo.Name),
 			zap.Stringer("bucket cid", bktInfo.CID),
@@ -69,47 +230,54 @@ func (c *Cache) PutBucket(bktInfo *data.BucketInfo) {
 }
 
 func (c *Cache) DeleteBucket(name string) {
-	c.bucketCache.Delete(name)
+	c.load().bucketCache.Delete(name)
+	c.publish(cache.InvalidationEvent{Kind: cache.InvalidateBucket, Bucket: name})
 }
 
 func (c *Cache) CleanListCacheEntriesContainingObject(objectName string, cnrID cid.ID) {
-	c.listsCache.CleanCacheEntriesContainingObject(objectName, cnrID)
+	c.load().listsCache.CleanCacheEntriesContainingObject(objectName, cnrID)
+	c.publish(cache.InvalidationEvent{Kind: cache.InvalidateList, CID: cnrID.EncodeToString(), Key: objectName})
 }
 
 func (c *Cache) DeleteObjectName(cnrID cid.ID, bktName, objName string) {
-	c.namesCache.Delete(bktName + "/" + objName)
-	c.listsCache.CleanCacheEntriesContainingObject(objName, cnrID)
+	c.load().namesCache.Delete(bktName + "/" + objName)
+	c.load().listsCache.CleanCacheEntriesContainingObject(objName, cnrID)
+	c.publish(cache.InvalidationEvent{Kind: cache.InvalidateName, Bucket: bktName, CID: cnrID.EncodeToString(), Key: objName})
 }
 
 func (c *Cache) DeleteObject(addr oid.Address) {
-	c.objCache.Delete(addr)
+	c.load().objCache.Delete(addr)
+	c.publish(cache.InvalidationEvent{Kind: cache.InvalidateObject, Key: addr.EncodeToString()})
 }
 
-func (c *Cache) GetObject(owner user.ID, addr oid.Address) *data.ExtendedObjectInfo {
-	if !c.accessCache.Get(owner, addr.String()) {
+func (c *Cache) GetObject(ctx context.Context, owner user.ID, addr oid.Address) (extObjInfo *data.ExtendedObjectInfo) {
+	defer func() { tracing.TraceCacheLookup(ctx, CacheObjects, extObjInfo != nil) }()
+
+	if !c.load().accessCache.Get(owner, addr.String()) {
 		return nil
 	}
 
-	return c.objCache.GetObject(addr)
+	return c.load().objCache.GetObject(addr)
 }
 
-func (c *Cache) GetLastObject(owner user.ID, bktName, objName string) *data.ExtendedObjectInfo {
-	addr := c.namesCache.Get(bktName + "/" + objName)
+func (c *Cache) GetLastObject(ctx context.Context, owner user.ID, bktName, objName string) *data.ExtendedObjectInfo {
+	addr := c.load().namesCache.Get(bktName + "/" + objName)
+	tracing.TraceCacheLookup(ctx, CacheNames, addr != nil)
 	if addr == nil {
 		return nil
 	}
 
-	return c.GetObject(owner, *addr)
+	return c.GetObject(ctx, owner, *addr)
 }
 
 func (c *Cache) PutObject(owner user.ID, extObjInfo *data.ExtendedObjectInfo) {
-	if err := c.objCache.PutObject(extObjInfo); err != nil {
+	if err := c.load().objCache.PutObject(extObjInfo); err != nil {
 		c.logger.Warn("couldn't add object to cache", zap.Error(err),
 			zap.String("object_name", extObjInfo.ObjectInfo.Name), zap.String("bucket_name", extObjInfo.ObjectInfo.Bucket),
 			zap.String("cid", extObjInfo.ObjectInfo.CID.EncodeToString()), zap.String("oid", extObjInfo.ObjectInfo.ID.EncodeToString()))
 	}
 
-	if err := c.accessCache.Put(owner, extObjInfo.ObjectInfo.Address().EncodeToString()); err != nil {
+	if err := c.load().accessCache.Put(owner, extObjInfo.ObjectInfo.Address().EncodeToString()); err != nil {
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
@@ -117,135 +285,193 @@ func (c *Cache) PutObject(owner user.ID, extObjInfo *data.ExtendedObjectInfo) {
 func (c *Cache) PutObjectWithName(owner user.ID, extObjInfo *data.ExtendedObjectInfo) {
 	c.PutObject(owner, extObjInfo)
 
-	if err := c.namesCache.Put(extObjInfo.ObjectInfo.NiceName(), extObjInfo.ObjectInfo.Address()); err != nil {
+	if err := c.load().namesCache.Put(extObjInfo.ObjectInfo.NiceName(), extObjInfo.ObjectInfo.Address()); err != nil {
 		c.logger.Warn("couldn't put obj address to name cache",
 			zap.String("obj nice name", extObjInfo.ObjectInfo.NiceName()),
 			zap.Error(err))
 	}
 }
 
-func (c *Cache) GetList(owner user.ID, key cache.ObjectsListKey) []*data.NodeVersion {
-	if !c.accessCache.Get(owner, key.String()) {
+func (c *Cache) GetList(ctx context.Context, owner user.ID, key cache.ObjectsListKey) (versions []*data.NodeVersion) {
+	defer func() { tracing.TraceCacheLookup(ctx, CacheList, versions != nil) }()
+
+	if !c.load().accessCache.Get(owner, key.String()) {
 		return nil
 	}
 
-	return c.listsCache.GetVersions(key)
+	return c.load().listsCache.GetVersions(key)
 }
 
 func (c *Cache) PutList(owner user.ID, key cache.ObjectsListKey, list []*data.NodeVersion) {
-	if err := c.listsCache.PutVersions(key, list); err != nil {
+	if err := c.load().listsCache.PutVersions(key, list); err != nil {
 		c.logger.Warn("couldn't cache list of objects", zap.Error(err))
 	}
 
-	if err := c.accessCache.Put(owner, key.String()); err != nil {
+	if err := c.load().accessCache.Put(owner, key.String()); err != nil {
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
 
-func (c *Cache) GetTagging(owner user.ID, key string) map[string]string {
-	if !c.accessCache.Get(owner, key) {
+func (c *Cache) GetTagging(ctx context.Context, owner user.ID, key string) (tags map[string]string) {
+	defer func() { tracing.TraceCacheLookup(ctx, CacheSystem, tags != nil) }()
+
+	if !c.load().accessCache.Get(owner, key) {
 		return nil
 	}
 
-	return c.systemCache.GetTagging(key)
+	return c.load().systemCache.GetTagging(key)
 }
 
 func (c *Cache) PutTagging(owner user.ID, key string, tags map[string]string) {
-	if err := c.systemCache.PutTagging(key, tags); err != nil {
+	if err := c.load().systemCache.PutTagging(key, tags); err != nil {
 		c.logger.Error("couldn't cache tags", zap.Error(err))
 	}
 
-	if err := c.accessCache.Put(owner, key); err != nil {
+	if err := c.load().accessCache.Put(owner, key); err != nil {
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
 
 func (c *Cache) DeleteTagging(key string) {
-	c.systemCache.Delete(key)
+	c.load().systemCache.Delete(key)
+	c.publishSystem(key)
 }
 
-func (c *Cache) GetLockInfo(owner user.ID, key string) *data.LockInfo {
-	if !c.accessCache.Get(owner, key) {
+func (c *Cache) GetLockInfo(ctx context.Context, owner user.ID, key string) (lockInfo *data.LockInfo) {
+	defer func() { tracing.TraceCacheLookup(ctx, CacheSystem, lockInfo != nil) }()
+
+	if !c.load().accessCache.Get(owner, key) {
 		return nil
 	}
 
-	return c.systemCache.GetLockInfo(key)
+	return c.load().systemCache.GetLockInfo(key)
 }
 
 func (c *Cache) PutLockInfo(owner user.ID, key string, lockInfo *data.LockInfo) {
-	if err := c.systemCache.PutLockInfo(key, lockInfo); err != nil {
+	if err := c.load().systemCache.PutLockInfo(key, lockInfo); err != nil {
 		c.logger.Error("couldn't cache lock info", zap.Error(err))
 	}
 
-	if err := c.accessCache.Put(owner, key); err != nil {
+	if err := c.load().accessCache.Put(owner, key); err != nil {
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
 
-func (c *Cache) GetSettings(owner user.ID, bktInfo *data.BucketInfo) *data.BucketSettings {
-	key := bktInfo.Name + bktInfo.SettingsObjectName()
+func (c *Cache) GetSettings(ctx context.Context, owner user.ID, bktInfo *data.BucketInfo) (settings *data.BucketSettings) {
+	defer func() { tracing.TraceCacheLookup(ctx, CacheSystem, settings != nil) }()
 
-	if !c.accessCache.Get(owner, key) {
+	key := settingsCacheKey(bktInfo)
+
+	if !c.load().accessCache.Get(owner, key) {
 		return nil
 	}
 
-	return c.systemCache.GetSettings(key)
+	return c.load().systemCache.GetSettings(key)
 }
 
 func (c *Cache) PutSettings(owner user.ID, bktInfo *data.BucketInfo, settings *data.BucketSettings) {
-	key := bktInfo.Name + bktInfo.SettingsObjectName()
-	if err := c.systemCache.PutSettings(key, settings); err != nil {
+	key := settingsCacheKey(bktInfo)
+	if err := c.load().systemCache.PutSettings(key, settings); err != nil {
 		c.logger.Warn("couldn't cache bucket settings", zap.String("bucket", bktInfo.Name), zap.Error(err))
 	}
 
-	if err := c.accessCache.Put(owner, key); err != nil {
+	if err := c.load().accessCache.Put(owner, key); err != nil {
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
 
-func (c *Cache) GetCORS(owner user.ID, bkt *data.BucketInfo) *data.CORSConfiguration {
-	key := bkt.Name + bkt.CORSObjectName()
+func (c *Cache) GetCORS(ctx context.Context, owner user.ID, bkt *data.BucketInfo) (cors *data.CORSConfiguration) {
+	defer func() { tracing.TraceCacheLookup(ctx, CacheSystem, cors != nil) }()
+
+	key := corsCacheKey(bkt)
 
-	if !c.accessCache.Get(owner, key) {
+	if !c.load().accessCache.Get(owner, key) {
 		return nil
 	}
 
-	return c.systemCache.GetCORS(key)
+	return c.load().systemCache.GetCORS(key)
 }
 
 func (c *Cache) PutCORS(owner user.ID, bkt *data.BucketInfo, cors *data.CORSConfiguration) {
-	key := bkt.Name + bkt.CORSObjectName()
+	key := corsCacheKey(bkt)
 
-	if err := c.systemCache.PutCORS(key, cors); err != nil {
+	if err := c.load().systemCache.PutCORS(key, cors); err != nil {
 		c.logger.Warn("couldn't cache cors", zap.String("bucket", bkt.Name), zap.Error(err))
 	}
 
-	if err := c.accessCache.Put(owner, key); err != nil {
+	if err := c.load().accessCache.Put(owner, key); err != nil {
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
 
 func (c *Cache) DeleteCORS(bktInfo *data.BucketInfo) {
-	c.systemCache.Delete(bktInfo.Name + bktInfo.CORSObjectName())
+	c.load().systemCache.Delete(corsCacheKey(bktInfo))
+	c.publishSystem(corsCacheKey(bktInfo))
 }
 
-func (c *Cache) GetNotificationConfiguration(owner user.ID, bktInfo *data.BucketInfo) *data.NotificationConfiguration {
-	key := bktInfo.Name + bktInfo.NotificationConfigurationObjectName()
+func (c *Cache) GetNotificationConfiguration(ctx context.Context, owner user.ID, bktInfo *data.BucketInfo) (conf *data.NotificationConfiguration) {
+	defer func() { tracing.TraceCacheLookup(ctx, CacheSystem, conf != nil) }()
 
-	if !c.accessCache.Get(owner, key) {
+	key := notificationConfigurationCacheKey(bktInfo)
+
+	if !c.load().accessCache.Get(owner, key) {
 		return nil
 	}
 
-	return c.systemCache.GetNotificationConfiguration(key)
+	return c.load().systemCache.GetNotificationConfiguration(key)
 }
 
 func (c *Cache) PutNotificationConfiguration(owner user.ID, bktInfo *data.BucketInfo, configuration *data.NotificationConfiguration) {
-	key := bktInfo.Name + bktInfo.NotificationConfigurationObjectName()
-	if err := c.systemCache.PutNotificationConfiguration(key, configuration); err != nil {
+	key := notificationConfigurationCacheKey(bktInfo)
+	if err := c.load().systemCache.PutNotificationConfiguration(key, configuration); err != nil {
 		c.logger.Warn("couldn't cache notification configuration", zap.String("bucket", bktInfo.Name), zap.Error(err))
 	}
 
-	if err := c.accessCache.Put(owner, key); err != nil {
+	if err := c.load().accessCache.Put(owner, key); err != nil {
 		c.logger.Warn("couldn't cache access control operation", zap.Error(err))
 	}
 }
+
+type statsCache interface {
+	Stats() cache.Stats
+	Purge()
+}
+
+func (c *Cache) caches() []struct {
+	kind  string
+	cache statsCache
+} {
+	set := c.load()
+	return []struct {
+		kind  string
+		cache statsCache
+	}{
+		{CacheObjects, set.objCache},
+		{CacheList, set.listsCache},
+		{CacheNames, set.namesCache},
+		{CacheBuckets, set.bucketCache},
+		{CacheSystem, set.systemCache},
+		{CacheAccessControl, set.accessCache},
+	}
+}
+
+// Stats returns statistics of all caches.
+func (c *Cache) Stats() []CacheStat {
+	caches := c.caches()
+	res := make([]CacheStat, len(caches))
+	for i, item := range caches {
+		res[i] = CacheStat{Kind: item.kind, Stats: item.cache.Stats()}
+	}
+	return res
+}
+
+// Purge removes all entries from the cache of the kind.
+func (c *Cache) Purge(kind string) error {
+	for _, item := range c.caches() {
+		if item.kind == kind {
+			item.cache.Purge()
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownCacheKind, kind)
+}
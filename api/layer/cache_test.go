@@ -0,0 +1,144 @@
+package layer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCacheInvalidation(t *testing.T) {
+	tc := prepareContext(t)
+	bus := cache.NewMemoryBus()
+
+	newInstance := func() Client {
+		cfg := DefaultCachesConfigs(zap.NewNop())
+		cfg.Invalidation = bus
+		return NewLayer(zap.NewNop(), tc.testNeoFS, &Config{
+			Caches:      cfg,
+			AnonKey:     tc.layer.(*layer).anonKey,
+			TreeService: tc.layer.(*layer).treeService,
+		})
+	}
+	gw1, gw2 := newInstance(), newInstance()
+
+	putObject := func(content string) *data.ObjectInfo {
+		extObjInfo, err := gw1.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  tc.obj,
+			Size:    int64(len(content)),
+			Reader:  bytes.NewReader([]byte(content)),
+			Header:  make(map[string]string),
+		})
+		require.NoError(t, err)
+		return extObjInfo.ObjectInfo
+	}
+	headObject := func() *data.ObjectInfo {
+		objInfo, err := gw2.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: tc.bktInfo, Object: tc.obj})
+		require.NoError(t, err)
+		return objInfo
+	}
+	listObjects := func() []*data.ObjectInfo {
+		res, err := gw2.ListObjectsV2(tc.ctx, &ListObjectsParamsV2{
+			ListObjectsParamsCommon: ListObjectsParamsCommon{BktInfo: tc.bktInfo, MaxKeys: 1000},
+		})
+		require.NoError(t, err)
+		return res.Objects
+	}
+
+	objInfo := putObject("content v1")
+	require.Equal(t, objInfo.ID, headObject().ID)
+	require.Len(t, listObjects(), 1)
+
+	// the second instance gets the new version instead of the cached one
+	objInfo = putObject("content v2")
+	require.Equal(t, objInfo.ID, headObject().ID)
+	objects := listObjects()
+	require.Len(t, objects, 1)
+	require.Equal(t, objInfo.ID, objects[0].ID)
+
+	settings, err := gw2.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.False(t, settings.VersioningEnabled())
+
+	err = gw1.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	settings, err = gw2.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.True(t, settings.VersioningEnabled())
+
+	tc.obj = "obj2"
+	objInfo = putObject("content")
+	require.Len(t, listObjects(), 2)
+
+	gw1.DeleteObjects(tc.ctx, &DeleteObjectParams{
+		BktInfo:  tc.bktInfo,
+		Objects:  []*VersionedObject{{Name: tc.obj, VersionID: objInfo.VersionID()}},
+		Settings: settings,
+	})
+	require.Len(t, listObjects(), 1)
+}
+
+func TestCacheStatsAndFlush(t *testing.T) {
+	tc := prepareContext(t)
+
+	_, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  tc.obj,
+		Reader:  bytes.NewReader(nil),
+		Header:  make(map[string]string),
+	})
+	require.NoError(t, err)
+
+	getStat := func(kind string) CacheStat {
+		for _, stat := range tc.layer.CacheStats() {
+			if stat.Kind == kind {
+				return stat
+			}
+		}
+		t.Fatalf("no stats of the cache %s", kind)
+		return CacheStat{}
+	}
+
+	require.Len(t, tc.layer.CacheStats(), 6)
+	require.Equal(t, 1, getStat(CacheObjects).Size)
+
+	require.NoError(t, tc.layer.FlushCache(CacheObjects))
+	require.Zero(t, getStat(CacheObjects).Size)
+
+	require.ErrorIs(t, tc.layer.FlushCache("unknown"), ErrUnknownCacheKind)
+}
+
+func TestCacheUpdate(t *testing.T) {
+	tc := prepareContext(t)
+	n := tc.layer.(*layer)
+
+	_, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  tc.obj,
+		Reader:  bytes.NewReader(nil),
+		Header:  make(map[string]string),
+	})
+	require.NoError(t, err)
+
+	objCache, systemCache := n.cache.load().objCache, n.cache.load().systemCache
+
+	// caches with unchanged config are kept
+	cfg := DefaultCachesConfigs(zap.NewNop())
+	cfg.Objects.Size *= 2
+	tc.layer.UpdateCaches(cfg)
+
+	require.NotSame(t, objCache, n.cache.load().objCache)
+	require.Same(t, systemCache, n.cache.load().systemCache)
+	require.Zero(t, n.cache.load().objCache.Stats().Size)
+
+	tc.layer.UpdateCaches(DefaultCachesConfigs(zap.NewNop()))
+	require.Same(t, systemCache, n.cache.load().systemCache)
+}
@@ -0,0 +1,55 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// completionTimeout is a timeout of the tree service updates made after the payload
+// object is stored.
+const completionTimeout = 10 * time.Second
+
+// detachedContext keeps values of the parent context, but isn't canceled with it.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// completionContext returns the context to complete the write after the payload object is stored.
+// The request cancellation (e.g. on the gateway shutdown) doesn't interrupt it, so the stored
+// object isn't left without the tree node.
+func completionContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(detachedContext{ctx}, completionTimeout)
+}
+
+// nodeNotWritten checks if the error of the tree service update proves that the tree doesn't reference
+// the new object: the precondition failed before the insertion or the inserted node is already removed.
+func nodeNotWritten(err error) bool {
+	return apiErrors.IsS3Error(err, apiErrors.ErrPreconditionFailed) || errors.Is(err, ErrNoNodeToRemove)
+}
+
+// cleanupObject deletes the stored object if the failed tree update proves that the object isn't
+// referenced by the tree. Otherwise, e.g. if the update timed out or failed after the node had been
+// written, its result is unknown, and the object is kept to be found by the bucket consistency check
+// if it's orphaned.
+func (n *layer) cleanupObject(ctx context.Context, bktInfo *data.BucketInfo, id oid.ID, treeErr error) {
+	if !nodeNotWritten(treeErr) || ctx.Err() != nil {
+		n.log.Warn("object may be left without tree node", zap.String("bucket", bktInfo.Name),
+			zap.Stringer("cid", bktInfo.CID), zap.Stringer("oid", id), zap.Error(treeErr))
+		return
+	}
+
+	if err := n.objectDelete(ctx, bktInfo, id); err != nil {
+		n.log.Error("couldn't delete object of failed write", zap.String("bucket", bktInfo.Name),
+			zap.Stringer("cid", bktInfo.CID), zap.Stringer("oid", id), zap.Error(err))
+	}
+}
@@ -0,0 +1,111 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/stretchr/testify/require"
+)
+
+// cancelingNeoFS cancels the request context right after the object is stored.
+type cancelingNeoFS struct {
+	NeoFS
+	cancel context.CancelFunc
+}
+
+func (n cancelingNeoFS) CreateObject(ctx context.Context, prm PrmObjectCreate) (oid.ID, error) {
+	id, err := n.NeoFS.CreateObject(ctx, prm)
+	n.cancel()
+	return id, err
+}
+
+type failingTreeService struct {
+	TreeService
+	err error
+}
+
+func (f failingTreeService) AddVersionWithTagging(context.Context, *data.BucketInfo, *data.NodeVersion, map[string]string) (uint64, error) {
+	return 0, f.err
+}
+
+func putTestObject(ctx context.Context, tc *testContext) (*data.ExtendedObjectInfo, error) {
+	content := []byte("content")
+	return tc.layer.PutObject(ctx, &PutObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  tc.obj,
+		Size:    int64(len(content)),
+		Reader:  bytes.NewReader(content),
+		Header:  make(map[string]string),
+	})
+}
+
+func TestPutObjectCanceledAfterPayload(t *testing.T) {
+	tc := prepareContext(t)
+	n := tc.layer.(*layer)
+
+	ctx, cancel := context.WithCancel(tc.ctx)
+	n.neoFS = cancelingNeoFS{NeoFS: n.neoFS, cancel: cancel}
+
+	extObjInfo, err := putTestObject(ctx, tc)
+	require.NoError(t, err)
+	require.Error(t, ctx.Err())
+
+	version, err := n.treeService.GetUnversioned(tc.ctx, tc.bktInfo, tc.obj)
+	require.NoError(t, err)
+	require.Equal(t, extObjInfo.ObjectInfo.ID, version.OID)
+}
+
+func TestPutObjectCleanup(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		err     error
+		deleted bool
+		stored  bool
+	}{
+		{name: "unknown result", err: errors.New("tree service is unavailable")},
+		{name: "precondition failed", err: apiErrors.GetAPIError(apiErrors.ErrPreconditionFailed), deleted: true},
+		{name: "node removed", err: fmt.Errorf("rollback: %w", ErrNoNodeToRemove), deleted: true},
+		{name: "stored without tags", err: fmt.Errorf("%w: tagging failed", ErrVersionTagging), stored: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := prepareContext(t)
+			n := ctx.layer.(*layer)
+			n.treeService = failingTreeService{TreeService: n.treeService, err: tc.err}
+
+			_, err := putTestObject(ctx.ctx, ctx)
+			if tc.stored {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+
+			// the object is kept for the bucket consistency check if the tree may reference it
+			if tc.deleted {
+				require.Empty(t, ctx.testNeoFS.Objects())
+			} else {
+				require.Len(t, ctx.testNeoFS.Objects(), 1)
+			}
+		})
+	}
+}
+
+func TestCompletionContext(t *testing.T) {
+	type key struct{}
+
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	ctx, cancelCompletion := completionContext(parent)
+	defer cancelCompletion()
+
+	cancel()
+	require.NoError(t, ctx.Err())
+	require.Equal(t, "value", ctx.Value(key{}))
+
+	_, ok := ctx.Deadline()
+	require.True(t, ok)
+}
@@ -12,8 +12,8 @@ func (n *layer) GetObjectTaggingAndLock(ctx context.Context, objVersion *ObjectV
 	var err error
 	owner := n.Owner(ctx)
 
-	tags := n.cache.GetTagging(owner, objectTaggingCacheKey(objVersion))
-	lockInfo := n.cache.GetLockInfo(owner, lockObjectKey(objVersion))
+	tags := n.cache.GetTagging(ctx, owner, objectTaggingCacheKey(objVersion))
+	lockInfo := n.cache.GetLockInfo(ctx, owner, lockObjectKey(objVersion))
 
 	if tags != nil && lockInfo != nil {
 		return tags, lockInfo, nil
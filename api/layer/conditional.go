@@ -0,0 +1,215 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"go.uber.org/zap"
+)
+
+type (
+	// PutConditions stores conditions that the latest version of the object
+	// must satisfy to be replaced by a new one (If-Match and If-None-Match headers).
+	PutConditions struct {
+		IfMatch     string
+		IfNoneMatch string
+	}
+
+	// objectLocks is a fixed set of mutexes to serialize conditional writes
+	// to the same object within the gateway instance.
+	objectLocks struct {
+		mu [objectLocksCount]sync.Mutex
+	}
+)
+
+const (
+	// AnyETag matches any existing object in If-Match and If-None-Match headers.
+	AnyETag = "*"
+
+	objectLocksCount = 256
+)
+
+func (l *objectLocks) lock(bktInfo *data.BucketInfo, objectName string) func() {
+	h := fnv.New32a()
+	_, _ = h.Write(bktInfo.CID[:])
+	_, _ = h.Write([]byte(objectName))
+
+	mu := &l.mu[h.Sum32()%objectLocksCount]
+	mu.Lock()
+	return mu.Unlock
+}
+
+// Check checks conditions against the latest version of the object.
+// Nil latest version or delete marker means that object doesn't exist.
+func (c *PutConditions) Check(latest *data.NodeVersion) error {
+	exists := latest != nil && !latest.IsDeleteMarker()
+
+	if len(c.IfMatch) > 0 {
+		if !exists || (c.IfMatch != AnyETag && c.IfMatch != latest.ETag) {
+			return apiErrors.GetAPIError(apiErrors.ErrPreconditionFailed)
+		}
+	}
+
+	if len(c.IfNoneMatch) > 0 && exists {
+		if c.IfNoneMatch == AnyETag || c.IfNoneMatch == latest.ETag {
+			return apiErrors.GetAPIError(apiErrors.ErrPreconditionFailed)
+		}
+	}
+
+	return nil
+}
+
+// latestVersionForConditions returns the latest version of the object bypassing caches.
+// If object has no versions nil is returned.
+func (n *layer) latestVersionForConditions(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
+	latest, err := n.treeService.GetLatestVersion(ctx, bktInfo, objectName)
+	if err != nil {
+		if errors.Is(err, ErrNodeNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get latest version: %w", err)
+	}
+
+	return latest, nil
+}
+
+// latestAndUnversioned returns the latest and the unversioned versions of the object bypassing caches
+// by a single tree service request. Nil is returned for missing versions.
+func (n *layer) latestAndUnversioned(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, *data.NodeVersion, error) {
+	latest, unversioned, err := n.treeService.GetLatestAndUnversioned(ctx, bktInfo, objectName)
+	if err != nil {
+		if errors.Is(err, ErrNodeNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("get latest and unversioned versions: %w", err)
+	}
+
+	return latest, unversioned, nil
+}
+
+// checkPutConditions checks conditions before payload is uploaded to avoid unnecessary object creation.
+func (n *layer) checkPutConditions(ctx context.Context, bktInfo *data.BucketInfo, objectName string, cond *PutConditions) error {
+	if cond == nil {
+		return nil
+	}
+
+	latest, err := n.latestVersionForConditions(ctx, bktInfo, objectName)
+	if err != nil {
+		return err
+	}
+
+	return cond.Check(latest)
+}
+
+// addVersionConditionally adds a new version to the tree service only if the latest version
+// satisfies conditions. The check and the insertion are serialized for the same object
+// within the gateway instance. Unversioned node is updated in place, so the node is re-read
+// right before it's replaced, and ErrPreconditionFailed is returned if it isn't the checked one
+// (e.g. it was replaced by another gateway instance). The tree is re-read after the insertion, and
+// if any other version was added between the check and the insertion, the new version is removed and
+// ErrPreconditionFailed is returned. If the unversioned node was replaced by another writer after
+// the insertion, ErrPreconditionFailed is returned without removal.
+// Tags are stored right after the version, the version is removed if it fails.
+// ErrPreconditionFailed is returned only if the tree doesn't reference the new version, so the caller
+// can remove the object from NeoFS. Other errors don't prove it.
+func (n *layer) addVersionConditionally(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion, tagSet map[string]string, cond *PutConditions) (uint64, error) {
+	if cond == nil {
+		nodeID, err := n.treeService.AddVersionWithTagging(ctx, bktInfo, newVersion, tagSet)
+		return nodeID, n.dismissTaggingError(bktInfo, newVersion, err)
+	}
+
+	unlock := n.objectLocks.lock(bktInfo, newVersion.FilePath)
+	defer unlock()
+
+	// the unversioned node is read along with the latest version, so it's the node that existed at the check
+	latest, expected, err := n.latestAndUnversioned(ctx, bktInfo, newVersion.FilePath)
+	if err != nil {
+		return 0, err
+	}
+	if err = cond.Check(latest); err != nil {
+		return 0, err
+	}
+
+	nodeID, err := n.treeService.AddVersionIfUnchanged(ctx, bktInfo, newVersion, tagSet, expected)
+	if err = n.dismissTaggingError(bktInfo, newVersion, err); err != nil {
+		if errors.Is(err, ErrNodeChanged) {
+			return 0, apiErrors.GetAPIError(apiErrors.ErrPreconditionFailed)
+		}
+		return 0, err
+	}
+
+	versions, err := n.treeService.GetVersions(ctx, bktInfo, newVersion.FilePath)
+	if err != nil {
+		return 0, n.rollbackVersion(ctx, bktInfo, nodeID, fmt.Errorf("get versions to check conditions: %w", err))
+	}
+
+	added := findVersion(versions, nodeID)
+	if added == nil || !added.OID.Equals(newVersion.OID) {
+		// our version is already removed or replaced in place by someone else
+		return 0, apiErrors.GetAPIError(apiErrors.ErrPreconditionFailed)
+	}
+
+	if hasConcurrentVersion(versions, latest, added) {
+		return 0, n.rollbackVersion(ctx, bktInfo, nodeID, apiErrors.GetAPIError(apiErrors.ErrPreconditionFailed))
+	}
+
+	return nodeID, nil
+}
+
+// dismissTaggingError logs the failure to store tags of the version that is already stored in the tree.
+// The write took effect and the tree references the new object, so the error isn't returned.
+func (n *layer) dismissTaggingError(bktInfo *data.BucketInfo, version *data.NodeVersion, err error) error {
+	if !errors.Is(err, ErrVersionTagging) {
+		return err
+	}
+
+	n.log.Warn("object version is stored without tags", zap.String("cid", bktInfo.CID.EncodeToString()),
+		zap.String("object", version.FilePath), zap.Stringer("oid", version.OID), zap.Error(err))
+	return nil
+}
+
+// rollbackVersion removes the added version and returns the reason of the removal. If the version can't
+// be removed, the removal error is returned instead, since the tree still references the new object.
+func (n *layer) rollbackVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64, reason error) error {
+	if err := n.treeService.RemoveVersion(ctx, bktInfo, nodeID); err != nil && !errors.Is(err, ErrNoNodeToRemove) {
+		n.log.Error("couldn't remove version of conditional write", zap.Uint64("node id", nodeID),
+			zap.String("cid", bktInfo.CID.EncodeToString()), zap.Error(err))
+		return fmt.Errorf("couldn't remove version of conditional write: %w", err)
+	}
+
+	return reason
+}
+
+func findVersion(versions []*data.NodeVersion, nodeID uint64) *data.NodeVersion {
+	for _, version := range versions {
+		if version.ID == nodeID {
+			return version
+		}
+	}
+
+	return nil
+}
+
+// hasConcurrentVersion checks if there is a version that was added after checked one
+// but before the added one. The checked version may be the same node as the added one
+// if the unversioned node was replaced in place.
+func hasConcurrentVersion(versions []*data.NodeVersion, checked, added *data.NodeVersion) bool {
+	for _, version := range versions {
+		if version.ID == added.ID || (checked != nil && version.ID == checked.ID) {
+			continue
+		}
+		if version.Timestamp > added.Timestamp {
+			continue
+		}
+		if checked == nil || version.Timestamp >= checked.Timestamp {
+			return true
+		}
+	}
+
+	return false
+}
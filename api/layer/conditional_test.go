@@ -0,0 +1,98 @@
+package layer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/stretchr/testify/require"
+)
+
+// racingTreeService adds the version of another writer right before or after the tested one.
+type racingTreeService struct {
+	*TreeServiceMock
+	concurrent *data.NodeVersion
+	after      bool
+}
+
+func (r *racingTreeService) AddVersionIfUnchanged(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion, tagSet map[string]string, expected *data.NodeVersion) (uint64, error) {
+	if !r.after {
+		if _, err := r.TreeServiceMock.AddVersion(ctx, bktInfo, r.concurrent); err != nil {
+			return 0, err
+		}
+	}
+
+	nodeID, err := r.TreeServiceMock.AddVersionIfUnchanged(ctx, bktInfo, newVersion, tagSet, expected)
+	if err != nil {
+		return 0, err
+	}
+
+	if r.after {
+		if _, err = r.TreeServiceMock.AddVersion(ctx, bktInfo, r.concurrent); err != nil {
+			return 0, err
+		}
+	}
+
+	return nodeID, nil
+}
+
+func TestAddVersionConditionallyConcurrent(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		unversioned bool
+		after       bool
+		existing    bool
+	}{
+		{name: "versioned, concurrent version before"},
+		{name: "unversioned, concurrent version before", unversioned: true},
+		{name: "unversioned, replaced after insertion", unversioned: true, after: true},
+		{name: "unversioned, concurrent version before over versioned one", unversioned: true, existing: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := prepareContext(t)
+			n := ctx.layer.(*layer)
+
+			concurrent := &data.NodeVersion{BaseNodeVersion: data.BaseNodeVersion{
+				OID:      oidtest.ID(),
+				FilePath: ctx.obj,
+			}, IsUnversioned: tc.unversioned}
+			n.treeService = &racingTreeService{TreeServiceMock: NewTreeService(), concurrent: concurrent, after: tc.after}
+
+			var cond PutConditions
+			expectedVersions := 1
+			if tc.existing {
+				// the latest version that satisfies If-Match is versioned one
+				existing := &data.NodeVersion{BaseNodeVersion: data.BaseNodeVersion{
+					OID:      oidtest.ID(),
+					FilePath: ctx.obj,
+					ETag:     "etag",
+				}}
+				_, err := n.treeService.AddVersion(ctx.ctx, ctx.bktInfo, existing)
+				require.NoError(t, err)
+				cond.IfMatch = existing.ETag
+				expectedVersions++
+			} else {
+				cond.IfNoneMatch = AnyETag
+			}
+
+			newVersion := &data.NodeVersion{BaseNodeVersion: data.BaseNodeVersion{
+				OID:      oidtest.ID(),
+				FilePath: ctx.obj,
+			}, IsUnversioned: tc.unversioned}
+
+			_, err := n.addVersionConditionally(ctx.ctx, ctx.bktInfo, newVersion, nil, &cond)
+			require.ErrorIs(t, err, apiErrors.GetAPIError(apiErrors.ErrPreconditionFailed))
+
+			// the version of the concurrent writer stays in the tree
+			latest, err := n.treeService.GetLatestVersion(ctx.ctx, ctx.bktInfo, ctx.obj)
+			require.NoError(t, err)
+			require.Equal(t, concurrent.OID, latest.OID)
+
+			versions, err := n.treeService.GetVersions(ctx.ctx, ctx.bktInfo, ctx.obj)
+			require.NoError(t, err)
+			require.Len(t, versions, expectedVersions)
+		})
+	}
+}
@@ -9,11 +9,13 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
 	"github.com/nspcc-dev/neofs-sdk-go/client"
 	"github.com/nspcc-dev/neofs-sdk-go/container"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
 	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
 	"go.uber.org/zap"
 )
 
@@ -77,10 +79,9 @@ func (n *layer) containerInfo(ctx context.Context, idCnr cid.ID) (*data.BucketIn
 	return info, nil
 }
 
-func (n *layer) containerList(ctx context.Context) ([]*data.BucketInfo, error) {
+func (n *layer) containerList(ctx context.Context, own user.ID) ([]*data.BucketInfo, error) {
 	var (
 		err error
-		own = n.Owner(ctx)
 		res []cid.ID
 		rid = api.GetRequestID(ctx)
 	)
@@ -150,7 +151,15 @@ func (n *layer) createContainer(ctx context.Context, p *CreateBucketParams) (*da
 		return nil, fmt.Errorf("set container eacl: %w", err)
 	}
 
+	// new bucket is empty, so its usage doesn't have to be calculated
+	if n.trackUsage {
+		if err = n.treeService.PutBucketUsage(ctx, bktInfo, &data.BucketUsage{}); err != nil {
+			n.log.Warn("couldn't put usage of new bucket", zap.String("bucket", bktInfo.Name), zap.Error(err))
+		}
+	}
+
 	n.cache.PutBucket(bktInfo)
+	metrics.BucketResolved(ctx, p.Name)
 
 	return bktInfo, nil
 }
@@ -65,6 +65,7 @@ func (n *layer) PutBucketCORS(ctx context.Context, p *PutCORSParams) error {
 	}
 
 	n.cache.PutCORS(n.Owner(ctx), p.BktInfo, cors)
+	n.cache.publishSystem(corsCacheKey(p.BktInfo))
 
 	return nil
 }
@@ -0,0 +1,334 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+// Types of the tree nodes referencing NeoFS objects.
+const (
+	CheckNodeVersion       = "version"
+	CheckNodePart          = "part"
+	CheckNodeLock          = "lock"
+	CheckNodeCORS          = "cors"
+	CheckNodeNotifications = "notifications"
+)
+
+type (
+	// CheckBucketParams stores parameters of the bucket consistency check.
+	CheckBucketParams struct {
+		BktInfo *data.BucketInfo
+		// Repair removes orphan objects created by the gateway and tree nodes referencing
+		// missing objects (only version and CORS nodes can be removed).
+		Repair bool
+		// DeleteObjects lists orphan objects to delete on repair even if they weren't
+		// created by the gateway (e.g. objects of other NeoFS tools in the same container).
+		DeleteObjects []oid.ID
+		// OrphanMinAge is the minimal age of the object not referenced by the tree
+		// to consider it orphan. Younger objects can belong to the uploads in progress.
+		OrphanMinAge time.Duration
+	}
+
+	// BucketCheckResult is a result of the bucket consistency check.
+	BucketCheckResult struct {
+		// CheckedNodes is the number of the tree nodes referencing objects.
+		CheckedNodes int
+		// CheckedObjects is the number of the container objects with S3 attributes.
+		CheckedObjects int
+		DanglingNodes  []DanglingNode
+		OrphanObjects  []OrphanObject
+	}
+
+	// DanglingNode is a tree node referencing an object missing in NeoFS.
+	DanglingNode struct {
+		Type     string
+		NodeID   uint64
+		Key      string
+		UploadID string
+		OID      oid.ID
+		Repaired bool
+	}
+
+	// OrphanObject is an object with S3 attributes not referenced by the tree.
+	OrphanObject struct {
+		OID      oid.ID
+		FilePath string
+		UploadID string
+		Size     uint64
+		Created  time.Time
+		// Gateway is true if the object has AttributeGateway, so it was created by the gateway.
+		Gateway  bool
+		Repaired bool
+	}
+)
+
+// CheckBucket checks that all objects referenced by the bucket tree exist in NeoFS
+// and all objects with S3 attributes in the bucket container are referenced by the tree.
+func (n *layer) CheckBucket(ctx context.Context, p *CheckBucketParams) (*BucketCheckResult, error) {
+	res := &BucketCheckResult{}
+	referenced := make(map[oid.ID]struct{})
+
+	check := func(node DanglingNode) error {
+		referenced[node.OID] = struct{}{}
+		res.CheckedNodes++
+
+		exists, err := n.objectExists(ctx, p.BktInfo, node.OID)
+		if err != nil {
+			return fmt.Errorf("couldn't check object '%s' of %s node: %w", node.OID.EncodeToString(), node.Type, err)
+		}
+		if !exists {
+			res.DanglingNodes = append(res.DanglingNodes, node)
+		}
+		return nil
+	}
+
+	if err := n.checkVersions(ctx, p.BktInfo, check); err != nil {
+		return nil, err
+	}
+	if err := n.checkMultipartUploads(ctx, p.BktInfo, check); err != nil {
+		return nil, err
+	}
+	if err := n.checkSystemNodes(ctx, p.BktInfo, check); err != nil {
+		return nil, err
+	}
+	if err := n.findOrphanObjects(ctx, p, referenced, res); err != nil {
+		return nil, err
+	}
+
+	if p.Repair {
+		n.repairBucket(ctx, p, res)
+	}
+
+	return res, nil
+}
+
+func (n *layer) checkVersions(ctx context.Context, bktInfo *data.BucketInfo, check func(DanglingNode) error) error {
+	versions, err := n.treeService.GetAllVersionsByPrefix(ctx, bktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return fmt.Errorf("couldn't get bucket versions: %w", err)
+	}
+
+	for _, version := range versions {
+		if !version.IsDeleteMarker() {
+			if err = check(DanglingNode{Type: CheckNodeVersion, NodeID: version.ID, Key: version.FilePath, OID: version.OID}); err != nil {
+				return err
+			}
+		}
+
+		lock, err := n.treeService.GetLock(ctx, bktInfo, version.ID)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return fmt.Errorf("couldn't get lock of '%s': %w", version.FilePath, err)
+		}
+		if lock == nil {
+			continue
+		}
+
+		var lockIDs []oid.ID
+		if lock.IsLegalHoldSet() {
+			lockIDs = append(lockIDs, lock.LegalHold())
+		}
+		if lock.IsRetentionSet() {
+			lockIDs = append(lockIDs, lock.Retention())
+		}
+		for _, lockID := range lockIDs {
+			if err = check(DanglingNode{Type: CheckNodeLock, NodeID: lock.ID(), Key: version.FilePath, OID: lockID}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *layer) checkMultipartUploads(ctx context.Context, bktInfo *data.BucketInfo, check func(DanglingNode) error) error {
+	uploads, err := n.treeService.GetMultipartUploadsByPrefix(ctx, bktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return fmt.Errorf("couldn't get multipart uploads: %w", err)
+	}
+
+	for _, upload := range uploads {
+		parts, err := n.treeService.GetParts(ctx, bktInfo, upload.ID)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return fmt.Errorf("couldn't get parts of upload '%s': %w", upload.UploadID, err)
+		}
+
+		for _, part := range parts {
+			node := DanglingNode{Type: CheckNodePart, NodeID: upload.ID, Key: upload.Key, UploadID: upload.UploadID, OID: part.OID}
+			if err = check(node); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (n *layer) checkSystemNodes(ctx context.Context, bktInfo *data.BucketInfo, check func(DanglingNode) error) error {
+	corsID, err := n.treeService.GetBucketCORS(ctx, bktInfo)
+	if err == nil {
+		err = check(DanglingNode{Type: CheckNodeCORS, Key: bktInfo.CORSObjectName(), OID: corsID})
+	}
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return fmt.Errorf("couldn't check bucket cors: %w", err)
+	}
+
+	notificationsID, err := n.treeService.GetNotificationConfigurationNode(ctx, bktInfo)
+	if err == nil {
+		err = check(DanglingNode{Type: CheckNodeNotifications, Key: bktInfo.NotificationConfigurationObjectName(), OID: notificationsID})
+	}
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return fmt.Errorf("couldn't check bucket notification configuration: %w", err)
+	}
+
+	return nil
+}
+
+// findOrphanObjects searches for the objects created by the gateway (objects with file path
+// or multipart upload attribute) and reports ones not referenced by the tree.
+func (n *layer) findOrphanObjects(ctx context.Context, p *CheckBucketParams, referenced map[oid.ID]struct{}, res *BucketCheckResult) error {
+	found := make(map[oid.ID]struct{})
+	for _, attr := range []string{object.AttributeFilePath, UploadIDAttributeName} {
+		prm := PrmObjectSearch{
+			Container:     p.BktInfo.CID,
+			WithAttribute: attr,
+		}
+		n.prepareAuthParameters(ctx, &prm.PrmAuth, p.BktInfo.Owner)
+
+		ids, err := n.neoFS.SearchObjects(ctx, prm)
+		if err != nil {
+			return fmt.Errorf("couldn't search objects with '%s' attribute: %w", attr, err)
+		}
+		for _, id := range ids {
+			found[id] = struct{}{}
+		}
+	}
+	res.CheckedObjects = len(found)
+
+	candidates := make([]oid.ID, 0, len(found))
+	for id := range found {
+		if _, ok := referenced[id]; !ok {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].EncodeToString() < candidates[j].EncodeToString()
+	})
+
+	for _, id := range candidates {
+		head, err := n.objectHead(ctx, p.BktInfo, id)
+		if err != nil {
+			if isErrObjectRemoved(err) {
+				continue
+			}
+			return fmt.Errorf("couldn't head object '%s': %w", id.EncodeToString(), err)
+		}
+
+		orphan := OrphanObject{OID: id, Size: head.PayloadSize()}
+		for _, attr := range head.Attributes() {
+			switch attr.Key() {
+			case object.AttributeFilePath:
+				orphan.FilePath = attr.Value()
+			case UploadIDAttributeName:
+				orphan.UploadID = attr.Value()
+			case object.AttributeTimestamp:
+				if ts, err := strconv.ParseInt(attr.Value(), 10, 64); err == nil {
+					orphan.Created = time.Unix(ts, 0).UTC()
+				}
+			case AttributeGateway:
+				orphan.Gateway = true
+			}
+		}
+
+		if !orphan.Created.IsZero() && time.Since(orphan.Created) < p.OrphanMinAge {
+			continue
+		}
+
+		res.OrphanObjects = append(res.OrphanObjects, orphan)
+	}
+
+	return nil
+}
+
+// repairBucket removes dangling version and CORS nodes and orphan objects created by the gateway
+// or explicitly listed in parameters. Other orphan objects can belong to other NeoFS tools, so they
+// are only reported. Repair errors are only logged, not repaired items are left unmarked in the result.
+func (n *layer) repairBucket(ctx context.Context, p *CheckBucketParams, res *BucketCheckResult) {
+	var (
+		bktInfo         = p.BktInfo
+		versionsRemoved bool
+	)
+
+	requested := make(map[oid.ID]struct{}, len(p.DeleteObjects))
+	for _, id := range p.DeleteObjects {
+		requested[id] = struct{}{}
+	}
+
+	for i, node := range res.DanglingNodes {
+		var err error
+		switch node.Type {
+		case CheckNodeVersion:
+			if err = n.treeService.RemoveVersion(ctx, bktInfo, node.NodeID); err == nil {
+				versionsRemoved = true
+				n.cache.CleanListCacheEntriesContainingObject(node.Key, bktInfo.CID)
+				n.cache.DeleteObjectName(bktInfo.CID, bktInfo.Name, node.Key)
+				n.cache.DeleteObject(newAddress(bktInfo.CID, node.OID))
+			}
+		case CheckNodeCORS:
+			if _, err = n.treeService.DeleteBucketCORS(ctx, bktInfo); err == nil || errors.Is(err, ErrNoNodeToRemove) {
+				err = nil
+				n.cache.DeleteCORS(bktInfo)
+			}
+		default:
+			continue
+		}
+
+		if err != nil {
+			n.log.Warn("couldn't remove dangling tree node", zap.String("bucket", bktInfo.Name),
+				zap.String("type", node.Type), zap.Uint64("node", node.NodeID), zap.Error(err))
+			continue
+		}
+		res.DanglingNodes[i].Repaired = true
+	}
+
+	for i, orphan := range res.OrphanObjects {
+		if _, ok := requested[orphan.OID]; !ok && !orphan.Gateway {
+			continue
+		}
+
+		if err := n.objectDelete(ctx, bktInfo, orphan.OID); err != nil {
+			n.log.Warn("couldn't delete orphan object", zap.String("bucket", bktInfo.Name),
+				zap.Stringer("oid", orphan.OID), zap.Error(err))
+			continue
+		}
+		res.OrphanObjects[i].Repaired = true
+	}
+
+	if versionsRemoved {
+		n.refreshBucketUsage(ctx, bktInfo)
+	}
+}
+
+func (n *layer) objectExists(ctx context.Context, bktInfo *data.BucketInfo, id oid.ID) (bool, error) {
+	_, err := n.objectHead(ctx, bktInfo, id)
+	if err == nil {
+		return true, nil
+	}
+	if isErrObjectRemoved(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func isErrObjectRemoved(err error) bool {
+	return client.IsErrObjectNotFound(err) || client.IsErrObjectAlreadyRemoved(err)
+}
@@ -0,0 +1,131 @@
+package layer
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/stretchr/testify/require"
+)
+
+func (tc *testContext) putOrphanObject(filePath string, created time.Time, attrs ...[2]string) oid.ID {
+	objID, err := tc.testNeoFS.CreateObject(tc.ctx, PrmObjectCreate{
+		Container:  tc.bktInfo.CID,
+		Creator:    tc.bktInfo.Owner,
+		Filepath:   filePath,
+		Attributes: append([][2]string{{object.AttributeTimestamp, strconv.FormatInt(created.Unix(), 10)}}, attrs...),
+	})
+	require.NoError(tc.t, err)
+	return objID
+}
+
+func (tc *testContext) checkBucket(repair bool, minAge time.Duration, toDelete ...oid.ID) *BucketCheckResult {
+	res, err := tc.layer.CheckBucket(tc.ctx, &CheckBucketParams{
+		BktInfo:       tc.bktInfo,
+		Repair:        repair,
+		OrphanMinAge:  minAge,
+		DeleteObjects: toDelete,
+	})
+	require.NoError(tc.t, err)
+	return res
+}
+
+func TestCheckBucket(t *testing.T) {
+	tc := prepareContext(t)
+
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	obj1 := tc.putObject([]byte("content obj1 v1"))
+	obj2 := tc.putObject([]byte("content obj1 v2"))
+	require.Contains(t, userHeaders(tc.getObjectByID(obj2.ID).Attributes()), AttributeGateway)
+
+	res := tc.checkBucket(false, 0)
+	require.Equal(t, 2, res.CheckedNodes)
+	require.Equal(t, 2, res.CheckedObjects)
+	require.Empty(t, res.DanglingNodes)
+	require.Empty(t, res.OrphanObjects)
+
+	// object lost in NeoFS
+	require.NoError(t, tc.testNeoFS.DeleteObject(tc.ctx, PrmObjectDelete{Container: tc.bktInfo.CID, Object: obj1.ID}))
+
+	// objects stored without tree nodes
+	orphan := tc.putOrphanObject("orphan", time.Now().Add(-2*time.Hour), [2]string{AttributeGateway, "true"})
+	foreign := tc.putOrphanObject("foreign", time.Now().Add(-3*time.Hour))
+	tc.putOrphanObject("fresh", time.Now())
+
+	res = tc.checkBucket(false, time.Hour)
+	require.Equal(t, 2, res.CheckedNodes)
+	require.Equal(t, 4, res.CheckedObjects)
+	require.Len(t, res.DanglingNodes, 1)
+	require.Equal(t, CheckNodeVersion, res.DanglingNodes[0].Type)
+	require.Equal(t, tc.obj, res.DanglingNodes[0].Key)
+	require.Equal(t, obj1.ID, res.DanglingNodes[0].OID)
+	require.False(t, res.DanglingNodes[0].Repaired)
+	require.Len(t, res.OrphanObjects, 2)
+	orphans := make(map[oid.ID]OrphanObject)
+	for _, obj := range res.OrphanObjects {
+		require.False(t, obj.Repaired)
+		orphans[obj.OID] = obj
+	}
+	require.Equal(t, "orphan", orphans[orphan].FilePath)
+	require.True(t, orphans[orphan].Gateway)
+	require.False(t, orphans[foreign].Gateway)
+
+	// objects of other tools are only reported
+	res = tc.checkBucket(true, time.Hour)
+	require.Len(t, res.DanglingNodes, 1)
+	require.True(t, res.DanglingNodes[0].Repaired)
+	require.Len(t, res.OrphanObjects, 2)
+	for _, obj := range res.OrphanObjects {
+		require.Equal(t, obj.OID == orphan, obj.Repaired)
+	}
+
+	versions := tc.listVersions()
+	require.Len(t, versions.Version, 1)
+	require.Equal(t, obj2.ID, versions.Version[0].ObjectInfo.ID)
+	require.Nil(t, tc.getObjectByID(orphan))
+	require.NotNil(t, tc.getObjectByID(foreign))
+
+	res = tc.checkBucket(true, time.Hour, foreign)
+	require.Len(t, res.OrphanObjects, 1)
+	require.True(t, res.OrphanObjects[0].Repaired)
+	require.Nil(t, tc.getObjectByID(foreign))
+
+	res = tc.checkBucket(false, time.Hour)
+	require.Equal(t, 1, res.CheckedNodes)
+	require.Empty(t, res.DanglingNodes)
+	require.Empty(t, res.OrphanObjects)
+}
+
+func TestCheckBucketSystemNodes(t *testing.T) {
+	tc := prepareContext(t)
+
+	corsID := tc.putOrphanObject(tc.bktInfo.CORSObjectName(), time.Now())
+	_, err := tc.layer.(*layer).treeService.PutBucketCORS(tc.ctx, tc.bktInfo, corsID)
+	require.ErrorIs(t, err, ErrNoNodeToRemove)
+
+	// missing notification configuration object
+	notificationsID := oidtest.ID()
+	_, err = tc.layer.(*layer).treeService.PutNotificationConfigurationNode(tc.ctx, tc.bktInfo, notificationsID)
+	require.ErrorIs(t, err, ErrNoNodeToRemove)
+
+	res := tc.checkBucket(false, 0)
+	require.Equal(t, 2, res.CheckedNodes)
+	require.Empty(t, res.OrphanObjects)
+	require.Len(t, res.DanglingNodes, 1)
+	require.Equal(t, CheckNodeNotifications, res.DanglingNodes[0].Type)
+	require.Equal(t, notificationsID, res.DanglingNodes[0].OID)
+
+	// notification nodes aren't repaired
+	res = tc.checkBucket(true, 0)
+	require.Len(t, res.DanglingNodes, 1)
+	require.False(t, res.DanglingNodes[0].Repaired)
+}
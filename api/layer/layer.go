@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"crypto/rand"
+	errorsStd "errors"
 	"fmt"
 	"io"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -17,6 +19,7 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer/encryption"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
@@ -52,6 +55,13 @@ type (
 		ncontroller EventListener
 		cache       *Cache
 		treeService TreeService
+		objectLocks objectLocks
+		usageLocks  objectLocks
+		// usageInits stores IDs of the buckets which usage is calculated in background.
+		usageInits sync.Map
+
+		deleteWorkers int
+		trackUsage    bool
 	}
 
 	Config struct {
@@ -60,6 +70,11 @@ type (
 		AnonKey      AnonymousKey
 		Resolver     BucketResolver
 		TreeService  TreeService
+		// DeleteWorkers is the number of object keys removed concurrently by DeleteObjects.
+		// Keys are removed one by one if it's not positive.
+		DeleteWorkers int
+		// TrackUsage enables usage tracking for all buckets regardless of their settings.
+		TrackUsage bool
 	}
 
 	// AnonymousKey contains data for anonymous requests.
@@ -107,6 +122,14 @@ type (
 		Lock         *data.ObjectLock
 		Encryption   encryption.Params
 		CopiesNumber uint32
+		StorageClass string
+		Conditions   *PutConditions
+		// TagSet is stored in the tree together with the new version.
+		TagSet map[string]string
+
+		// partsSize is a size of the parts of the completed multipart upload,
+		// it's moved from the multipart size to the size of the bucket usage.
+		partsSize int64
 	}
 
 	DeleteObjectParams struct {
@@ -130,16 +153,19 @@ type (
 
 	// CopyObjectParams stores object copy request parameters.
 	CopyObjectParams struct {
-		SrcObject   *data.ObjectInfo
-		ScrBktInfo  *data.BucketInfo
-		DstBktInfo  *data.BucketInfo
-		DstObject   string
-		SrcSize     int64
-		Header      map[string]string
-		Range       *RangeParams
-		Lock        *data.ObjectLock
-		Encryption  encryption.Params
-		CopiesNuber uint32
+		SrcObject    *data.ObjectInfo
+		ScrBktInfo   *data.BucketInfo
+		DstBktInfo   *data.BucketInfo
+		DstObject    string
+		SrcSize      int64
+		Header       map[string]string
+		Range        *RangeParams
+		Lock         *data.ObjectLock
+		Encryption   encryption.Params
+		CopiesNuber  uint32
+		StorageClass string
+		Conditions   *PutConditions
+		TagSet       map[string]string
 	}
 	// CreateBucketParams stores bucket create request parameters.
 	CreateBucketParams struct {
@@ -178,6 +204,7 @@ type (
 	VersionedObject struct {
 		Name              string
 		VersionID         string
+		IfMatch           string
 		DeleteMarkVersion string
 		DeleteMarkerEtag  string
 		Error             error
@@ -191,11 +218,31 @@ type (
 		GetBucketSettings(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error)
 		PutBucketSettings(ctx context.Context, p *PutSettingsParams) error
 
+		PutBucketQuota(ctx context.Context, p *PutBucketQuotaParams) error
+		GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error)
+		// BucketUsageTracked checks if the usage of the bucket with the settings is maintained by the gateway.
+		BucketUsageTracked(settings *data.BucketSettings) bool
+		SetBucketUsageTracking(ctx context.Context, bktInfo *data.BucketInfo, enabled bool) (*data.BucketUsage, error)
+		CheckBucket(ctx context.Context, p *CheckBucketParams) (*BucketCheckResult, error)
+		RebuildBucketTree(ctx context.Context, p *RebuildBucketParams) (*BucketRebuildResult, error)
+		GetImportCursor(ctx context.Context, bktInfo *data.BucketInfo) (*RebuildCursor, error)
+		PutImportCursor(ctx context.Context, bktInfo *data.BucketInfo, cursor *RebuildCursor) error
+		ListStaleMultipartUploads(ctx context.Context, bktInfo *data.BucketInfo, createdBefore time.Time) ([]*UploadInfo, error)
+
+		// CacheStats returns statistics of the layer caches.
+		CacheStats() []CacheStat
+		// FlushCache removes all entries from the cache of the kind. ErrUnknownCacheKind is returned
+		// for unknown kinds.
+		FlushCache(kind string) error
+		// UpdateCaches recreates caches which size or lifetime are changed.
+		UpdateCaches(cfg *CachesConfig)
+
 		PutBucketCORS(ctx context.Context, p *PutCORSParams) error
 		GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (*data.CORSConfiguration, error)
 		DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) error
 
 		ListBuckets(ctx context.Context) ([]*data.BucketInfo, error)
+		ListUserBuckets(ctx context.Context, owner user.ID) ([]*data.BucketInfo, error)
 		GetBucketInfo(ctx context.Context, name string) (*data.BucketInfo, error)
 		GetBucketACL(ctx context.Context, bktInfo *data.BucketInfo) (*BucketACL, error)
 		PutBucketACL(ctx context.Context, p *PutBucketACLParams) error
@@ -254,6 +301,10 @@ const (
 	AttributeDecryptedSize       = api.NeoFSSystemMetadataPrefix + "Decrypted-Size"
 	AttributeHMACSalt            = api.NeoFSSystemMetadataPrefix + "HMAC-Salt"
 	AttributeHMACKey             = api.NeoFSSystemMetadataPrefix + "HMAC-Key"
+	AttributeStorageClass        = api.NeoFSSystemMetadataPrefix + "Storage-Class"
+	// AttributeGateway marks objects created by the gateway, so they can be told apart
+	// from objects of other NeoFS tools in the same container.
+	AttributeGateway = api.NeoFSSystemMetadataPrefix + "Gateway"
 
 	AttributeNeofsCopiesNumber = "neofs-copies-number" // such formate to match X-Amz-Meta-Neofs-Copies-Number header
 )
@@ -276,6 +327,9 @@ func NewLayer(log *zap.Logger, neoFS NeoFS, config *Config) Client {
 		resolver:    config.Resolver,
 		cache:       NewCache(config.Caches),
 		treeService: config.TreeService,
+
+		deleteWorkers: config.DeleteWorkers,
+		trackUsage:    config.TrackUsage,
 	}
 }
 
@@ -336,7 +390,8 @@ func (n *layer) GetBucketInfo(ctx context.Context, name string) (*data.BucketInf
 		return nil, fmt.Errorf("unescape bucket name: %w", err)
 	}
 
-	if bktInfo := n.cache.GetBucket(name); bktInfo != nil {
+	if bktInfo := n.cache.GetBucket(ctx, name); bktInfo != nil {
+		metrics.BucketResolved(ctx, name)
 		return bktInfo, nil
 	}
 
@@ -346,7 +401,13 @@ func (n *layer) GetBucketInfo(ctx context.Context, name string) (*data.BucketInf
 		return nil, errors.GetAPIError(errors.ErrNoSuchBucket)
 	}
 
-	return n.containerInfo(ctx, containerID)
+	bktInfo, err := n.containerInfo(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.BucketResolved(ctx, name)
+	return bktInfo, nil
 }
 
 // GetBucketACL returns bucket acl info by name.
@@ -370,7 +431,27 @@ func (n *layer) PutBucketACL(ctx context.Context, param *PutBucketACLParams) err
 // ListBuckets returns all user containers. The name of the bucket is a container
 // id. Timestamp is omitted since it is not saved in neofs container.
 func (n *layer) ListBuckets(ctx context.Context) ([]*data.BucketInfo, error) {
-	return n.containerList(ctx)
+	return n.containerList(ctx, n.Owner(ctx))
+}
+
+// ListUserBuckets returns buckets of the user regardless of the request credentials.
+func (n *layer) ListUserBuckets(ctx context.Context, owner user.ID) ([]*data.BucketInfo, error) {
+	return n.containerList(ctx, owner)
+}
+
+// CacheStats returns statistics of the layer caches.
+func (n *layer) CacheStats() []CacheStat {
+	return n.cache.Stats()
+}
+
+// FlushCache removes all entries from the cache of the kind.
+func (n *layer) FlushCache(kind string) error {
+	return n.cache.Purge(kind)
+}
+
+// UpdateCaches recreates caches which size or lifetime are changed.
+func (n *layer) UpdateCaches(cfg *CachesConfig) {
+	n.cache.Update(cfg)
 }
 
 // GetObject from storage.
@@ -482,6 +563,26 @@ func (n *layer) GetExtendedObjectInfo(ctx context.Context, p *HeadObjectParams)
 
 // CopyObject from one bucket into another bucket.
 func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.ExtendedObjectInfo, error) {
+	if err := n.checkPutConditions(ctx, p.DstBktInfo, p.DstObject, p.Conditions); err != nil {
+		return nil, err
+	}
+
+	// check quota before the source object is read, PutObject doesn't read the payload if quota is exceeded
+	dstSettings, err := n.GetBucketSettings(ctx, p.DstBktInfo)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get versioning settings object: %w", err)
+	}
+	delta, err := n.versionUsageDelta(ctx, p.DstBktInfo, dstSettings, &data.NodeVersion{
+		BaseNodeVersion: data.BaseNodeVersion{FilePath: p.DstObject, Size: p.SrcSize},
+		IsUnversioned:   !dstSettings.VersioningEnabled(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err = n.checkQuota(ctx, p.DstBktInfo, dstSettings, delta); err != nil {
+		return nil, err
+	}
+
 	pr, pw := io.Pipe()
 
 	go func() {
@@ -506,6 +607,9 @@ func (n *layer) CopyObject(ctx context.Context, p *CopyObjectParams) (*data.Exte
 		Header:       p.Header,
 		Encryption:   p.Encryption,
 		CopiesNumber: p.CopiesNuber,
+		StorageClass: p.StorageClass,
+		Conditions:   p.Conditions,
+		TagSet:       p.TagSet,
 	})
 }
 
@@ -520,41 +624,79 @@ func getRandomOID() (oid.ID, error) {
 	return objID, nil
 }
 
-func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings *data.BucketSettings, obj *VersionedObject) *VersionedObject {
+// deleteObject removes the object version or adds a delete marker and returns the change of the bucket usage.
+// All versions of the key are read by a single tree service request if the latest version is required
+// to check conditions or to track the number of current objects, or if the version to remove is required.
+func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings *data.BucketSettings, obj *VersionedObject) (*VersionedObject, usageDelta) {
+	if len(obj.IfMatch) != 0 {
+		unlock := n.objectLocks.lock(bkt, obj.Name)
+		defer unlock()
+	}
+
+	var (
+		versions []*data.NodeVersion
+		latest   *data.NodeVersion
+	)
+	if len(obj.IfMatch) != 0 || n.BucketUsageTracked(settings) || len(obj.VersionID) != 0 || !settings.VersioningEnabled() {
+		var err error
+		if versions, err = n.treeService.GetVersions(ctx, bkt, obj.Name); err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+			obj.Error = fmt.Errorf("get versions: %w", err)
+			return obj, usageDelta{}
+		}
+		latest = latestVersion(versions)
+	}
+
+	if len(obj.IfMatch) != 0 {
+		if obj.Error = (&PutConditions{IfMatch: obj.IfMatch}).Check(latest); obj.Error != nil {
+			return obj, usageDelta{}
+		}
+	}
+
 	if len(obj.VersionID) != 0 || settings.Unversioned() {
 		var nodeVersion *data.NodeVersion
-		if nodeVersion, obj.Error = n.getNodeVersionToDelete(ctx, bkt, obj); obj.Error != nil {
-			return dismissNotFoundError(obj)
+		if nodeVersion, obj.Error = versionToDelete(versions, latest, obj.VersionID); obj.Error != nil {
+			return dismissNotFoundError(obj), usageDelta{}
 		}
 
 		if obj.DeleteMarkVersion, obj.Error = n.removeOldVersion(ctx, bkt, nodeVersion, obj); obj.Error != nil {
-			return obj
+			return obj, usageDelta{}
 		}
 
-		obj.Error = n.treeService.RemoveVersion(ctx, bkt, nodeVersion.ID)
+		var delta usageDelta
+		if obj.Error = n.treeService.RemoveVersion(ctx, bkt, nodeVersion.ID); obj.Error == nil {
+			delta = removedLatestUsageDelta(versions, latest, nodeVersion)
+		}
 		n.cache.CleanListCacheEntriesContainingObject(obj.Name, bkt.CID)
-		return obj
+		return obj, delta
 	}
 
 	var newVersion *data.NodeVersion
 
+	delta := usageDelta{deleteMarkers: 1}
+	if isLive(latest) {
+		delta.currentObjects = -1
+	}
+
 	if settings.VersioningSuspended() {
 		obj.VersionID = data.UnversionedObjectVersionID
 
 		var nodeVersion *data.NodeVersion
-		if nodeVersion, obj.Error = n.getNodeVersionToDelete(ctx, bkt, obj); obj.Error != nil {
-			return dismissNotFoundError(obj)
+		if nodeVersion, obj.Error = versionToDelete(versions, latest, obj.VersionID); obj.Error != nil {
+			return dismissNotFoundError(obj), usageDelta{}
 		}
 
 		if obj.DeleteMarkVersion, obj.Error = n.removeOldVersion(ctx, bkt, nodeVersion, obj); obj.Error != nil {
-			return obj
+			return obj, usageDelta{}
 		}
+
+		// null version is replaced by the delete marker below
+		delta = delta.add(removedVersionDelta(nodeVersion))
 	}
 
 	randOID, err := getRandomOID()
 	if err != nil {
 		obj.Error = fmt.Errorf("couldn't get random oid: %w", err)
-		return obj
+		return obj, usageDelta{}
 	}
 
 	obj.DeleteMarkVersion = randOID.EncodeToString()
@@ -572,12 +714,12 @@ func (n *layer) deleteObject(ctx context.Context, bkt *data.BucketInfo, settings
 	}
 
 	if _, obj.Error = n.treeService.AddVersion(ctx, bkt, newVersion); obj.Error != nil {
-		return obj
+		return obj, usageDelta{}
 	}
 
 	n.cache.DeleteObjectName(bkt.CID, bkt.Name, obj.Name)
 
-	return obj
+	return obj, delta
 }
 
 func dismissNotFoundError(obj *VersionedObject) *VersionedObject {
@@ -589,15 +731,42 @@ func dismissNotFoundError(obj *VersionedObject) *VersionedObject {
 	return obj
 }
 
-func (n *layer) getNodeVersionToDelete(ctx context.Context, bkt *data.BucketInfo, obj *VersionedObject) (*data.NodeVersion, error) {
-	objVersion := &ObjectVersion{
-		BktInfo:               bkt,
-		ObjectName:            obj.Name,
-		VersionID:             obj.VersionID,
-		NoErrorOnDeleteMarker: true,
+// latestVersion returns the latest of the object versions or nil if there are no versions.
+func latestVersion(versions []*data.NodeVersion) *data.NodeVersion {
+	var latest *data.NodeVersion
+	for _, version := range versions {
+		if latest == nil || latest.Timestamp <= version.Timestamp {
+			latest = version
+		}
 	}
 
-	return n.getNodeVersion(ctx, objVersion)
+	return latest
+}
+
+// versionToDelete finds the version to delete among all versions of the object.
+// Empty version ID means the latest version, delete markers are returned too.
+func versionToDelete(versions []*data.NodeVersion, latest *data.NodeVersion, versionID string) (*data.NodeVersion, error) {
+	switch versionID {
+	case "":
+		if latest == nil {
+			return nil, errors.GetAPIError(errors.ErrNoSuchKey)
+		}
+		return latest, nil
+	case data.UnversionedObjectVersionID:
+		for _, version := range versions {
+			if version.IsUnversioned {
+				return version, nil
+			}
+		}
+		return nil, errors.GetAPIError(errors.ErrNoSuchKey)
+	default:
+		for _, version := range versions {
+			if version.OID.EncodeToString() == versionID {
+				return version, nil
+			}
+		}
+		return nil, errors.GetAPIError(errors.ErrNoSuchVersion)
+	}
 }
 
 func (n *layer) removeOldVersion(ctx context.Context, bkt *data.BucketInfo, nodeVersion *data.NodeVersion, obj *VersionedObject) (string, error) {
@@ -608,11 +777,64 @@ func (n *layer) removeOldVersion(ctx context.Context, bkt *data.BucketInfo, node
 	return "", n.objectDelete(ctx, bkt, nodeVersion.OID)
 }
 
-// DeleteObjects from the storage.
+// DeleteObjects from the storage. Different keys are removed concurrently by
+// the configured number of workers, versions of the same key are removed in
+// the request order. Results keep the order of the requested objects.
+// The tree service has no batch requests, so every key takes at most one request to read
+// its versions and one request to modify the tree. Bucket usage is updated once per call.
 func (n *layer) DeleteObjects(ctx context.Context, p *DeleteObjectParams) []*VersionedObject {
+	deltas := make([]usageDelta, len(p.Objects))
+	defer func() {
+		var total usageDelta
+		for _, delta := range deltas {
+			total = total.add(delta)
+		}
+		n.updateBucketUsage(ctx, p.BktInfo, p.Settings, total)
+	}()
+
+	if n.deleteWorkers <= 1 || len(p.Objects) <= 1 {
+		for i, obj := range p.Objects {
+			p.Objects[i], deltas[i] = n.deleteObject(ctx, p.BktInfo, p.Settings, obj)
+		}
+		return p.Objects
+	}
+
+	var keys [][]int
+	indexes := make(map[string]int, len(p.Objects))
 	for i, obj := range p.Objects {
-		p.Objects[i] = n.deleteObject(ctx, p.BktInfo, p.Settings, obj)
+		ind, ok := indexes[obj.Name]
+		if !ok {
+			ind = len(keys)
+			indexes[obj.Name] = ind
+			keys = append(keys, nil)
+		}
+		keys[ind] = append(keys[ind], i)
+	}
+
+	workers := n.deleteWorkers
+	if workers > len(keys) {
+		workers = len(keys)
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan []int)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for objects := range jobs {
+				for _, i := range objects {
+					p.Objects[i], deltas[i] = n.deleteObject(ctx, p.BktInfo, p.Settings, p.Objects[i])
+				}
+			}
+		}()
+	}
+
+	for _, objects := range keys {
+		jobs <- objects
 	}
+	close(jobs)
+	wg.Wait()
 
 	return p.Objects
 }
@@ -0,0 +1,148 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/stretchr/testify/require"
+)
+
+// syncTreeService serializes tree calls of the delete path, the mock isn't safe for concurrent use.
+type syncTreeService struct {
+	TreeService
+	mu *sync.Mutex
+}
+
+func (s syncTreeService) GetVersions(ctx context.Context, bktInfo *data.BucketInfo, objectName string) ([]*data.NodeVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.TreeService.GetVersions(ctx, bktInfo, objectName)
+}
+
+func (s syncTreeService) GetLatestVersion(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.TreeService.GetLatestVersion(ctx, bktInfo, objectName)
+}
+
+func (s syncTreeService) GetUnversioned(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.TreeService.GetUnversioned(ctx, bktInfo, objectName)
+}
+
+func (s syncTreeService) AddVersion(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.TreeService.AddVersion(ctx, bktInfo, newVersion)
+}
+
+func (s syncTreeService) RemoveVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.TreeService.RemoveVersion(ctx, bktInfo, nodeID)
+}
+
+type syncNeoFS struct {
+	NeoFS
+	mu *sync.Mutex
+}
+
+func (s syncNeoFS) DeleteObject(ctx context.Context, prm PrmObjectDelete) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.NeoFS.DeleteObject(ctx, prm)
+}
+
+func TestPutObjectWithTagging(t *testing.T) {
+	tc := prepareContext(t)
+	treeService := tc.layer.(*layer).treeService
+
+	putObject := func(tagSet map[string]string) *data.ExtendedObjectInfo {
+		content := []byte("content")
+		extObjInfo, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+			BktInfo: tc.bktInfo,
+			Object:  tc.obj,
+			Size:    int64(len(content)),
+			Reader:  bytes.NewReader(content),
+			Header:  make(map[string]string),
+			TagSet:  tagSet,
+		})
+		require.NoError(t, err)
+		return extObjInfo
+	}
+
+	tagSet := map[string]string{"key": "value"}
+	extObjInfo := putObject(tagSet)
+
+	tags, err := treeService.GetObjectTagging(tc.ctx, tc.bktInfo, extObjInfo.NodeVersion)
+	require.NoError(t, err)
+	require.Equal(t, tagSet, tags)
+
+	_, tags, err = tc.layer.GetObjectTagging(tc.ctx, &GetObjectTaggingParams{
+		ObjectVersion: &ObjectVersion{
+			BktInfo:    tc.bktInfo,
+			ObjectName: tc.obj,
+			VersionID:  extObjInfo.ObjectInfo.VersionID(),
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, tagSet, tags)
+
+	// tags of the replaced unversioned object are removed
+	extObjInfo = putObject(nil)
+	tags, err = treeService.GetObjectTagging(tc.ctx, tc.bktInfo, extObjInfo.NodeVersion)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+}
+
+func TestDeleteObjectsConcurrently(t *testing.T) {
+	tc := prepareContext(t)
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{BktInfo: tc.bktInfo, Settings: settings})
+	require.NoError(t, err)
+
+	var objects []*VersionedObject
+	for i := 0; i < 20; i++ {
+		name := "obj" + strconv.Itoa(i)
+		for j := 0; j < 2; j++ {
+			require.NoError(t, tc.putNamedObject(name, []byte("content "+strconv.Itoa(j))))
+		}
+		versions, err := tc.layer.(*layer).treeService.GetVersions(tc.ctx, tc.bktInfo, name)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		for _, version := range versions {
+			objects = append(objects, &VersionedObject{Name: name, VersionID: version.OID.EncodeToString()})
+		}
+	}
+
+	n := tc.layer.(*layer)
+	mu := &sync.Mutex{}
+	n.treeService = syncTreeService{TreeService: n.treeService, mu: mu}
+	n.neoFS = syncNeoFS{NeoFS: n.neoFS, mu: mu}
+	n.deleteWorkers = 4
+
+	requested := make([]VersionedObject, len(objects))
+	for i, obj := range objects {
+		requested[i] = *obj
+	}
+
+	res := tc.layer.DeleteObjects(tc.ctx, &DeleteObjectParams{
+		BktInfo:  tc.bktInfo,
+		Objects:  objects,
+		Settings: settings,
+	})
+	require.Len(t, res, len(requested))
+	for i, obj := range res {
+		require.NoError(t, obj.Error)
+		require.Equal(t, requested[i].Name, obj.Name)
+		require.Equal(t, requested[i].VersionID, obj.VersionID)
+	}
+
+	require.Empty(t, tc.listVersions().Version)
+	require.Empty(t, tc.testNeoFS.Objects())
+}
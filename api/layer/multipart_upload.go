@@ -49,6 +49,7 @@ type (
 		Header       map[string]string
 		Data         *UploadData
 		CopiesNumber uint32
+		StorageClass string
 	}
 
 	UploadData struct {
@@ -72,8 +73,9 @@ type (
 	}
 
 	CompleteMultipartParams struct {
-		Info  *UploadInfoParams
-		Parts []*CompletedPart
+		Info       *UploadInfoParams
+		Parts      []*CompletedPart
+		Conditions *PutConditions
 	}
 
 	CompletedPart struct {
@@ -151,6 +153,10 @@ func (n *layer) CreateMultipartUpload(ctx context.Context, p *CreateMultipartPar
 	for key, val := range p.Header {
 		info.Meta[metaPrefix+key] = val
 	}
+	delete(info.Meta, metaPrefix+AttributeStorageClass)
+	if len(p.StorageClass) > 0 {
+		info.Meta[metaPrefix+AttributeStorageClass] = p.StorageClass
+	}
 
 	if p.Data != nil {
 		for key, val := range p.Data.ACLHeaders {
@@ -184,7 +190,16 @@ func (n *layer) UploadPart(ctx context.Context, p *UploadPartParams) (string, er
 		return "", errors.GetAPIError(errors.ErrEntityTooLarge)
 	}
 
-	objInfo, err := n.uploadPart(ctx, multipartInfo, p)
+	bktSettings, err := n.GetBucketSettings(ctx, p.Info.Bkt)
+	if err != nil {
+		return "", fmt.Errorf("couldn't get versioning settings object: %w", err)
+	}
+
+	if err = n.checkQuota(ctx, p.Info.Bkt, bktSettings, usageDelta{multipartSize: p.Size}); err != nil {
+		return "", err
+	}
+
+	objInfo, err := n.uploadPart(ctx, bktSettings, multipartInfo, p)
 	if err != nil {
 		return "", err
 	}
@@ -192,7 +207,7 @@ func (n *layer) UploadPart(ctx context.Context, p *UploadPartParams) (string, er
 	return objInfo.HashSum, nil
 }
 
-func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInfo, p *UploadPartParams) (*data.ObjectInfo, error) {
+func (n *layer) uploadPart(ctx context.Context, bktSettings *data.BucketSettings, multipartInfo *data.MultipartInfo, p *UploadPartParams) (*data.ObjectInfo, error) {
 	encInfo := FormEncryptionInfo(multipartInfo.Meta)
 	if err := p.Info.Encryption.MatchObjectEncryption(encInfo); err != nil {
 		n.log.Warn("mismatched obj encryptionInfo", zap.Error(err))
@@ -227,6 +242,9 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 		return nil, err
 	}
 
+	ctx, cancel := completionContext(ctx)
+	defer cancel()
+
 	partInfo := &data.PartInfo{
 		Key:      p.Info.Key,
 		UploadID: p.Info.UploadID,
@@ -237,11 +255,18 @@ func (n *layer) uploadPart(ctx context.Context, multipartInfo *data.MultipartInf
 		Created:  time.Now(),
 	}
 
+	delta := usageDelta{multipartSize: decSize}
+	if n.BucketUsageTracked(bktSettings) {
+		delta.multipartSize -= n.partSize(ctx, bktInfo, multipartInfo, p.PartNumber)
+	}
+
 	oldPartID, err := n.treeService.AddPart(ctx, bktInfo, multipartInfo.ID, partInfo)
 	oldPartIDNotFound := stderrors.Is(err, ErrNoNodeToRemove)
 	if err != nil && !oldPartIDNotFound {
+		n.cleanupObject(ctx, bktInfo, id, err)
 		return nil, err
 	}
+	n.updateBucketUsage(ctx, bktInfo, bktSettings, delta)
 	if !oldPartIDNotFound {
 		if err = n.objectDelete(ctx, bktInfo, oldPartID); err != nil {
 			n.log.Error("couldn't delete old part object", zap.Error(err),
@@ -285,6 +310,15 @@ func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.
 		return nil, errors.GetAPIError(errors.ErrEntityTooLarge)
 	}
 
+	bktSettings, err := n.GetBucketSettings(ctx, p.Info.Bkt)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get versioning settings object: %w", err)
+	}
+
+	if err = n.checkQuota(ctx, p.Info.Bkt, bktSettings, usageDelta{multipartSize: size}); err != nil {
+		return nil, err
+	}
+
 	pr, pw := io.Pipe()
 
 	go func() {
@@ -307,7 +341,7 @@ func (n *layer) UploadPartCopy(ctx context.Context, p *UploadCopyParams) (*data.
 		Reader:     pr,
 	}
 
-	return n.uploadPart(ctx, multipartInfo, params)
+	return n.uploadPart(ctx, bktSettings, multipartInfo, params)
 }
 
 // implements io.Reader of payloads of the object list stored in the NeoFS network.
@@ -360,6 +394,10 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 	if err != nil {
 		return nil, nil, err
 	}
+	if err = n.checkPutConditions(ctx, p.Info.Bkt, p.Info.Key, p.Conditions); err != nil {
+		return nil, nil, err
+	}
+
 	encInfo := FormEncryptionInfo(multipartInfo.Meta)
 
 	if len(partsInfo) < len(p.Parts) {
@@ -441,6 +479,10 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 		Size:         multipartObjetSize,
 		Encryption:   p.Info.Encryption,
 		CopiesNumber: multipartInfo.CopiesNumber,
+		StorageClass: initMetadata[AttributeStorageClass],
+		Conditions:   p.Conditions,
+		TagSet:       uploadData.TagSet,
+		partsSize:    partsSize(partsInfo),
 	})
 	if err != nil {
 		n.log.Error("could not put a completed object (multipart upload)",
@@ -448,6 +490,9 @@ func (n *layer) CompleteMultipartUpload(ctx context.Context, p *CompleteMultipar
 			zap.String("uploadKey", p.Info.Key),
 			zap.Error(err))
 
+		if errors.IsS3Error(err, errors.ErrPreconditionFailed) || errors.IsS3Error(err, errors.ErrQuotaExceeded) {
+			return nil, nil, err
+		}
 		return nil, nil, errors.GetAPIError(errors.ErrInternalError)
 	}
 
@@ -527,6 +572,31 @@ func (n *layer) ListMultipartUploads(ctx context.Context, p *ListMultipartUpload
 	return &result, nil
 }
 
+// ListStaleMultipartUploads returns uploads of the bucket initiated before the specified time
+// sorted by keys and upload ids.
+func (n *layer) ListStaleMultipartUploads(ctx context.Context, bktInfo *data.BucketInfo, createdBefore time.Time) ([]*UploadInfo, error) {
+	multipartInfos, err := n.treeService.GetMultipartUploadsByPrefix(ctx, bktInfo, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var uploads []*UploadInfo
+	for _, multipartInfo := range multipartInfos {
+		if multipartInfo.Created.Before(createdBefore) {
+			uploads = append(uploads, uploadInfoFromMultipartInfo(multipartInfo, "", ""))
+		}
+	}
+
+	sort.Slice(uploads, func(i, j int) bool {
+		if uploads[i].Key == uploads[j].Key {
+			return uploads[i].UploadID < uploads[j].UploadID
+		}
+		return uploads[i].Key < uploads[j].Key
+	})
+
+	return uploads, nil
+}
+
 func (n *layer) AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) error {
 	multipartInfo, parts, err := n.getUploadParts(ctx, p)
 	if err != nil {
@@ -540,7 +610,13 @@ func (n *layer) AbortMultipartUpload(ctx context.Context, p *UploadInfoParams) e
 		}
 	}
 
-	return n.treeService.DeleteMultipartUpload(ctx, p.Bkt, multipartInfo.ID)
+	if err = n.treeService.DeleteMultipartUpload(ctx, p.Bkt, multipartInfo.ID); err != nil {
+		return err
+	}
+
+	n.updateMultipartUsage(ctx, p.Bkt, parts)
+
+	return nil
 }
 
 func (n *layer) ListParts(ctx context.Context, p *ListPartsParams) (*ListPartsInfo, error) {
@@ -3,7 +3,9 @@ package layer
 import (
 	"sort"
 	"testing"
+	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/stretchr/testify/require"
 )
 
@@ -106,3 +108,28 @@ func TestTrimAfterUploadKey(t *testing.T) {
 		require.Empty(t, keys)
 	})
 }
+
+func TestListStaleMultipartUploads(t *testing.T) {
+	tc := prepareContext(t)
+	treeService := tc.layer.(*layer).treeService
+
+	now := time.Now()
+	for _, upload := range []*data.MultipartInfo{
+		{Key: "b", UploadID: "old-b", Created: now.Add(-2 * time.Hour)},
+		{Key: "a", UploadID: "old-a", Created: now.Add(-3 * time.Hour)},
+		{Key: "c", UploadID: "new", Created: now},
+	} {
+		upload.Owner = tc.bktInfo.Owner
+		require.NoError(t, treeService.CreateMultipartUpload(tc.ctx, tc.bktInfo, upload))
+	}
+
+	uploads, err := tc.layer.ListStaleMultipartUploads(tc.ctx, tc.bktInfo, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.Len(t, uploads, 2)
+	require.Equal(t, "old-a", uploads[0].UploadID)
+	require.Equal(t, "old-b", uploads[1].UploadID)
+
+	uploads, err = tc.layer.ListStaleMultipartUploads(tc.ctx, tc.bktInfo, now.Add(-4*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, uploads)
+}
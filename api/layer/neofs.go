@@ -122,6 +122,21 @@ type PrmObjectDelete struct {
 	Object oid.ID
 }
 
+// PrmObjectSearch groups parameters of NeoFS.SearchObjects operation.
+type PrmObjectSearch struct {
+	// Authentication parameters.
+	PrmAuth
+
+	// Container to select the objects from.
+	Container cid.ID
+
+	// Key of the attribute the objects must have (with any value). Optional.
+	WithAttribute string
+
+	// Creation epoch of the objects. Optional, zero matches any epoch.
+	CreationEpoch uint64
+}
+
 // ErrAccessDenied is returned from NeoFS in case of access violation.
 var ErrAccessDenied = errors.New("access denied")
 
@@ -204,6 +219,14 @@ type NeoFS interface {
 	// It returns any error encountered which prevented the removal request from being sent.
 	DeleteObject(context.Context, PrmObjectDelete) error
 
+	// SearchObjects selects root objects of the NeoFS container. Parts of the
+	// big objects are not selected.
+	//
+	// It returns ErrAccessDenied on search access violation.
+	//
+	// It returns any error encountered which prevented the objects from being selected.
+	SearchObjects(context.Context, PrmObjectSearch) ([]oid.ID, error)
+
 	// TimeToEpoch computes current epoch and the epoch that corresponds to the provided time.
 	// Note:
 	// * time must be in the future
@@ -15,6 +15,7 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-sdk-go/bearer"
 	"github.com/nspcc-dev/neofs-sdk-go/checksum"
+	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
 	"github.com/nspcc-dev/neofs-sdk-go/container"
 	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
 	"github.com/nspcc-dev/neofs-sdk-go/eacl"
@@ -156,7 +157,7 @@ func (t *TestNeoFS) ReadObject(ctx context.Context, prm PrmObjectRead) (*ObjectP
 		}, nil
 	}
 
-	return nil, fmt.Errorf("object not found %s", addr)
+	return nil, fmt.Errorf("%w: %s", apistatus.ObjectNotFound{}, addr)
 }
 
 func (t *TestNeoFS) CreateObject(ctx context.Context, prm PrmObjectCreate) (oid.ID, error) {
@@ -235,6 +236,36 @@ func (t *TestNeoFS) DeleteObject(ctx context.Context, prm PrmObjectDelete) error
 	return nil
 }
 
+func (t *TestNeoFS) SearchObjects(_ context.Context, prm PrmObjectSearch) ([]oid.ID, error) {
+	var res []oid.ID
+	for _, obj := range t.objects {
+		if cnrID, _ := obj.ContainerID(); !cnrID.Equals(prm.Container) {
+			continue
+		}
+
+		if prm.CreationEpoch != 0 && obj.CreationEpoch() != prm.CreationEpoch {
+			continue
+		}
+
+		if prm.WithAttribute != "" {
+			var found bool
+			for _, attr := range obj.Attributes() {
+				if found = attr.Key() == prm.WithAttribute; found {
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+
+		objID, _ := obj.ID()
+		res = append(res, objID)
+	}
+
+	return res, nil
+}
+
 func (t *TestNeoFS) TimeToEpoch(_ context.Context, futureTime time.Time) (uint64, uint64, error) {
 	return t.currentEpoch, t.currentEpoch + uint64(futureTime.Second()), nil
 }
@@ -54,13 +54,14 @@ func (n *layer) PutBucketNotificationConfiguration(ctx context.Context, p *PutBu
 	}
 
 	n.cache.PutNotificationConfiguration(n.Owner(ctx), p.BktInfo, p.Configuration)
+	n.cache.publishSystem(notificationConfigurationCacheKey(p.BktInfo))
 
 	return nil
 }
 
 func (n *layer) GetBucketNotificationConfiguration(ctx context.Context, bktInfo *data.BucketInfo) (*data.NotificationConfiguration, error) {
 	owner := n.Owner(ctx)
-	if conf := n.cache.GetNotificationConfiguration(owner, bktInfo); conf != nil {
+	if conf := n.cache.GetNotificationConfiguration(ctx, owner, bktInfo); conf != nil {
 		return conf, nil
 	}
 
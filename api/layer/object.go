@@ -192,12 +192,32 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		return nil, fmt.Errorf("couldn't get versioning settings object: %w", err)
 	}
 
+	if err = n.checkPutConditions(ctx, p.BktInfo, p.Object, p.Conditions); err != nil {
+		return nil, err
+	}
+
 	newVersion := &data.NodeVersion{
 		BaseNodeVersion: data.BaseNodeVersion{
 			FilePath: p.Object,
 			Size:     p.Size,
 		},
 		IsUnversioned: !bktSettings.VersioningEnabled(),
+		StorageClass:  p.StorageClass,
+	}
+
+	delta, err := n.versionUsageDelta(ctx, p.BktInfo, bktSettings, newVersion)
+	if err != nil {
+		return nil, err
+	}
+	delta.multipartSize = -p.partsSize
+	if err = n.checkQuota(ctx, p.BktInfo, bktSettings, delta); err != nil {
+		return nil, err
+	}
+
+	// storage class can be set only by the gateway, not by user metadata
+	delete(p.Header, AttributeStorageClass)
+	if len(p.StorageClass) > 0 {
+		p.Header[AttributeStorageClass] = p.StorageClass
 	}
 
 	r := p.Reader
@@ -248,12 +268,30 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		return nil, err
 	}
 
+	ctx, cancel := completionContext(ctx)
+	defer cancel()
+
 	newVersion.OID = id
 	newVersion.ETag = hex.EncodeToString(hash)
-	if newVersion.ID, err = n.treeService.AddVersion(ctx, p.BktInfo, newVersion); err != nil {
+	if newVersion.ID, err = n.addVersionConditionally(ctx, p.BktInfo, newVersion, p.TagSet, p.Conditions); err != nil {
+		n.cleanupObject(ctx, p.BktInfo, id, err)
+		if apiErrors.IsS3Error(err, apiErrors.ErrPreconditionFailed) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("couldn't add new verion to tree service: %w", err)
 	}
 
+	n.updateBucketUsage(ctx, p.BktInfo, bktSettings, delta)
+
+	if len(p.TagSet) != 0 {
+		objVersion := &ObjectVersion{
+			BktInfo:    p.BktInfo,
+			ObjectName: p.Object,
+			VersionID:  id.EncodeToString(),
+		}
+		n.cache.PutTagging(owner, objectTaggingCacheKey(objVersion), p.TagSet)
+	}
+
 	if p.Lock != nil && (p.Lock.Retention != nil || p.Lock.LegalHold != nil) {
 		putLockInfoPrms := &PutLockInfoParams{
 			ObjVersion: &ObjectVersion{
@@ -264,6 +302,8 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 			NewLock:      p.Lock,
 			CopiesNumber: p.CopiesNumber,
 			NodeVersion:  newVersion, // provide new version to make one less tree service call in PutLockInfo
+			// new versioned node can't have a lock yet, unversioned one is reused and may have it
+			NewNodeVersion: !newVersion.IsUnversioned,
 		}
 
 		if err = n.PutLockInfo(ctx, putLockInfoPrms); err != nil {
@@ -271,7 +311,8 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 		}
 	}
 
-	n.cache.CleanListCacheEntriesContainingObject(p.Object, p.BktInfo.CID)
+	// other gateway instances may have the previous version of the object cached by name
+	n.cache.DeleteObjectName(p.BktInfo.CID, p.BktInfo.Name, p.Object)
 
 	objInfo := &data.ObjectInfo{
 		ID:  id,
@@ -299,7 +340,7 @@ func (n *layer) PutObject(ctx context.Context, p *PutObjectParams) (*data.Extend
 
 func (n *layer) headLastVersionIfNotDeleted(ctx context.Context, bkt *data.BucketInfo, objectName string) (*data.ExtendedObjectInfo, error) {
 	owner := n.Owner(ctx)
-	if extObjInfo := n.cache.GetLastObject(owner, bkt.Name, objectName); extObjInfo != nil {
+	if extObjInfo := n.cache.GetLastObject(ctx, owner, bkt.Name, objectName); extObjInfo != nil {
 		return extObjInfo, nil
 	}
 
@@ -360,7 +401,7 @@ func (n *layer) headVersion(ctx context.Context, bkt *data.BucketInfo, p *HeadOb
 	}
 
 	owner := n.Owner(ctx)
-	if extObjInfo := n.cache.GetObject(owner, newAddress(bkt.CID, foundVersion.OID)); extObjInfo != nil {
+	if extObjInfo := n.cache.GetObject(ctx, owner, newAddress(bkt.CID, foundVersion.OID)); extObjInfo != nil {
 		return extObjInfo, nil
 	}
 
@@ -401,6 +442,7 @@ func (n *layer) objectDelete(ctx context.Context, bktInfo *data.BucketInfo, idOb
 // Returns object ID and payload sha256 hash.
 func (n *layer) objectPutAndHash(ctx context.Context, prm PrmObjectCreate, bktInfo *data.BucketInfo) (oid.ID, []byte, error) {
 	n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
+	prm.Attributes = withGatewayAttribute(prm.Attributes)
 	hash := sha256.New()
 	prm.Payload = wrapReader(prm.Payload, 64*1024, func(buf []byte) {
 		hash.Write(buf)
@@ -409,6 +451,19 @@ func (n *layer) objectPutAndHash(ctx context.Context, prm PrmObjectCreate, bktIn
 	return id, hash.Sum(nil), err
 }
 
+// withGatewayAttribute returns a copy of attributes with AttributeGateway.
+// Attributes copied from the source object can already contain it.
+func withGatewayAttribute(attrs [][2]string) [][2]string {
+	res := make([][2]string, 0, len(attrs)+1)
+	for _, attr := range attrs {
+		if attr[0] != AttributeGateway {
+			res = append(res, attr)
+		}
+	}
+
+	return append(res, [2]string{AttributeGateway, "true"})
+}
+
 // ListObjectsV1 returns objects in a bucket for requests of Version 1.
 func (n *layer) ListObjectsV1(ctx context.Context, p *ListObjectsParamsV1) (*ListObjectsInfoV1, error) {
 	var result ListObjectsInfoV1
@@ -479,7 +534,7 @@ func (n *layer) getLatestObjectsVersions(ctx context.Context, p allObjectParams)
 
 	owner := n.Owner(ctx)
 	cacheKey := cache.CreateObjectsListCacheKey(p.Bucket.CID, p.Prefix, true)
-	nodeVersions := n.cache.GetList(owner, cacheKey)
+	nodeVersions := n.cache.GetList(ctx, owner, cacheKey)
 
 	if nodeVersions == nil {
 		nodeVersions, err = n.treeService.GetLatestVersionsByPrefix(ctx, p.Bucket, p.Prefix)
@@ -618,7 +673,7 @@ func (n *layer) bucketNodeVersions(ctx context.Context, bkt *data.BucketInfo, pr
 
 	owner := n.Owner(ctx)
 	cacheKey := cache.CreateObjectsListCacheKey(bkt.CID, prefix, false)
-	nodeVersions := n.cache.GetList(owner, cacheKey)
+	nodeVersions := n.cache.GetList(ctx, owner, cacheKey)
 
 	if nodeVersions == nil {
 		nodeVersions, err = n.treeService.GetAllVersionsByPrefix(ctx, bkt, prefix)
@@ -737,7 +792,7 @@ func (n *layer) objectInfoFromObjectsCacheOrNeoFS(ctx context.Context, bktInfo *
 	}
 
 	owner := n.Owner(ctx)
-	if extInfo := n.cache.GetObject(owner, newAddress(bktInfo.CID, node.OID)); extInfo != nil {
+	if extInfo := n.cache.GetObject(ctx, owner, newAddress(bktInfo.CID, node.OID)); extInfo != nil {
 		return extInfo.ObjectInfo
 	}
 
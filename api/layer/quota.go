@@ -0,0 +1,449 @@
+package layer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
+	"go.uber.org/zap"
+)
+
+type (
+	// PutBucketQuotaParams stores PutBucketQuota request parameters.
+	PutBucketQuotaParams struct {
+		BktInfo *data.BucketInfo
+		// Quota to set, nil removes the quota from the bucket.
+		Quota *data.BucketQuota
+	}
+
+	// usageDelta is a change of the bucket usage caused by a single operation.
+	usageDelta struct {
+		size           int64
+		objects        int64
+		currentObjects int64
+		deleteMarkers  int64
+		multipartSize  int64
+	}
+)
+
+// usageLockKey is an object name used to serialize usage updates of the bucket.
+const usageLockKey = ""
+
+var (
+	// ErrUsageRequired is returned on attempt to disable usage tracking for the bucket with a quota.
+	ErrUsageRequired = errors.New("usage tracking is required by the bucket quota")
+
+	// ErrUsageTrackedForAll is returned on attempt to disable usage tracking for the bucket
+	// when usage is tracked for all buckets.
+	ErrUsageTrackedForAll = errors.New("usage is tracked for all buckets")
+)
+
+// BucketUsageTracked checks if the usage of the bucket is maintained by the gateway.
+// Usage is tracked for all buckets if it's enabled in the gateway config.
+func (n *layer) BucketUsageTracked(settings *data.BucketSettings) bool {
+	return n.trackUsage || settings.UsageTracked()
+}
+
+// PutBucketQuota sets or removes quota of the bucket. Bucket usage is recalculated
+// from the tree service when the quota is set, because it isn't tracked for buckets without quota.
+func (n *layer) PutBucketQuota(ctx context.Context, p *PutBucketQuotaParams) error {
+	settings, err := n.GetBucketSettings(ctx, p.BktInfo)
+	if err != nil {
+		return fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+
+	if p.Quota != nil {
+		if _, err = n.recalculateBucketUsage(ctx, p.BktInfo); err != nil {
+			return err
+		}
+	}
+
+	newSettings := *settings
+	newSettings.Quota = p.Quota
+
+	return n.putSettingsForOwners(ctx, p.BktInfo, &newSettings)
+}
+
+// SetBucketUsageTracking enables or disables tracking of the bucket usage. Usage is recalculated
+// from the tree service every time tracking is enabled, so it can be used to fix accumulated errors.
+// Tracking can't be disabled for the bucket with a quota.
+func (n *layer) SetBucketUsageTracking(ctx context.Context, bktInfo *data.BucketInfo, enabled bool) (*data.BucketUsage, error) {
+	settings, err := n.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+
+	var usage *data.BucketUsage
+	if enabled {
+		if usage, err = n.recalculateBucketUsage(ctx, bktInfo); err != nil {
+			return nil, err
+		}
+	} else if settings.Quota != nil {
+		return nil, ErrUsageRequired
+	} else if n.trackUsage {
+		return nil, ErrUsageTrackedForAll
+	}
+
+	newSettings := *settings
+	newSettings.TrackUsage = enabled
+
+	if err = n.putSettingsForOwners(ctx, bktInfo, &newSettings); err != nil {
+		return nil, err
+	}
+
+	if !n.BucketUsageTracked(&newSettings) {
+		metrics.DeleteBucketUsage(bktInfo.Name)
+	}
+
+	return usage, nil
+}
+
+// putSettingsForOwners puts bucket settings and refreshes them in cache for the bucket owner.
+// Settings are cached per owner, so the bucket owner gets changes made by the admin
+// without waiting for cache expiration.
+func (n *layer) putSettingsForOwners(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings) error {
+	if err := n.PutBucketSettings(ctx, &PutSettingsParams{BktInfo: bktInfo, Settings: settings}); err != nil {
+		return err
+	}
+
+	n.cache.PutSettings(bktInfo.Owner, bktInfo, settings)
+
+	return nil
+}
+
+// GetBucketUsage returns the current usage of the bucket with usage tracking.
+// ErrNodeNotFound is returned if usage isn't tracked for the bucket. If usage is tracked
+// for all buckets, the calculation of the missing usage is started in background.
+func (n *layer) GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error) {
+	usage, err := n.treeService.GetBucketUsage(ctx, bktInfo)
+	if err != nil {
+		if errors.Is(err, ErrNodeNotFound) && n.trackUsage {
+			n.initBucketUsage(ctx, bktInfo)
+		}
+		return nil, err
+	}
+
+	metrics.SetBucketUsage(bktInfo.Name, usage)
+
+	return usage, nil
+}
+
+func (n *layer) recalculateBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error) {
+	unlock := n.usageLocks.lock(bktInfo, usageLockKey)
+	defer unlock()
+
+	usage, err := n.calculateBucketUsage(ctx, bktInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = n.treeService.PutBucketUsage(ctx, bktInfo, usage); err != nil {
+		return nil, fmt.Errorf("couldn't put bucket usage: %w", err)
+	}
+
+	metrics.SetBucketUsage(bktInfo.Name, usage)
+
+	return usage, nil
+}
+
+// initBucketUsage calculates usage of the tracked bucket without the usage node in background,
+// e.g. of the bucket created before usage tracking was enabled for all buckets. The bucket isn't
+// locked during the calculation, so changes made meanwhile can be missed. Usage is stored only if
+// it's still missing, since it could be recalculated by the admin request or another gateway.
+func (n *layer) initBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) {
+	if _, loaded := n.usageInits.LoadOrStore(bktInfo.CID, struct{}{}); loaded {
+		return
+	}
+
+	// the calculation can take a long time, so it isn't limited by the request
+	ctx = detachedContext{ctx}
+
+	go func() {
+		defer n.usageInits.Delete(bktInfo.CID)
+
+		usage, err := n.calculateBucketUsage(ctx, bktInfo)
+		if err != nil {
+			n.log.Error("couldn't calculate bucket usage", zap.String("bucket", bktInfo.Name), zap.Error(err))
+			return
+		}
+
+		unlock := n.usageLocks.lock(bktInfo, usageLockKey)
+		defer unlock()
+
+		if _, err = n.treeService.GetBucketUsage(ctx, bktInfo); !errors.Is(err, ErrNodeNotFound) {
+			if err != nil {
+				n.log.Error("couldn't get bucket usage", zap.String("bucket", bktInfo.Name), zap.Error(err))
+			}
+			return
+		}
+
+		if err = n.treeService.PutBucketUsage(ctx, bktInfo, usage); err != nil {
+			n.log.Error("couldn't put bucket usage", zap.String("bucket", bktInfo.Name), zap.Error(err))
+			return
+		}
+
+		metrics.SetBucketUsage(bktInfo.Name, usage)
+		n.log.Info("bucket usage calculated", zap.String("bucket", bktInfo.Name))
+	}()
+}
+
+// refreshBucketUsage recalculates usage of the bucket with usage tracking after
+// tree modifications made bypassing regular object operations.
+func (n *layer) refreshBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) {
+	settings, err := n.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		n.log.Warn("couldn't get bucket settings to refresh usage", zap.String("bucket", bktInfo.Name), zap.Error(err))
+		return
+	}
+	if !n.BucketUsageTracked(settings) {
+		return
+	}
+
+	if _, err = n.recalculateBucketUsage(ctx, bktInfo); err != nil {
+		n.log.Warn("couldn't recalculate bucket usage", zap.String("bucket", bktInfo.Name), zap.Error(err))
+	}
+}
+
+func (n *layer) calculateBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error) {
+	versions, err := n.treeService.GetAllVersionsByPrefix(ctx, bktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("couldn't get bucket versions: %w", err)
+	}
+
+	usage := &data.BucketUsage{}
+	latest := make(map[string]*data.NodeVersion)
+	for _, version := range versions {
+		if last, ok := latest[version.FilePath]; !ok || last.Timestamp <= version.Timestamp {
+			latest[version.FilePath] = version
+		}
+
+		if version.IsDeleteMarker() {
+			usage.DeleteMarkers++
+			continue
+		}
+		usage.Size += uint64(version.Size)
+		usage.Objects++
+	}
+
+	for _, version := range latest {
+		if !version.IsDeleteMarker() {
+			usage.CurrentObjects++
+		}
+	}
+
+	uploads, err := n.treeService.GetMultipartUploadsByPrefix(ctx, bktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("couldn't get multipart uploads: %w", err)
+	}
+
+	for _, upload := range uploads {
+		parts, err := n.treeService.GetParts(ctx, bktInfo, upload.ID)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return nil, fmt.Errorf("couldn't get parts of upload '%s': %w", upload.UploadID, err)
+		}
+		for _, part := range parts {
+			usage.MultipartSize += uint64(part.Size)
+		}
+	}
+
+	return usage, nil
+}
+
+// checkQuota checks if the bucket can store additional data. Size of the parts of not completed
+// multipart uploads is counted in the size limit. Exceeding of the hard limit results in
+// ErrQuotaExceeded, exceeding of the soft limit is only logged. The check isn't atomic with the
+// following write, so concurrent requests can exceed the hard limit a bit.
+func (n *layer) checkQuota(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, delta usageDelta) error {
+	if settings.Quota == nil {
+		return nil
+	}
+
+	usage, err := n.treeService.GetBucketUsage(ctx, bktInfo)
+	if err != nil {
+		if !errors.Is(err, ErrNodeNotFound) {
+			return fmt.Errorf("couldn't get bucket usage: %w", err)
+		}
+		usage = &data.BucketUsage{}
+	}
+
+	sizeDelta := delta.size + delta.multipartSize
+	newSize := applyDelta(usage.Size+usage.MultipartSize, sizeDelta)
+	newObjects := applyDelta(usage.Objects, delta.objects)
+	quota := settings.Quota
+
+	if (sizeDelta > 0 && exceeds(newSize, quota.MaxSize)) || (delta.objects > 0 && exceeds(newObjects, quota.MaxObjects)) {
+		return apiErrors.GetAPIError(apiErrors.ErrQuotaExceeded)
+	}
+
+	if exceeds(newSize, quota.SoftMaxSize) || exceeds(newObjects, quota.SoftMaxObjects) {
+		n.log.Warn("bucket soft quota exceeded", zap.String("bucket", bktInfo.Name),
+			zap.Uint64("size", newSize), zap.Uint64("objects", newObjects))
+	}
+
+	return nil
+}
+
+// updateBucketUsage applies changes to the usage of the bucket with usage tracking.
+// Updates are serialized within the gateway instance only, so usage is approximate
+// when the bucket is modified through several gateways simultaneously. Changes aren't
+// applied if the usage is missing, it's calculated from scratch in background instead.
+func (n *layer) updateBucketUsage(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, delta usageDelta) {
+	if !n.BucketUsageTracked(settings) || delta == (usageDelta{}) {
+		return
+	}
+
+	unlock := n.usageLocks.lock(bktInfo, usageLockKey)
+	defer unlock()
+
+	usage, err := n.treeService.GetBucketUsage(ctx, bktInfo)
+	if err != nil {
+		if errors.Is(err, ErrNodeNotFound) {
+			n.initBucketUsage(ctx, bktInfo)
+		} else {
+			n.log.Error("couldn't get bucket usage", zap.String("bucket", bktInfo.Name), zap.Error(err))
+		}
+		return
+	}
+
+	usage.Size = applyDelta(usage.Size, delta.size)
+	usage.Objects = applyDelta(usage.Objects, delta.objects)
+	usage.CurrentObjects = applyDelta(usage.CurrentObjects, delta.currentObjects)
+	usage.DeleteMarkers = applyDelta(usage.DeleteMarkers, delta.deleteMarkers)
+	usage.MultipartSize = applyDelta(usage.MultipartSize, delta.multipartSize)
+
+	if err = n.treeService.PutBucketUsage(ctx, bktInfo, usage); err != nil {
+		n.log.Error("couldn't update bucket usage", zap.String("bucket", bktInfo.Name), zap.Error(err))
+		return
+	}
+
+	metrics.SetBucketUsage(bktInfo.Name, usage)
+}
+
+// versionUsageDelta returns changes of the bucket usage caused by the adding of the new version.
+func (n *layer) versionUsageDelta(ctx context.Context, bktInfo *data.BucketInfo, settings *data.BucketSettings, newVersion *data.NodeVersion) (usageDelta, error) {
+	delta := usageDelta{size: newVersion.Size, objects: 1}
+	if !n.BucketUsageTracked(settings) {
+		return delta, nil
+	}
+
+	latest, unversioned, err := n.latestAndUnversioned(ctx, bktInfo, newVersion.FilePath)
+	if err != nil {
+		return usageDelta{}, err
+	}
+	if !isLive(latest) {
+		delta.currentObjects = 1
+	}
+
+	// unversioned object is replaced in place
+	if newVersion.IsUnversioned && unversioned != nil {
+		delta = delta.add(removedVersionDelta(unversioned))
+	}
+
+	return delta, nil
+}
+
+// removedVersionDelta returns changes of the bucket usage caused by the removing of the version
+// not counting current objects.
+func removedVersionDelta(version *data.NodeVersion) usageDelta {
+	if version.IsDeleteMarker() {
+		return usageDelta{deleteMarkers: -1}
+	}
+
+	return usageDelta{size: -version.Size, objects: -1}
+}
+
+func (d usageDelta) add(other usageDelta) usageDelta {
+	return usageDelta{
+		size:           d.size + other.size,
+		objects:        d.objects + other.objects,
+		currentObjects: d.currentObjects + other.currentObjects,
+		deleteMarkers:  d.deleteMarkers + other.deleteMarkers,
+		multipartSize:  d.multipartSize + other.multipartSize,
+	}
+}
+
+func isLive(version *data.NodeVersion) bool {
+	return version != nil && !version.IsDeleteMarker()
+}
+
+func partsSize(parts map[int]*data.PartInfo) int64 {
+	var size int64
+	for _, part := range parts {
+		size += part.Size
+	}
+	return size
+}
+
+func applyDelta(value uint64, delta int64) uint64 {
+	if delta < 0 {
+		if uint64(-delta) > value {
+			return 0
+		}
+		return value - uint64(-delta)
+	}
+
+	return value + uint64(delta)
+}
+
+func exceeds(value, limit uint64) bool {
+	return limit != 0 && value > limit
+}
+
+// removedLatestUsageDelta returns changes of the bucket usage caused by the removing of the version.
+// If the removed version was the latest one, the new latest version is found among the versions
+// of the object read before the removal to update the number of current objects.
+func removedLatestUsageDelta(versions []*data.NodeVersion, latest, removed *data.NodeVersion) usageDelta {
+	delta := removedVersionDelta(removed)
+	if latest == nil || latest.ID != removed.ID {
+		return delta
+	}
+
+	var newLatest *data.NodeVersion
+	for _, version := range versions {
+		if version.ID != removed.ID && (newLatest == nil || newLatest.Timestamp <= version.Timestamp) {
+			newLatest = version
+		}
+	}
+
+	if isLive(latest) && !isLive(newLatest) {
+		delta.currentObjects = -1
+	} else if !isLive(latest) && isLive(newLatest) {
+		delta.currentObjects = 1
+	}
+
+	return delta
+}
+
+// partSize returns the size of the uploaded part with the number or zero if there is no such part.
+func (n *layer) partSize(ctx context.Context, bktInfo *data.BucketInfo, multipartInfo *data.MultipartInfo, number int) int64 {
+	parts, err := n.treeService.GetParts(ctx, bktInfo, multipartInfo.ID)
+	if err != nil {
+		if !errors.Is(err, ErrNodeNotFound) {
+			n.log.Error("couldn't get parts to update bucket usage", zap.String("bucket", bktInfo.Name),
+				zap.String("upload id", multipartInfo.UploadID), zap.Error(err))
+		}
+		return 0
+	}
+
+	for _, part := range parts {
+		if part.Number == number {
+			return part.Size
+		}
+	}
+
+	return 0
+}
+
+// updateMultipartUsage removes parts of completed or aborted upload from the bucket usage.
+func (n *layer) updateMultipartUsage(ctx context.Context, bktInfo *data.BucketInfo, parts map[int]*data.PartInfo) {
+	settings, err := n.GetBucketSettings(ctx, bktInfo)
+	if err != nil {
+		n.log.Error("couldn't get bucket settings to update bucket usage", zap.String("bucket", bktInfo.Name), zap.Error(err))
+		return
+	}
+
+	n.updateBucketUsage(ctx, bktInfo, settings, usageDelta{multipartSize: -partsSize(parts)})
+}
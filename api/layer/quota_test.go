@@ -0,0 +1,268 @@
+package layer
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func (tc *testContext) putNamedObject(name string, content []byte) error {
+	_, err := tc.layer.PutObject(tc.ctx, &PutObjectParams{
+		BktInfo: tc.bktInfo,
+		Object:  name,
+		Size:    int64(len(content)),
+		Reader:  bytes.NewReader(content),
+		Header:  make(map[string]string),
+	})
+	return err
+}
+
+func (tc *testContext) checkUsage(expected data.BucketUsage) {
+	usage, err := tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.NoError(tc.t, err)
+	require.Equal(tc.t, &expected, usage)
+}
+
+func TestBucketQuotaUnversioned(t *testing.T) {
+	tc := prepareContext(t)
+
+	require.NoError(t, tc.putNamedObject("obj1", make([]byte, 10)))
+
+	err := tc.layer.PutBucketQuota(tc.ctx, &PutBucketQuotaParams{
+		BktInfo: tc.bktInfo,
+		Quota:   &data.BucketQuota{MaxSize: 25, MaxObjects: 2},
+	})
+	require.NoError(t, err)
+	tc.checkUsage(data.BucketUsage{Size: 10, Objects: 1, CurrentObjects: 1})
+
+	// overwrite of unversioned object changes size only
+	require.NoError(t, tc.putNamedObject("obj1", make([]byte, 20)))
+	tc.checkUsage(data.BucketUsage{Size: 20, Objects: 1, CurrentObjects: 1})
+
+	err = tc.putNamedObject("obj2", make([]byte, 10))
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrQuotaExceeded))
+	tc.checkUsage(data.BucketUsage{Size: 20, Objects: 1, CurrentObjects: 1})
+
+	require.NoError(t, tc.putNamedObject("obj2", make([]byte, 5)))
+	tc.checkUsage(data.BucketUsage{Size: 25, Objects: 2, CurrentObjects: 2})
+
+	err = tc.putNamedObject("obj3", nil)
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrQuotaExceeded))
+
+	settings, err := tc.layer.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	tc.deleteObject("obj1", "", settings)
+	tc.checkUsage(data.BucketUsage{Size: 5, Objects: 1, CurrentObjects: 1})
+
+	require.NoError(t, tc.putNamedObject("obj3", nil))
+	tc.checkUsage(data.BucketUsage{Size: 5, Objects: 2, CurrentObjects: 2})
+
+	err = tc.layer.PutBucketQuota(tc.ctx, &PutBucketQuotaParams{BktInfo: tc.bktInfo})
+	require.NoError(t, err)
+	require.NoError(t, tc.putNamedObject("obj4", make([]byte, 100)))
+}
+
+func TestBucketQuotaVersioned(t *testing.T) {
+	tc := prepareContext(t)
+
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	err = tc.layer.PutBucketQuota(tc.ctx, &PutBucketQuotaParams{
+		BktInfo: tc.bktInfo,
+		Quota:   &data.BucketQuota{MaxObjects: 2},
+	})
+	require.NoError(t, err)
+	tc.checkUsage(data.BucketUsage{})
+
+	obj1v1 := tc.putObject([]byte("v1"))
+	tc.putObject([]byte("v2"))
+	tc.checkUsage(data.BucketUsage{Size: 4, Objects: 2, CurrentObjects: 1})
+
+	err = tc.putNamedObject(tc.obj, []byte("v3"))
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrQuotaExceeded))
+
+	settings, err := tc.layer.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+
+	// delete marker doesn't free space
+	tc.deleteObject(tc.obj, "", settings)
+	tc.checkUsage(data.BucketUsage{Size: 4, Objects: 2, DeleteMarkers: 1})
+
+	tc.deleteObject(tc.obj, obj1v1.ID.EncodeToString(), settings)
+	tc.checkUsage(data.BucketUsage{Size: 2, Objects: 1, DeleteMarkers: 1})
+}
+
+func TestBucketQuotaMultipart(t *testing.T) {
+	tc := prepareContext(t)
+
+	err := tc.layer.PutBucketQuota(tc.ctx, &PutBucketQuotaParams{
+		BktInfo: tc.bktInfo,
+		Quota:   &data.BucketQuota{MaxSize: 10},
+	})
+	require.NoError(t, err)
+
+	uploadInfo := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: "multipart"}
+	err = tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: uploadInfo, Header: make(map[string]string)})
+	require.NoError(t, err)
+
+	uploadPart := func(number int, content []byte) (string, error) {
+		return tc.layer.UploadPart(tc.ctx, &UploadPartParams{
+			Info:       uploadInfo,
+			PartNumber: number,
+			Size:       int64(len(content)),
+			Reader:     bytes.NewReader(content),
+		})
+	}
+
+	etag, err := uploadPart(1, make([]byte, 6))
+	require.NoError(t, err)
+	tc.checkUsage(data.BucketUsage{MultipartSize: 6})
+
+	// parts of not completed uploads are counted in the size limit
+	_, err = uploadPart(2, make([]byte, 6))
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrQuotaExceeded))
+	err = tc.putNamedObject("obj", make([]byte, 6))
+	require.True(t, apiErrors.IsS3Error(err, apiErrors.ErrQuotaExceeded))
+
+	_, _, err = tc.layer.CompleteMultipartUpload(tc.ctx, &CompleteMultipartParams{
+		Info:  uploadInfo,
+		Parts: []*CompletedPart{{ETag: etag, PartNumber: 1}},
+	})
+	require.NoError(t, err)
+	tc.checkUsage(data.BucketUsage{Size: 6, Objects: 1, CurrentObjects: 1})
+}
+
+func TestBucketUsageTracking(t *testing.T) {
+	tc := prepareContext(t)
+
+	err := tc.layer.PutBucketSettings(tc.ctx, &PutSettingsParams{
+		BktInfo:  tc.bktInfo,
+		Settings: &data.BucketSettings{Versioning: data.VersioningEnabled},
+	})
+	require.NoError(t, err)
+
+	tc.putObject([]byte("content"))
+
+	usage, err := tc.layer.SetBucketUsageTracking(tc.ctx, tc.bktInfo, true)
+	require.NoError(t, err)
+	require.Equal(t, &data.BucketUsage{Size: 7, Objects: 1, CurrentObjects: 1}, usage)
+
+	settings, err := tc.layer.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+
+	tc.deleteObject(tc.obj, "", settings)
+	tc.checkUsage(data.BucketUsage{Size: 7, Objects: 1, DeleteMarkers: 1})
+
+	versions := tc.listVersions()
+	require.Len(t, versions.DeleteMarker, 1)
+
+	// removing of the delete marker restores the object
+	tc.deleteObject(tc.obj, versions.DeleteMarker[0].ObjectInfo.VersionID(), settings)
+	tc.checkUsage(data.BucketUsage{Size: 7, Objects: 1, CurrentObjects: 1})
+
+	uploadInfo := &UploadInfoParams{UploadID: "upload", Bkt: tc.bktInfo, Key: "multipart"}
+	err = tc.layer.CreateMultipartUpload(tc.ctx, &CreateMultipartParams{Info: uploadInfo, Header: make(map[string]string)})
+	require.NoError(t, err)
+
+	uploadPart := func(content []byte) {
+		_, err := tc.layer.UploadPart(tc.ctx, &UploadPartParams{
+			Info:       uploadInfo,
+			PartNumber: 1,
+			Size:       int64(len(content)),
+			Reader:     bytes.NewReader(content),
+		})
+		require.NoError(t, err)
+	}
+
+	uploadPart([]byte("part"))
+	tc.checkUsage(data.BucketUsage{Size: 7, Objects: 1, CurrentObjects: 1, MultipartSize: 4})
+
+	// reupload of the part replaces its size
+	uploadPart([]byte("pt"))
+	tc.checkUsage(data.BucketUsage{Size: 7, Objects: 1, CurrentObjects: 1, MultipartSize: 2})
+
+	require.NoError(t, tc.layer.AbortMultipartUpload(tc.ctx, uploadInfo))
+	tc.checkUsage(data.BucketUsage{Size: 7, Objects: 1, CurrentObjects: 1})
+
+	_, err = tc.layer.SetBucketUsageTracking(tc.ctx, tc.bktInfo, false)
+	require.NoError(t, err)
+
+	err = tc.layer.PutBucketQuota(tc.ctx, &PutBucketQuotaParams{BktInfo: tc.bktInfo, Quota: &data.BucketQuota{MaxSize: 100}})
+	require.NoError(t, err)
+	_, err = tc.layer.SetBucketUsageTracking(tc.ctx, tc.bktInfo, false)
+	require.ErrorIs(t, err, ErrUsageRequired)
+}
+
+func TestBucketUsageTrackAll(t *testing.T) {
+	tc := prepareContext(t)
+	tc.layer.(*layer).trackUsage = true
+
+	// bucket is created before tracking of all buckets, so it has no usage node yet
+	tc.putObject([]byte("content"))
+
+	_, err := tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+	require.ErrorIs(t, err, ErrNodeNotFound)
+
+	require.Eventually(t, func() bool {
+		usage, err := tc.layer.GetBucketUsage(tc.ctx, tc.bktInfo)
+		return err == nil && *usage == data.BucketUsage{Size: 7, Objects: 1, CurrentObjects: 1}
+	}, time.Second, 10*time.Millisecond)
+
+	// usage is updated incrementally after the calculation
+	require.NoError(t, tc.putNamedObject("obj2", make([]byte, 3)))
+	tc.checkUsage(data.BucketUsage{Size: 10, Objects: 2, CurrentObjects: 2})
+
+	_, err = tc.layer.SetBucketUsageTracking(tc.ctx, tc.bktInfo, false)
+	require.ErrorIs(t, err, ErrUsageTrackedForAll)
+}
+
+// countingUsageTreeService counts updates of the bucket usage node.
+type countingUsageTreeService struct {
+	TreeService
+	puts int
+}
+
+func (c *countingUsageTreeService) PutBucketUsage(ctx context.Context, bktInfo *data.BucketInfo, usage *data.BucketUsage) error {
+	c.puts++
+	return c.TreeService.PutBucketUsage(ctx, bktInfo, usage)
+}
+
+func TestDeleteObjectsUsage(t *testing.T) {
+	tc := prepareContext(t)
+	n := tc.layer.(*layer)
+
+	_, err := tc.layer.SetBucketUsageTracking(tc.ctx, tc.bktInfo, true)
+	require.NoError(t, err)
+
+	objects := make([]*VersionedObject, 0, 10)
+	for i := 0; i < 10; i++ {
+		name := "obj" + strconv.Itoa(i)
+		require.NoError(t, tc.putNamedObject(name, make([]byte, 10)))
+		objects = append(objects, &VersionedObject{Name: name})
+	}
+	tc.checkUsage(data.BucketUsage{Size: 100, Objects: 10, CurrentObjects: 10})
+
+	settings, err := tc.layer.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+
+	counting := &countingUsageTreeService{TreeService: n.treeService}
+	n.treeService = counting
+
+	for _, obj := range tc.layer.DeleteObjects(tc.ctx, &DeleteObjectParams{BktInfo: tc.bktInfo, Settings: settings, Objects: objects}) {
+		require.NoError(t, obj.Error)
+	}
+
+	// usage is updated once per request
+	require.Equal(t, 1, counting.puts)
+	tc.checkUsage(data.BucketUsage{})
+}
@@ -0,0 +1,464 @@
+package layer
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"go.uber.org/zap"
+)
+
+type (
+	// RebuildBucketParams stores parameters of the bucket tree rebuild.
+	RebuildBucketParams struct {
+		BktInfo *data.BucketInfo
+		// DryRun only reports what would be restored without tree modifications.
+		DryRun bool
+		// MinAge is the minimal age of the object not referenced by the tree to add it.
+		// Younger objects can belong to the writes in progress which tree nodes aren't added yet.
+		MinAge time.Duration
+		// ExternalOnly skips objects created by the gateway. Not referenced objects of the gateway
+		// are left after failed writes or removals, so they must not become visible again.
+		ExternalOnly bool
+		// Cursor makes the search incremental if it's set. Only objects created since the epoch
+		// of the cursor are searched, the cursor is moved forward after the successful rebuild.
+		Cursor *RebuildCursor
+	}
+
+	// RebuildCursor stores the position of the incremental bucket import.
+	RebuildCursor struct {
+		// Epoch is the creation epoch of the objects to continue the search from,
+		// zero means the search of all objects.
+		Epoch uint64
+	}
+
+	// BucketRebuildResult is a result of the bucket tree rebuild.
+	BucketRebuildResult struct {
+		// Keys is the number of the restored or updated object keys.
+		Keys int
+		// Versions is the number of the restored version nodes.
+		Versions int
+		// Skipped is the number of the objects not referenced by the tree
+		// which are older than the latest version of their key in the tree.
+		Skipped int
+		// Postponed is the number of the objects younger than MinAge.
+		Postponed             int
+		SettingsRestored      bool
+		CORSRestored          bool
+		NotificationsRestored bool
+	}
+
+	// rebuildObject is an object version restored from the object header.
+	rebuildObject struct {
+		version   *data.NodeVersion
+		timestamp int64
+		epoch     uint64
+		gateway   bool
+	}
+)
+
+// GetImportCursor returns the position of the incremental import of the bucket stored in the tree.
+// Zero cursor is returned if the bucket hasn't been imported yet.
+func (n *layer) GetImportCursor(ctx context.Context, bktInfo *data.BucketInfo) (*RebuildCursor, error) {
+	epoch, err := n.treeService.GetImportCursor(ctx, bktInfo)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, err
+	}
+
+	return &RebuildCursor{Epoch: epoch}, nil
+}
+
+// PutImportCursor stores the position of the incremental import of the bucket in the tree,
+// so the import is continued by the restarted gateway or another gateway instance.
+func (n *layer) PutImportCursor(ctx context.Context, bktInfo *data.BucketInfo, cursor *RebuildCursor) error {
+	return n.treeService.PutImportCursor(ctx, bktInfo, cursor.Epoch)
+}
+
+// maxRebuildSearchEpochs is the maximal number of epochs searched one by one by the incremental
+// rebuild, all objects are searched at once if the cursor is older.
+const maxRebuildSearchEpochs = 64
+
+// RebuildBucketTree restores tree nodes of the bucket from attributes of NeoFS objects.
+// Objects are named by FilePath attribute or by FileName attribute if there is no path,
+// so containers populated by other NeoFS tools can be imported too. Objects not referenced
+// by the tree are added as new versions if they are newer than the latest version of the key,
+// so the rebuild can be repeated and doesn't reorder already existing versions. Bucket settings,
+// CORS and notification configuration are restored if they are missing in the tree.
+// Tags, ACL, object locks and delete markers are stored in the tree only, so they can't be restored.
+// Objects deleted in versioned buckets are kept in NeoFS, so they become current again if their
+// delete markers are lost, existing delete markers hide older objects of the key.
+func (n *layer) RebuildBucketTree(ctx context.Context, p *RebuildBucketParams) (*BucketRebuildResult, error) {
+	var (
+		currentEpoch uint64
+		epochs       []uint64
+		err          error
+	)
+	if p.Cursor != nil {
+		// the epoch is fetched before the search, so objects created during the rebuild are searched next time
+		if currentEpoch, err = n.currentEpoch(ctx); err != nil {
+			return nil, err
+		}
+		epochs = searchEpochs(p.Cursor.Epoch, currentEpoch)
+	}
+
+	versions, err := n.treeService.GetAllVersionsByPrefix(ctx, p.BktInfo, "")
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, fmt.Errorf("couldn't get bucket versions: %w", err)
+	}
+
+	referenced := make(map[oid.ID]struct{}, len(versions))
+	latestVersions := make(map[string]*data.NodeVersion, len(versions))
+	for _, version := range versions {
+		referenced[version.OID] = struct{}{}
+		if last, ok := latestVersions[version.FilePath]; !ok || last.Timestamp <= version.Timestamp {
+			latestVersions[version.FilePath] = version
+		}
+	}
+
+	objects, err := n.searchRebuildObjects(ctx, p.BktInfo, referenced, epochs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &BucketRebuildResult{}
+	keys := make(map[string][]*rebuildObject)
+	nextEpoch := currentEpoch
+	var cors, notifications *rebuildObject
+	for _, obj := range objects {
+		if p.ExternalOnly && obj.gateway {
+			continue
+		}
+		if p.MinAge > 0 && time.Since(time.Unix(obj.timestamp, 0)) < p.MinAge {
+			res.Postponed++
+			if obj.epoch < nextEpoch {
+				nextEpoch = obj.epoch
+			}
+			continue
+		}
+
+		switch obj.version.FilePath {
+		case p.BktInfo.CORSObjectName():
+			cors = latestRebuildObject(cors, obj)
+		case p.BktInfo.NotificationConfigurationObjectName():
+			notifications = latestRebuildObject(notifications, obj)
+		default:
+			keys[obj.version.FilePath] = append(keys[obj.version.FilePath], obj)
+		}
+	}
+
+	for name, group := range keys {
+		last, ok := latestVersions[name]
+		if !ok {
+			continue
+		}
+
+		lastTimestamp, err := n.versionTimestamp(ctx, p.BktInfo, last)
+		if err != nil {
+			return nil, err
+		}
+
+		newer := group[:0]
+		for _, obj := range group {
+			if obj.timestamp > lastTimestamp {
+				newer = append(newer, obj)
+			}
+		}
+		res.Skipped += len(group) - len(newer)
+
+		if len(newer) == 0 {
+			delete(keys, name)
+			continue
+		}
+		keys[name] = newer
+	}
+
+	if res.SettingsRestored, err = n.rebuildSettings(ctx, p, keys); err != nil {
+		return nil, err
+	}
+	if res.CORSRestored, err = n.rebuildSystemNode(ctx, p, cors, n.treeService.GetBucketCORS, n.treeService.PutBucketCORS); err != nil {
+		return nil, fmt.Errorf("couldn't restore bucket cors: %w", err)
+	}
+	if res.CORSRestored && !p.DryRun {
+		n.cache.DeleteCORS(p.BktInfo)
+	}
+	if res.NotificationsRestored, err = n.rebuildSystemNode(ctx, p, notifications,
+		n.treeService.GetNotificationConfigurationNode, n.treeService.PutNotificationConfigurationNode); err != nil {
+		return nil, fmt.Errorf("couldn't restore bucket notification configuration: %w", err)
+	}
+
+	settings, err := n.GetBucketSettings(ctx, p.BktInfo)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		group := keys[name]
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].timestamp != group[j].timestamp {
+				return group[i].timestamp < group[j].timestamp
+			}
+			if group[i].epoch != group[j].epoch {
+				return group[i].epoch < group[j].epoch
+			}
+			return group[i].version.OID.EncodeToString() < group[j].version.OID.EncodeToString()
+		})
+
+		// versions are ordered by the tree node timestamps, so they must be added from the oldest one;
+		// new versions of the existing keys are versioned not to replace unversioned ones
+		_, exists := latestVersions[name]
+		for _, obj := range group {
+			obj.version.IsUnversioned = len(group) == 1 && !exists && !settings.VersioningEnabled()
+			if !p.DryRun {
+				if _, err = n.treeService.AddVersion(ctx, p.BktInfo, obj.version); err != nil {
+					return nil, fmt.Errorf("couldn't add version of '%s': %w", name, err)
+				}
+			}
+			res.Versions++
+		}
+		res.Keys++
+
+		if !p.DryRun {
+			n.cache.CleanListCacheEntriesContainingObject(name, p.BktInfo.CID)
+			n.cache.DeleteObjectName(p.BktInfo.CID, p.BktInfo.Name, name)
+		}
+	}
+
+	if res.Versions > 0 && !p.DryRun {
+		n.refreshBucketUsage(ctx, p.BktInfo)
+	}
+
+	logFn := n.log.Debug
+	if res.Versions > 0 || res.SettingsRestored || res.CORSRestored || res.NotificationsRestored {
+		logFn = n.log.Info
+	}
+	logFn("bucket tree rebuilt", zap.String("bucket", p.BktInfo.Name), zap.Bool("dry_run", p.DryRun),
+		zap.Int("keys", res.Keys), zap.Int("versions", res.Versions), zap.Int("skipped", res.Skipped),
+		zap.Int("postponed", res.Postponed))
+
+	if p.Cursor != nil {
+		p.Cursor.Epoch = nextEpoch
+	}
+
+	return res, nil
+}
+
+// currentEpoch returns the current NeoFS epoch.
+func (n *layer) currentEpoch(ctx context.Context) (uint64, error) {
+	// any future time can be used, only the current epoch is needed
+	current, _, err := n.neoFS.TimeToEpoch(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get current epoch: %w", err)
+	}
+
+	return current, nil
+}
+
+// searchEpochs returns epochs to search objects created since the epoch one by one.
+// Nil is returned if all objects must be searched.
+func searchEpochs(since, current uint64) []uint64 {
+	if since == 0 || since > current || current-since >= maxRebuildSearchEpochs {
+		return nil
+	}
+
+	epochs := make([]uint64, 0, current-since+1)
+	for epoch := since; epoch <= current; epoch++ {
+		epochs = append(epochs, epoch)
+	}
+
+	return epochs
+}
+
+// searchRebuildObjects returns headers of the objects with file path or file name attribute
+// not referenced by the tree. Objects created in the provided epochs are searched only if
+// epochs are set. Parts of multipart uploads are ignored.
+func (n *layer) searchRebuildObjects(ctx context.Context, bktInfo *data.BucketInfo, referenced map[oid.ID]struct{}, epochs []uint64) ([]*rebuildObject, error) {
+	if epochs == nil {
+		epochs = []uint64{0}
+	}
+
+	found := make(map[oid.ID]struct{})
+	for _, attr := range []string{object.AttributeFilePath, object.AttributeFileName} {
+		for _, epoch := range epochs {
+			prm := PrmObjectSearch{
+				Container:     bktInfo.CID,
+				WithAttribute: attr,
+				CreationEpoch: epoch,
+			}
+			n.prepareAuthParameters(ctx, &prm.PrmAuth, bktInfo.Owner)
+
+			ids, err := n.neoFS.SearchObjects(ctx, prm)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't search objects with '%s' attribute: %w", attr, err)
+			}
+			for _, id := range ids {
+				if _, ok := referenced[id]; !ok {
+					found[id] = struct{}{}
+				}
+			}
+		}
+	}
+
+	res := make([]*rebuildObject, 0, len(found))
+	for id := range found {
+		head, err := n.objectHead(ctx, bktInfo, id)
+		if err != nil {
+			if isErrObjectRemoved(err) {
+				continue
+			}
+			return nil, fmt.Errorf("couldn't head object '%s': %w", id.EncodeToString(), err)
+		}
+
+		if obj := newRebuildObject(id, head); obj != nil {
+			res = append(res, obj)
+		}
+	}
+
+	return res, nil
+}
+
+func newRebuildObject(id oid.ID, head *object.Object) *rebuildObject {
+	payloadChecksum, _ := head.PayloadChecksum()
+	obj := &rebuildObject{
+		version: &data.NodeVersion{
+			BaseNodeVersion: data.BaseNodeVersion{
+				OID:  id,
+				Size: int64(head.PayloadSize()),
+				ETag: hex.EncodeToString(payloadChecksum.Value()),
+			},
+		},
+		epoch: head.CreationEpoch(),
+	}
+
+	var fileName string
+	for _, attr := range head.Attributes() {
+		switch attr.Key() {
+		case UploadIDAttributeName:
+			return nil
+		case object.AttributeFilePath:
+			obj.version.FilePath = attr.Value()
+		case object.AttributeFileName:
+			fileName = attr.Value()
+		case object.AttributeTimestamp:
+			obj.timestamp, _ = strconv.ParseInt(attr.Value(), 10, 64)
+		case AttributeDecryptedSize:
+			if size, err := strconv.ParseInt(attr.Value(), 10, 64); err == nil {
+				obj.version.Size = size
+			}
+		case AttributeStorageClass:
+			obj.version.StorageClass = attr.Value()
+		case AttributeGateway:
+			obj.gateway = true
+		}
+	}
+
+	if len(obj.version.FilePath) == 0 {
+		obj.version.FilePath = fileName
+	}
+	if len(obj.version.FilePath) == 0 {
+		return nil
+	}
+
+	return obj
+}
+
+// versionTimestamp returns creation time in seconds of the object version, zero is returned
+// if the version object is missing.
+func (n *layer) versionTimestamp(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) (int64, error) {
+	if version.IsDeleteMarker() {
+		return version.DeleteMarker.Created.Unix(), nil
+	}
+
+	head, err := n.objectHead(ctx, bktInfo, version.OID)
+	if err != nil {
+		if isErrObjectRemoved(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("couldn't head object '%s': %w", version.OID.EncodeToString(), err)
+	}
+
+	if obj := newRebuildObject(version.OID, head); obj != nil {
+		return obj.timestamp, nil
+	}
+	return 0, nil
+}
+
+func latestRebuildObject(current, obj *rebuildObject) *rebuildObject {
+	if current == nil || current.timestamp < obj.timestamp ||
+		current.timestamp == obj.timestamp && current.epoch < obj.epoch {
+		return obj
+	}
+	return current
+}
+
+// rebuildSettings restores bucket settings if they are missing in the tree. Versioning is
+// considered enabled if the bucket has object lock or several versions of the same key.
+func (n *layer) rebuildSettings(ctx context.Context, p *RebuildBucketParams, keys map[string][]*rebuildObject) (bool, error) {
+	_, err := n.treeService.GetSettingsNode(ctx, p.BktInfo)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, ErrNodeNotFound) {
+		return false, fmt.Errorf("couldn't get bucket settings: %w", err)
+	}
+
+	settings := &data.BucketSettings{Versioning: data.VersioningUnversioned}
+	if p.BktInfo.ObjectLockEnabled {
+		settings.Versioning = data.VersioningEnabled
+	}
+	for _, group := range keys {
+		if len(group) > 1 {
+			settings.Versioning = data.VersioningEnabled
+			break
+		}
+	}
+
+	if p.DryRun {
+		return true, nil
+	}
+
+	if err = n.PutBucketSettings(ctx, &PutSettingsParams{BktInfo: p.BktInfo, Settings: settings}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// rebuildSystemNode restores system tree node from the latest system object if the node is missing.
+func (n *layer) rebuildSystemNode(ctx context.Context, p *RebuildBucketParams, obj *rebuildObject,
+	get func(context.Context, *data.BucketInfo) (oid.ID, error),
+	put func(context.Context, *data.BucketInfo, oid.ID) (oid.ID, error)) (bool, error) {
+	if obj == nil {
+		return false, nil
+	}
+
+	_, err := get(ctx, p.BktInfo)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, ErrNodeNotFound) {
+		return false, err
+	}
+
+	if p.DryRun {
+		return true, nil
+	}
+
+	if _, err = put(ctx, p.BktInfo, obj.version.OID); err != nil && !errors.Is(err, ErrNoNodeToRemove) {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,165 @@
+package layer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	"github.com/stretchr/testify/require"
+)
+
+func (tc *testContext) rebuildBucket(dryRun bool) *BucketRebuildResult {
+	res, err := tc.layer.RebuildBucketTree(tc.ctx, &RebuildBucketParams{
+		BktInfo: tc.bktInfo,
+		DryRun:  dryRun,
+	})
+	require.NoError(tc.t, err)
+	return res
+}
+
+func TestRebuildBucketTree(t *testing.T) {
+	tc := prepareContext(t)
+
+	now := time.Now()
+	tc.putOrphanObject("dir/obj", now.Add(-2*time.Hour))
+	latest := tc.putOrphanObject("dir/obj", now.Add(-time.Hour))
+	single := tc.putOrphanObject("single", now)
+	cors := tc.putOrphanObject(tc.bktInfo.CORSObjectName(), now)
+
+	res := tc.rebuildBucket(true)
+	require.Equal(t, &BucketRebuildResult{Keys: 2, Versions: 3, SettingsRestored: true, CORSRestored: true}, res)
+	require.Empty(t, tc.listVersions().Version)
+
+	res = tc.rebuildBucket(false)
+	require.Equal(t, &BucketRebuildResult{Keys: 2, Versions: 3, SettingsRestored: true, CORSRestored: true}, res)
+
+	settings, err := tc.layer.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.True(t, settings.VersioningEnabled())
+
+	corsID, err := tc.layer.(*layer).treeService.GetBucketCORS(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, cors, corsID)
+
+	objInfo, err := tc.layer.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: tc.bktInfo, Object: "dir/obj"})
+	require.NoError(t, err)
+	require.Equal(t, latest, objInfo.ID)
+
+	objInfo, err = tc.layer.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: tc.bktInfo, Object: "single"})
+	require.NoError(t, err)
+	require.Equal(t, single, objInfo.ID)
+	require.Len(t, tc.listVersions().Version, 3)
+
+	// objects older than the latest version are skipped, newer ones are added
+	tc.putOrphanObject("single", now.Add(-time.Hour))
+	newer := tc.putOrphanObject("single", now.Add(time.Hour))
+	res = tc.rebuildBucket(false)
+	require.Equal(t, &BucketRebuildResult{Keys: 1, Versions: 1, Skipped: 1}, res)
+	require.Len(t, tc.listVersions().Version, 4)
+
+	objInfo, err = tc.layer.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: tc.bktInfo, Object: "single"})
+	require.NoError(t, err)
+	require.Equal(t, newer, objInfo.ID)
+
+	res = tc.rebuildBucket(false)
+	require.Equal(t, &BucketRebuildResult{Skipped: 1}, res)
+}
+
+func TestRebuildBucketTreeFileName(t *testing.T) {
+	tc := prepareContext(t)
+
+	imported, err := tc.testNeoFS.CreateObject(tc.ctx, PrmObjectCreate{
+		Container:  tc.bktInfo.CID,
+		Creator:    tc.bktInfo.Owner,
+		Attributes: [][2]string{{object.AttributeFileName, "photo.jpg"}},
+	})
+	require.NoError(t, err)
+
+	// objects without names can't be imported
+	_, err = tc.testNeoFS.CreateObject(tc.ctx, PrmObjectCreate{Container: tc.bktInfo.CID, Creator: tc.bktInfo.Owner})
+	require.NoError(t, err)
+
+	res := tc.rebuildBucket(false)
+	require.Equal(t, &BucketRebuildResult{Keys: 1, Versions: 1, SettingsRestored: true}, res)
+
+	objInfo, err := tc.layer.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: tc.bktInfo, Object: "photo.jpg"})
+	require.NoError(t, err)
+	require.Equal(t, imported, objInfo.ID)
+	require.Equal(t, "photo.jpg", objInfo.Name)
+}
+
+func TestRebuildBucketTreeUnversioned(t *testing.T) {
+	tc := prepareContext(t)
+
+	tc.putOrphanObject("obj1", time.Now())
+	tc.putOrphanObject("obj2", time.Now())
+
+	res := tc.rebuildBucket(false)
+	require.Equal(t, &BucketRebuildResult{Keys: 2, Versions: 2, SettingsRestored: true}, res)
+
+	settings, err := tc.layer.GetBucketSettings(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.False(t, settings.VersioningEnabled())
+
+	for _, version := range tc.listVersions().Version {
+		require.Equal(t, data.UnversionedObjectVersionID, version.Version())
+	}
+}
+
+func TestRebuildBucketTreeImport(t *testing.T) {
+	tc := prepareContext(t)
+
+	now := time.Now()
+	tc.putOrphanObject("gateway", now.Add(-2*time.Hour), [2]string{AttributeGateway, "true"})
+	tc.putOrphanObject("old", now.Add(-2*time.Hour))
+	tc.putOrphanObject("young", now)
+
+	rebuild := func(cursor *RebuildCursor) *BucketRebuildResult {
+		res, err := tc.layer.RebuildBucketTree(tc.ctx, &RebuildBucketParams{
+			BktInfo:      tc.bktInfo,
+			MinAge:       time.Hour,
+			ExternalOnly: true,
+			Cursor:       cursor,
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	// objects of the gateway aren't imported, young objects are postponed
+	cursor := &RebuildCursor{}
+	res := rebuild(cursor)
+	require.Equal(t, &BucketRebuildResult{Keys: 1, Versions: 1, Postponed: 1, SettingsRestored: true}, res)
+	youngEpoch := tc.testNeoFS.CurrentEpoch() - 1
+	require.Equal(t, youngEpoch, cursor.Epoch)
+
+	_, err := tc.layer.GetObjectInfo(tc.ctx, &HeadObjectParams{BktInfo: tc.bktInfo, Object: "gateway"})
+	require.Error(t, err)
+
+	tc.putOrphanObject("next", now.Add(-2*time.Hour))
+	res = rebuild(cursor)
+	require.Equal(t, &BucketRebuildResult{Keys: 1, Versions: 1, Postponed: 1}, res)
+	require.Equal(t, youngEpoch, cursor.Epoch)
+	require.Len(t, tc.listVersions().Version, 2)
+}
+
+func TestImportCursor(t *testing.T) {
+	tc := prepareContext(t)
+
+	cursor, err := tc.layer.GetImportCursor(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, &RebuildCursor{}, cursor)
+
+	require.NoError(t, tc.layer.PutImportCursor(tc.ctx, tc.bktInfo, &RebuildCursor{Epoch: 42}))
+	cursor, err = tc.layer.GetImportCursor(tc.ctx, tc.bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, &RebuildCursor{Epoch: 42}, cursor)
+}
+
+func TestSearchEpochs(t *testing.T) {
+	require.Nil(t, searchEpochs(0, 10))
+	require.Nil(t, searchEpochs(11, 10))
+	require.Nil(t, searchEpochs(10, 10+maxRebuildSearchEpochs))
+	require.Equal(t, []uint64{10}, searchEpochs(10, 10))
+	require.Equal(t, []uint64{8, 9, 10}, searchEpochs(8, 10))
+}
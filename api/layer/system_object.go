@@ -24,6 +24,8 @@ type PutLockInfoParams struct {
 	NewLock      *data.ObjectLock
 	CopiesNumber uint32
 	NodeVersion  *data.NodeVersion // optional
+	// NewNodeVersion is set if NodeVersion has just been created and has no lock yet.
+	NewNodeVersion bool
 }
 
 func (n *layer) PutLockInfo(ctx context.Context, p *PutLockInfoParams) (err error) {
@@ -38,9 +40,12 @@ func (n *layer) PutLockInfo(ctx context.Context, p *PutLockInfoParams) (err erro
 		}
 	}
 
-	lockInfo, err := n.treeService.GetLock(ctx, p.ObjVersion.BktInfo, versionNode.ID)
-	if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
-		return err
+	var lockInfo *data.LockInfo
+	if !p.NewNodeVersion {
+		lockInfo, err = n.treeService.GetLock(ctx, p.ObjVersion.BktInfo, versionNode.ID)
+		if err != nil && !errorsStd.Is(err, ErrNodeNotFound) {
+			return err
+		}
 	}
 
 	if lockInfo == nil {
@@ -96,13 +101,14 @@ func (n *layer) PutLockInfo(ctx context.Context, p *PutLockInfoParams) (err erro
 	}
 
 	n.cache.PutLockInfo(n.Owner(ctx), lockObjectKey(p.ObjVersion), lockInfo)
+	n.cache.publishSystem(lockObjectKey(p.ObjVersion))
 
 	return nil
 }
 
 func (n *layer) getNodeVersionFromCacheOrNeofs(ctx context.Context, objVersion *ObjectVersion) (nodeVersion *data.NodeVersion, err error) {
 	// check cache if node version is stored inside extendedObjectVersion
-	nodeVersion = n.getNodeVersionFromCache(n.Owner(ctx), objVersion)
+	nodeVersion = n.getNodeVersionFromCache(ctx, n.Owner(ctx), objVersion)
 	if nodeVersion == nil {
 		// else get node version from tree service
 		return n.getNodeVersion(ctx, objVersion)
@@ -131,7 +137,7 @@ func (n *layer) putLockObject(ctx context.Context, bktInfo *data.BucketInfo, obj
 
 func (n *layer) GetLockInfo(ctx context.Context, objVersion *ObjectVersion) (*data.LockInfo, error) {
 	owner := n.Owner(ctx)
-	if lockInfo := n.cache.GetLockInfo(owner, lockObjectKey(objVersion)); lockInfo != nil {
+	if lockInfo := n.cache.GetLockInfo(ctx, owner, lockObjectKey(objVersion)); lockInfo != nil {
 		return lockInfo, nil
 	}
 
@@ -155,7 +161,7 @@ func (n *layer) GetLockInfo(ctx context.Context, objVersion *ObjectVersion) (*da
 
 func (n *layer) getCORS(ctx context.Context, bkt *data.BucketInfo) (*data.CORSConfiguration, error) {
 	owner := n.Owner(ctx)
-	if cors := n.cache.GetCORS(owner, bkt); cors != nil {
+	if cors := n.cache.GetCORS(ctx, owner, bkt); cors != nil {
 		return cors, nil
 	}
 
@@ -192,7 +198,7 @@ func lockObjectKey(objVersion *ObjectVersion) string {
 
 func (n *layer) GetBucketSettings(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error) {
 	owner := n.Owner(ctx)
-	if settings := n.cache.GetSettings(owner, bktInfo); settings != nil {
+	if settings := n.cache.GetSettings(ctx, owner, bktInfo); settings != nil {
 		return settings, nil
 	}
 
@@ -215,6 +221,7 @@ func (n *layer) PutBucketSettings(ctx context.Context, p *PutSettingsParams) err
 	}
 
 	n.cache.PutSettings(n.Owner(ctx), p.BktInfo, p.Settings)
+	n.cache.publishSystem(settingsCacheKey(p.BktInfo))
 
 	return nil
 }
@@ -31,7 +31,7 @@ func (n *layer) GetObjectTagging(ctx context.Context, p *GetObjectTaggingParams)
 	owner := n.Owner(ctx)
 
 	if len(p.ObjectVersion.VersionID) != 0 && p.ObjectVersion.VersionID != data.UnversionedObjectVersionID {
-		if tags := n.cache.GetTagging(owner, objectTaggingCacheKey(p.ObjectVersion)); tags != nil {
+		if tags := n.cache.GetTagging(ctx, owner, objectTaggingCacheKey(p.ObjectVersion)); tags != nil {
 			return p.ObjectVersion.VersionID, tags, nil
 		}
 	}
@@ -45,7 +45,7 @@ func (n *layer) GetObjectTagging(ctx context.Context, p *GetObjectTaggingParams)
 	}
 	p.ObjectVersion.VersionID = nodeVersion.OID.EncodeToString()
 
-	if tags := n.cache.GetTagging(owner, objectTaggingCacheKey(p.ObjectVersion)); tags != nil {
+	if tags := n.cache.GetTagging(ctx, owner, objectTaggingCacheKey(p.ObjectVersion)); tags != nil {
 		return p.ObjectVersion.VersionID, tags, nil
 	}
 
@@ -81,6 +81,7 @@ func (n *layer) PutObjectTagging(ctx context.Context, p *PutObjectTaggingParams)
 	}
 
 	n.cache.PutTagging(n.Owner(ctx), objectTaggingCacheKey(p.ObjectVersion), p.TagSet)
+	n.cache.publishSystem(objectTaggingCacheKey(p.ObjectVersion))
 
 	return nodeVersion, nil
 }
@@ -109,7 +110,7 @@ func (n *layer) DeleteObjectTagging(ctx context.Context, p *ObjectVersion) (*dat
 func (n *layer) GetBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) (map[string]string, error) {
 	owner := n.Owner(ctx)
 
-	if tags := n.cache.GetTagging(owner, bucketTaggingCacheKey(bktInfo.CID)); tags != nil {
+	if tags := n.cache.GetTagging(ctx, owner, bucketTaggingCacheKey(bktInfo.CID)); tags != nil {
 		return tags, nil
 	}
 
@@ -129,6 +130,7 @@ func (n *layer) PutBucketTagging(ctx context.Context, bktInfo *data.BucketInfo,
 	}
 
 	n.cache.PutTagging(n.Owner(ctx), bucketTaggingCacheKey(bktInfo.CID), tagSet)
+	n.cache.publishSystem(bucketTaggingCacheKey(bktInfo.CID))
 
 	return nil
 }
@@ -178,7 +180,7 @@ func (n *layer) getNodeVersion(ctx context.Context, objVersion *ObjectVersion) (
 	return version, err
 }
 
-func (n *layer) getNodeVersionFromCache(owner user.ID, o *ObjectVersion) *data.NodeVersion {
+func (n *layer) getNodeVersionFromCache(ctx context.Context, owner user.ID, o *ObjectVersion) *data.NodeVersion {
 	if len(o.VersionID) == 0 || o.VersionID == data.UnversionedObjectVersionID {
 		return nil
 	}
@@ -192,7 +194,7 @@ func (n *layer) getNodeVersionFromCache(owner user.ID, o *ObjectVersion) *data.N
 	addr.SetContainer(o.BktInfo.CID)
 	addr.SetObject(objID)
 
-	extObjectInfo := n.cache.GetObject(owner, addr)
+	extObjectInfo := n.cache.GetObject(ctx, owner, addr)
 	if extObjectInfo == nil {
 		return nil
 	}
@@ -2,6 +2,7 @@ package layer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
@@ -11,13 +12,19 @@ import (
 )
 
 type TreeServiceMock struct {
-	settings   map[string]*data.BucketSettings
-	versions   map[string]map[string][]*data.NodeVersion
-	system     map[string]map[string]*data.BaseNodeVersion
-	locks      map[string]map[uint64]*data.LockInfo
-	tags       map[string]map[uint64]map[string]string
-	multiparts map[string]map[string][]*data.MultipartInfo
-	parts      map[string]map[int]*data.PartInfo
+	settings      map[string]*data.BucketSettings
+	usage         map[string]*data.BucketUsage
+	imports       map[string]uint64
+	versions      map[string]map[string][]*data.NodeVersion
+	system        map[string]map[string]*data.BaseNodeVersion
+	locks         map[string]map[uint64]*data.LockInfo
+	tags          map[string]map[uint64]map[string]string
+	multiparts    map[string]map[string][]*data.MultipartInfo
+	parts         map[string]map[int]*data.PartInfo
+	cors          map[string]oid.ID
+	notifications map[string]oid.ID
+	// lastID is the last node ID of versions, node IDs are unique within the tree like in tree service.
+	lastID uint64
 }
 
 func (t *TreeServiceMock) GetObjectTaggingAndLock(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, *data.LockInfo, error) {
@@ -76,13 +83,17 @@ func (t *TreeServiceMock) DeleteBucketTagging(ctx context.Context, bktInfo *data
 
 func NewTreeService() *TreeServiceMock {
 	return &TreeServiceMock{
-		settings:   make(map[string]*data.BucketSettings),
-		versions:   make(map[string]map[string][]*data.NodeVersion),
-		system:     make(map[string]map[string]*data.BaseNodeVersion),
-		locks:      make(map[string]map[uint64]*data.LockInfo),
-		tags:       make(map[string]map[uint64]map[string]string),
-		multiparts: make(map[string]map[string][]*data.MultipartInfo),
-		parts:      make(map[string]map[int]*data.PartInfo),
+		settings:      make(map[string]*data.BucketSettings),
+		usage:         make(map[string]*data.BucketUsage),
+		imports:       make(map[string]uint64),
+		versions:      make(map[string]map[string][]*data.NodeVersion),
+		system:        make(map[string]map[string]*data.BaseNodeVersion),
+		locks:         make(map[string]map[uint64]*data.LockInfo),
+		tags:          make(map[string]map[uint64]map[string]string),
+		multiparts:    make(map[string]map[string][]*data.MultipartInfo),
+		parts:         make(map[string]map[int]*data.PartInfo),
+		cors:          make(map[string]oid.ID),
+		notifications: make(map[string]oid.ID),
 	}
 }
 
@@ -100,24 +111,79 @@ func (t *TreeServiceMock) GetSettingsNode(_ context.Context, bktInfo *data.Bucke
 	return settings, nil
 }
 
-func (t *TreeServiceMock) GetNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetBucketUsage(_ context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error) {
+	usage, ok := t.usage[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return nil, ErrNodeNotFound
+	}
+
+	usageCopy := *usage
+	return &usageCopy, nil
 }
 
-func (t *TreeServiceMock) PutNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) PutBucketUsage(_ context.Context, bktInfo *data.BucketInfo, usage *data.BucketUsage) error {
+	usageCopy := *usage
+	t.usage[bktInfo.CID.EncodeToString()] = &usageCopy
+	return nil
 }
 
-func (t *TreeServiceMock) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetImportCursor(_ context.Context, bktInfo *data.BucketInfo) (uint64, error) {
+	epoch, ok := t.imports[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return 0, ErrNodeNotFound
+	}
+
+	return epoch, nil
 }
 
-func (t *TreeServiceMock) PutBucketCORS(ctx context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) PutImportCursor(_ context.Context, bktInfo *data.BucketInfo, epoch uint64) error {
+	t.imports[bktInfo.CID.EncodeToString()] = epoch
+	return nil
 }
 
-func (t *TreeServiceMock) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetNotificationConfigurationNode(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	return getSystemObject(t.notifications, bktInfo)
+}
+
+func (t *TreeServiceMock) PutNotificationConfigurationNode(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	return putSystemObject(t.notifications, bktInfo, objID)
+}
+
+func (t *TreeServiceMock) GetBucketCORS(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	return getSystemObject(t.cors, bktInfo)
+}
+
+func (t *TreeServiceMock) PutBucketCORS(_ context.Context, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	return putSystemObject(t.cors, bktInfo, objID)
+}
+
+func (t *TreeServiceMock) DeleteBucketCORS(_ context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
+	objID, err := getSystemObject(t.cors, bktInfo)
+	if err != nil {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	delete(t.cors, bktInfo.CID.EncodeToString())
+	return objID, nil
+}
+
+func getSystemObject(objects map[string]oid.ID, bktInfo *data.BucketInfo) (oid.ID, error) {
+	objID, ok := objects[bktInfo.CID.EncodeToString()]
+	if !ok {
+		return oid.ID{}, ErrNodeNotFound
+	}
+
+	return objID, nil
+}
+
+func putSystemObject(objects map[string]oid.ID, bktInfo *data.BucketInfo, objID oid.ID) (oid.ID, error) {
+	prev, ok := objects[bktInfo.CID.EncodeToString()]
+	objects[bktInfo.CID.EncodeToString()] = objID
+	if !ok {
+		return oid.ID{}, ErrNoNodeToRemove
+	}
+
+	return prev, nil
 }
 
 func (t *TreeServiceMock) GetVersions(_ context.Context, bktInfo *data.BucketInfo, objectName string) ([]*data.NodeVersion, error) {
@@ -131,7 +197,8 @@ func (t *TreeServiceMock) GetVersions(_ context.Context, bktInfo *data.BucketInf
 		return nil, ErrNodeNotFound
 	}
 
-	return versions, nil
+	// the stored slice is modified in place on removal
+	return append([]*data.NodeVersion(nil), versions...), nil
 }
 
 func (t *TreeServiceMock) GetLatestVersion(_ context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
@@ -202,6 +269,9 @@ func (t *TreeServiceMock) GetUnversioned(_ context.Context, bktInfo *data.Bucket
 }
 
 func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion) (uint64, error) {
+	t.lastID++
+	newVersion.ID = t.lastID
+
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
 		t.versions[bktInfo.CID.EncodeToString()] = map[string][]*data.NodeVersion{
@@ -221,7 +291,6 @@ func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo
 	})
 
 	if len(versions) != 0 {
-		newVersion.ID = versions[len(versions)-1].ID + 1
 		newVersion.Timestamp = versions[len(versions)-1].Timestamp + 1
 	}
 
@@ -241,6 +310,48 @@ func (t *TreeServiceMock) AddVersion(_ context.Context, bktInfo *data.BucketInfo
 	return newVersion.ID, nil
 }
 
+func (t *TreeServiceMock) AddVersionWithTagging(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion, tagSet map[string]string) (uint64, error) {
+	nodeID, err := t.AddVersion(ctx, bktInfo, newVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(tagSet) == 0 {
+		return nodeID, t.DeleteObjectTagging(ctx, bktInfo, newVersion)
+	}
+
+	return nodeID, t.PutObjectTagging(ctx, bktInfo, newVersion, tagSet)
+}
+
+func (t *TreeServiceMock) GetLatestAndUnversioned(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, *data.NodeVersion, error) {
+	latest, err := t.GetLatestVersion(ctx, bktInfo, objectName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unversioned, err := t.GetUnversioned(ctx, bktInfo, objectName)
+	if err != nil && !errors.Is(err, ErrNodeNotFound) {
+		return nil, nil, err
+	}
+
+	return latest, unversioned, nil
+}
+
+func (t *TreeServiceMock) AddVersionIfUnchanged(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion, tagSet map[string]string, expected *data.NodeVersion) (uint64, error) {
+	if newVersion.IsUnversioned {
+		node, err := t.GetUnversioned(ctx, bktInfo, newVersion.FilePath)
+		if err != nil && !errors.Is(err, ErrNodeNotFound) {
+			return 0, err
+		}
+
+		if (node == nil) != (expected == nil) || (node != nil && (node.ID != expected.ID || !node.OID.Equals(expected.OID))) {
+			return 0, ErrNodeChanged
+		}
+	}
+
+	return t.AddVersionWithTagging(ctx, bktInfo, newVersion, tagSet)
+}
+
 func (t *TreeServiceMock) RemoveVersion(_ context.Context, bktInfo *data.BucketInfo, nodeID uint64) error {
 	cnrVersionsMap, ok := t.versions[bktInfo.CID.EncodeToString()]
 	if !ok {
@@ -293,8 +404,15 @@ func (t *TreeServiceMock) CreateMultipartUpload(_ context.Context, bktInfo *data
 	return nil
 }
 
-func (t *TreeServiceMock) GetMultipartUploadsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.MultipartInfo, error) {
-	panic("implement me")
+func (t *TreeServiceMock) GetMultipartUploadsByPrefix(_ context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.MultipartInfo, error) {
+	var result []*data.MultipartInfo
+	for key, multiparts := range t.multiparts[bktInfo.CID.EncodeToString()] {
+		if strings.HasPrefix(key, prefix) {
+			result = append(result, multiparts...)
+		}
+	}
+
+	return result, nil
 }
 
 func (t *TreeServiceMock) GetMultipartUpload(_ context.Context, bktInfo *data.BucketInfo, objectName, uploadID string) (*data.MultipartInfo, error) {
@@ -18,6 +18,22 @@ type TreeService interface {
 	// If tree node is not found returns ErrNodeNotFound error.
 	GetSettingsNode(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error)
 
+	// GetBucketUsage retrieves the bucket usage node from the tree service.
+	//
+	// If tree node is not found returns ErrNodeNotFound error.
+	GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error)
+
+	// PutBucketUsage update or create new bucket usage node in tree service.
+	PutBucketUsage(ctx context.Context, bktInfo *data.BucketInfo, usage *data.BucketUsage) error
+
+	// GetImportCursor retrieves the epoch the incremental bucket import continues from.
+	//
+	// If tree node is not found returns ErrNodeNotFound error.
+	GetImportCursor(ctx context.Context, bktInfo *data.BucketInfo) (uint64, error)
+
+	// PutImportCursor update or create new bucket import node in tree service.
+	PutImportCursor(ctx context.Context, bktInfo *data.BucketInfo, epoch uint64) error
+
 	// GetNotificationConfigurationNode gets an object id that corresponds to object with bucket CORS.
 	//
 	// If tree node is not found returns ErrNodeNotFound error.
@@ -58,6 +74,15 @@ type TreeService interface {
 	GetAllVersionsByPrefix(ctx context.Context, bktInfo *data.BucketInfo, prefix string) ([]*data.NodeVersion, error)
 	GetUnversioned(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error)
 	AddVersion(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion) (uint64, error)
+	// AddVersionWithTagging adds a new version like AddVersion and then stores the tag set of the version.
+	// The new version is removed if its tags can't be stored. Tags of the replaced unversioned
+	// node are removed if the tag set is empty or can't be updated. If the version is stored,
+	// but its tags aren't, ID of the version node is returned along with ErrVersionTagging.
+	AddVersionWithTagging(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion, tagSet map[string]string) (uint64, error)
+	// AddVersionIfUnchanged adds a new version like AddVersionWithTagging. If the new version is unversioned,
+	// the unversioned node is re-read right before it's replaced, and ErrNodeChanged is returned if it
+	// isn't the expected one (nil expected version means that there must be no unversioned node).
+	AddVersionIfUnchanged(ctx context.Context, bktInfo *data.BucketInfo, newVersion *data.NodeVersion, tagSet map[string]string, expected *data.NodeVersion) (uint64, error)
 	RemoveVersion(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) error
 
 	PutLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64, lock *data.LockInfo) error
@@ -79,6 +104,12 @@ type TreeService interface {
 
 	// GetObjectTaggingAndLock unifies GetObjectTagging and GetLock methods in single tree service invocation.
 	GetObjectTaggingAndLock(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) (map[string]string, *data.LockInfo, error)
+
+	// GetLatestAndUnversioned unifies GetLatestVersion and GetUnversioned methods in single tree service invocation.
+	// Unversioned version is nil if there is no such version.
+	//
+	// If the object has no versions returns ErrNodeNotFound error.
+	GetLatestAndUnversioned(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (latest, unversioned *data.NodeVersion, err error)
 }
 
 var (
@@ -90,4 +121,10 @@ var (
 
 	// ErrNoNodeToRemove is returned from Tree service in case of the lack of node with OID to remove.
 	ErrNoNodeToRemove = errors.New("no node to remove")
+
+	// ErrVersionTagging is returned from Tree service in case the version is stored, but its tags aren't.
+	ErrVersionTagging = errors.New("version is stored without tags")
+
+	// ErrNodeChanged is returned from Tree service in case the node to replace differs from the expected one.
+	ErrNodeChanged = errors.New("node changed")
 )
@@ -121,12 +121,21 @@ func addEncryptionHeaders(meta map[string]string, enc encryption.Params) error {
 	return nil
 }
 
+// filepathFromObject returns object name from FilePath attribute. FileName attribute is used
+// for objects without path (e.g. imported from containers populated by other NeoFS tools).
 func filepathFromObject(o *object.Object) string {
+	var fileName string
 	for _, attr := range o.Attributes() {
-		if attr.Key() == object.AttributeFilePath {
+		switch attr.Key() {
+		case object.AttributeFilePath:
 			return attr.Value()
+		case object.AttributeFileName:
+			fileName = attr.Value()
 		}
 	}
+	if len(fileName) > 0 {
+		return fileName
+	}
 	objID, _ := o.ID()
 	return objID.EncodeToString()
 }
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
@@ -11,9 +12,13 @@ type (
 	// MaxClients provides HTTP handler wrapper with the client limit.
 	MaxClients interface {
 		Handle(http.HandlerFunc) http.HandlerFunc
+		// Update replaces the limits. Requests in progress are counted in the previous
+		// limit, so the total number of requests can exceed the new one until they finish.
+		Update(count int, timeout time.Duration)
 	}
 
 	maxClients struct {
+		mu      sync.RWMutex
 		pool    chan struct{}
 		timeout time.Duration
 	}
@@ -37,17 +42,21 @@ func NewMaxClientsMiddleware(count int, timeout time.Duration) MaxClients {
 // Handler wraps HTTP handler function with logic limiting access to it.
 func (m *maxClients) Handle(f http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if m.pool == nil {
+		m.mu.RLock()
+		pool, timeout := m.pool, m.timeout
+		m.mu.RUnlock()
+
+		if pool == nil {
 			f.ServeHTTP(w, r)
 			return
 		}
 
-		deadline := time.NewTimer(m.timeout)
+		deadline := time.NewTimer(timeout)
 		defer deadline.Stop()
 
 		select {
-		case m.pool <- struct{}{}:
-			defer func() { <-m.pool }()
+		case pool <- struct{}{}:
+			defer func() { <-pool }()
 			f.ServeHTTP(w, r)
 		case <-deadline.C:
 			// Send a http timeout message
@@ -58,3 +67,18 @@ func (m *maxClients) Handle(f http.HandlerFunc) http.HandlerFunc {
 		}
 	}
 }
+
+// Update replaces the limits of the concurrent requests.
+func (m *maxClients) Update(count int, timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = defaultRequestDeadline
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cap(m.pool) != count {
+		m.pool = make(chan struct{}, count)
+	}
+	m.timeout = timeout
+}
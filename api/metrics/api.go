@@ -45,6 +45,7 @@ type (
 		http.ResponseWriter
 
 		statusCode int
+		errorCode  string
 		startTime  time.Time
 	}
 )
@@ -113,6 +114,7 @@ func APIStats(api string, f http.HandlerFunc) http.HandlerFunc {
 		out := &writeCounter{ResponseWriter: w}
 
 		r.Body = in
+		r, bucket := withBucketLabel(r)
 
 		statsWriter := &responseWrapper{
 			ResponseWriter: out,
@@ -127,6 +129,7 @@ func APIStats(api string, f http.HandlerFunc) http.HandlerFunc {
 		durationSecs := time.Since(statsWriter.startTime).Seconds()
 
 		httpStatsMetric.updateStats(api, statsWriter, r, durationSecs)
+		updateLabelledStats(api, statsWriter, r, bucket, in.countBytes, out.countBytes, durationSecs)
 
 		atomic.AddUint64(&httpStatsMetric.totalInputBytes, in.countBytes)
 		atomic.AddUint64(&httpStatsMetric.totalOutputBytes, out.countBytes)
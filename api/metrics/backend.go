@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	backendDurationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+	// NeoFS metrics have no node label unlike the tree service ones: the connection pool
+	// doesn't expose the storage node the request is sent to, only aggregated node statistic.
+	neofsRequestsDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "neofs_s3",
+			Subsystem: "neofs",
+			Name:      "request_seconds",
+			Help:      "Time taken by NeoFS requests of current NeoFS S3 Gate instance by method (all storage nodes)",
+			Buckets:   backendDurationBuckets,
+		},
+		[]string{"method"},
+	)
+
+	neofsErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Subsystem: "neofs",
+			Name:      "errors_total",
+			Help:      "Total number of failed NeoFS requests of current NeoFS S3 Gate instance by method (all storage nodes)",
+		},
+		[]string{"method"},
+	)
+
+	neofsFirstByte = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "neofs_s3",
+			Subsystem: "neofs",
+			Name:      "first_byte_seconds",
+			Help:      "Time to the first byte of the object payload read from NeoFS by current NeoFS S3 Gate instance",
+			Buckets:   backendDurationBuckets,
+		},
+		[]string{"method"},
+	)
+
+	treeRequestsDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "neofs_s3",
+			Subsystem: "tree",
+			Name:      "request_seconds",
+			Help:      "Time taken by tree service requests of current NeoFS S3 Gate instance by endpoint and method",
+			Buckets:   backendDurationBuckets,
+		},
+		[]string{"node", "method"},
+	)
+
+	treeErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Subsystem: "tree",
+			Name:      "errors_total",
+			Help:      "Total number of failed tree service requests of current NeoFS S3 Gate instance by endpoint and method",
+		},
+		[]string{"node", "method"},
+	)
+
+	treeSubTreeNodes = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "neofs_s3",
+			Subsystem: "tree",
+			Name:      "subtree_nodes",
+			Help:      "Number of nodes returned by tree service GetSubTree requests by endpoint",
+			Buckets:   prometheus.ExponentialBuckets(1, 4, 10),
+		},
+		[]string{"node"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(neofsRequestsDuration, neofsErrors, neofsFirstByte,
+		treeRequestsDuration, treeErrors, treeSubTreeNodes)
+}
+
+// ObserveNeoFSRequest records the duration of the NeoFS request and counts it as failed if failed is true.
+func ObserveNeoFSRequest(method string, duration time.Duration, failed bool) {
+	neofsRequestsDuration.WithLabelValues(method).Observe(duration.Seconds())
+	if failed {
+		neofsErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// ObserveNeoFSFirstByte records the time from the start of the NeoFS request to the first byte of the payload.
+func ObserveNeoFSFirstByte(method string, duration time.Duration) {
+	neofsFirstByte.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// ObserveTreeRequest records the duration of the request to the tree service endpoint
+// and counts it as failed if failed is true.
+func ObserveTreeRequest(node, method string, duration time.Duration, failed bool) {
+	treeRequestsDuration.WithLabelValues(node, method).Observe(duration.Seconds())
+	if failed {
+		treeErrors.WithLabelValues(node, method).Inc()
+	}
+}
+
+// ObserveTreeSubTreeSize records the number of nodes returned by the GetSubTree request to the tree service endpoint.
+func ObserveTreeSubTreeSize(node string, nodes int) {
+	treeSubTreeNodes.WithLabelValues(node).Observe(float64(nodes))
+}
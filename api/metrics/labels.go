@@ -0,0 +1,253 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LabelConfig configures request metrics labelled by the bucket or by the user.
+type LabelConfig struct {
+	// Enabled turns the metrics on.
+	Enabled bool
+
+	// Allowed values are reported with own label value, other values are reported as OtherLabelValue.
+	// All values are allowed if the list is empty.
+	Allowed []string
+
+	// MaxValues limits the number of distinct label values, values seen after the limit is reached
+	// are reported as OtherLabelValue. There is no limit if it's zero.
+	MaxValues int
+}
+
+// OtherLabelValue is reported instead of the bucket or the user which isn't allowed or exceeds the limit.
+const OtherLabelValue = "other"
+
+// AnonymousUser is a user label value of the requests without credentials.
+const AnonymousUser = "anonymous"
+
+type (
+	userContextKey   struct{}
+	bucketContextKey struct{}
+)
+
+// bucketLabel is the bucket of the request. The bucket is reported only after it's resolved,
+// so requests to nonexistent buckets don't take label values.
+type bucketLabel struct {
+	name     string
+	resolved uint32
+}
+
+// labelledStats contains request metrics with the bucket or user label.
+type labelledStats struct {
+	label string
+
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	received *prometheus.CounterVec
+	sent     *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	mu      sync.RWMutex
+	cfg     LabelConfig
+	allowed map[string]struct{}
+	seen    map[string]struct{}
+}
+
+var (
+	bucketStats = newLabelledStats("bucket")
+	userStats   = newLabelledStats("user")
+)
+
+func init() {
+	bucketStats.register()
+	userStats.register()
+}
+
+func newLabelledStats(label string) *labelledStats {
+	return &labelledStats{
+		label: label,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Subsystem: label,
+			Name:      "requests_total",
+			Help:      "Total number of s3 requests by " + label + " in current NeoFS S3 Gate instance",
+		}, []string{label, "api"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Subsystem: label,
+			Name:      "errors_total",
+			Help:      "Total number of s3 errors by " + label + " and S3 error code in current NeoFS S3 Gate instance",
+		}, []string{label, "api", "code"}),
+		received: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Subsystem: label,
+			Name:      "received_bytes_total",
+			Help:      "Total number of request bytes received by " + label + " by current NeoFS S3 Gate instance",
+		}, []string{label}),
+		sent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Subsystem: label,
+			Name:      "sent_bytes_total",
+			Help:      "Total number of response bytes sent by " + label + " by current NeoFS S3 Gate instance",
+		}, []string{label}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "neofs_s3",
+			Subsystem: label,
+			Name:      "request_seconds",
+			Help:      "Time taken by requests by " + label + " served by current NeoFS S3 Gate instance",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{label, "api"}),
+		seen: make(map[string]struct{}),
+	}
+}
+
+func (s *labelledStats) register() {
+	prometheus.MustRegister(s.requests, s.errors, s.received, s.sent, s.duration)
+}
+
+// setConfig applies the changed config and drops the reported metrics since
+// label values may be reported differently with the new config.
+func (s *labelledStats) setConfig(cfg LabelConfig) {
+	allowed := make(map[string]struct{}, len(cfg.Allowed))
+	for _, v := range cfg.Allowed {
+		allowed[v] = struct{}{}
+	}
+
+	s.mu.Lock()
+	if s.cfg.Enabled == cfg.Enabled && s.cfg.MaxValues == cfg.MaxValues && reflect.DeepEqual(s.allowed, allowed) {
+		s.mu.Unlock()
+		return
+	}
+	s.cfg = cfg
+	s.allowed = allowed
+	s.seen = make(map[string]struct{})
+	s.mu.Unlock()
+
+	s.requests.Reset()
+	s.errors.Reset()
+	s.received.Reset()
+	s.sent.Reset()
+	s.duration.Reset()
+}
+
+// labelValue returns the value reported for the bucket or the user.
+// The second result is false if the metrics are disabled.
+func (s *labelledStats) labelValue(v string) (string, bool) {
+	s.mu.RLock()
+	if !s.cfg.Enabled {
+		s.mu.RUnlock()
+		return "", false
+	}
+
+	if len(s.allowed) != 0 {
+		_, ok := s.allowed[v]
+		s.mu.RUnlock()
+		if !ok {
+			return OtherLabelValue, true
+		}
+		return v, true
+	}
+
+	_, ok := s.seen[v]
+	s.mu.RUnlock()
+	if ok {
+		return v, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok = s.seen[v]; !ok {
+		if s.cfg.MaxValues > 0 && len(s.seen) >= s.cfg.MaxValues {
+			return OtherLabelValue, true
+		}
+		s.seen[v] = struct{}{}
+	}
+
+	return v, true
+}
+
+func (s *labelledStats) update(value, api string, code int, errCode string, in, out uint64, durationSecs float64) {
+	if value == "" {
+		return
+	}
+
+	value, ok := s.labelValue(value)
+	if !ok {
+		return
+	}
+
+	s.requests.WithLabelValues(value, api).Inc()
+	if code >= http.StatusBadRequest {
+		if errCode == "" {
+			errCode = strconv.Itoa(code)
+		}
+		s.errors.WithLabelValues(value, api, errCode).Inc()
+	}
+	s.received.WithLabelValues(value).Add(float64(in))
+	s.sent.WithLabelValues(value).Add(float64(out))
+	s.duration.WithLabelValues(value, api).Observe(durationSecs)
+}
+
+// SetBucketLabelsConfig configures request metrics labelled by the bucket.
+func SetBucketLabelsConfig(cfg LabelConfig) {
+	bucketStats.setConfig(cfg)
+}
+
+// SetUserLabelsConfig configures request metrics labelled by the user.
+func SetUserLabelsConfig(cfg LabelConfig) {
+	userStats.setConfig(cfg)
+}
+
+// ContextWithUser returns the context with the user the request metrics are reported for.
+func ContextWithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// BucketResolved marks the bucket of the request as existing, so the request metrics are labelled by it.
+// Requests to the buckets which haven't been resolved are reported without the bucket label.
+func BucketResolved(ctx context.Context, bucket string) {
+	if label, ok := ctx.Value(bucketContextKey{}).(*bucketLabel); ok && label.name == bucket {
+		atomic.StoreUint32(&label.resolved, 1)
+	}
+}
+
+// withBucketLabel adds the bucket of the request to the request context to be marked as resolved.
+func withBucketLabel(r *http.Request) (*http.Request, *bucketLabel) {
+	label := &bucketLabel{name: mux.Vars(r)["bucket"]}
+	if label.name == "" {
+		return r, label
+	}
+	return r.WithContext(context.WithValue(r.Context(), bucketContextKey{}, label)), label
+}
+
+func (l *bucketLabel) value() string {
+	if atomic.LoadUint32(&l.resolved) == 0 {
+		return ""
+	}
+	return l.name
+}
+
+func userFromContext(ctx context.Context) string {
+	user, _ := ctx.Value(userContextKey{}).(string)
+	return user
+}
+
+// SetErrorCode remembers the S3 error code of the response written by the handler wrapped with APIStats.
+func SetErrorCode(w http.ResponseWriter, code string) {
+	if res, ok := w.(*responseWrapper); ok {
+		res.errorCode = code
+	}
+}
+
+func updateLabelledStats(api string, w *responseWrapper, r *http.Request, bucket *bucketLabel, in, out uint64, durationSecs float64) {
+	bucketStats.update(bucket.value(), api, w.statusCode, w.errorCode, in, out, durationSecs)
+	userStats.update(userFromContext(r.Context()), api, w.statusCode, w.errorCode, in, out, durationSecs)
+}
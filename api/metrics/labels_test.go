@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLabelledStats(t *testing.T) {
+	SetBucketLabelsConfig(LabelConfig{Enabled: true, MaxValues: 2})
+	SetUserLabelsConfig(LabelConfig{Enabled: true, Allowed: []string{"user1"}})
+	t.Cleanup(func() {
+		SetBucketLabelsConfig(LabelConfig{})
+		SetUserLabelsConfig(LabelConfig{})
+	})
+
+	handler := APIStats("putobject", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		if r.Header.Get("X-Missing") != "" {
+			SetErrorCode(w, "NoSuchBucket")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		BucketResolved(r.Context(), mux.Vars(r)["bucket"])
+		if r.Header.Get("X-Fail") != "" {
+			SetErrorCode(w, "NoSuchKey")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("response"))
+	})
+
+	serve := func(bucket, user string, missing, fail bool) {
+		r := httptest.NewRequest(http.MethodPut, "/"+bucket+"/obj", strings.NewReader("content"))
+		r = mux.SetURLVars(r, map[string]string{"bucket": bucket})
+		r = r.WithContext(ContextWithUser(r.Context(), user))
+		if missing {
+			r.Header.Set("X-Missing", "true")
+		}
+		if fail {
+			r.Header.Set("X-Fail", "true")
+		}
+		handler(httptest.NewRecorder(), r)
+	}
+
+	serve("bucket1", "user1", false, false)
+	serve("bucket2", "user2", false, false)
+	// nonexistent buckets don't take label values
+	serve("missing1", "user1", true, false)
+	serve("missing2", "user1", true, false)
+	serve("bucket3", "user1", false, true)
+	serve("bucket1", "user1", false, true)
+
+	require.Equal(t, 2.0, testutil.ToFloat64(bucketStats.requests.WithLabelValues("bucket1", "putobject")))
+	require.Equal(t, 1.0, testutil.ToFloat64(bucketStats.requests.WithLabelValues("bucket2", "putobject")))
+	// the limit of the distinct buckets is reached
+	require.Equal(t, 1.0, testutil.ToFloat64(bucketStats.requests.WithLabelValues(OtherLabelValue, "putobject")))
+	require.Equal(t, 3, testutil.CollectAndCount(bucketStats.requests))
+	require.Equal(t, 1.0, testutil.ToFloat64(bucketStats.errors.WithLabelValues("bucket1", "putobject", "NoSuchKey")))
+	require.Equal(t, float64(2*len("content")), testutil.ToFloat64(bucketStats.received.WithLabelValues("bucket1")))
+	require.Equal(t, float64(len("response")), testutil.ToFloat64(bucketStats.sent.WithLabelValues("bucket1")))
+
+	// user2 isn't in the allow-list
+	require.Equal(t, 5.0, testutil.ToFloat64(userStats.requests.WithLabelValues("user1", "putobject")))
+	require.Equal(t, 1.0, testutil.ToFloat64(userStats.requests.WithLabelValues(OtherLabelValue, "putobject")))
+	require.Equal(t, 2.0, testutil.ToFloat64(userStats.errors.WithLabelValues("user1", "putobject", "NoSuchBucket")))
+	require.Equal(t, 2.0, testutil.ToFloat64(userStats.errors.WithLabelValues("user1", "putobject", "NoSuchKey")))
+
+	// unchanged config keeps metrics, disabled metrics are dropped
+	SetUserLabelsConfig(LabelConfig{Enabled: true, Allowed: []string{"user1"}})
+	require.Equal(t, 2, testutil.CollectAndCount(userStats.requests))
+
+	SetBucketLabelsConfig(LabelConfig{})
+	serve("bucket1", "user1", false, false)
+	require.Zero(t, testutil.CollectAndCount(bucketStats.requests))
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	rateLimitRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Subsystem: "rate_limit",
+			Name:      "rejected_total",
+			Help:      "Total number of requests rejected with SlowDown by the scope of the exceeded request rate limit",
+		},
+		[]string{"scope"},
+	)
+
+	rateLimitThrottled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "neofs_s3",
+			Subsystem: "rate_limit",
+			Name:      "throttled_seconds_total",
+			Help:      "Total time requests waited for the bandwidth limit by the scope of the limit",
+		},
+		[]string{"scope"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(rateLimitRejected, rateLimitThrottled)
+}
+
+// ObserveRateLimitRejected counts the request rejected because of the request rate limit of the scope.
+func ObserveRateLimitRejected(scope string) {
+	rateLimitRejected.WithLabelValues(scope).Inc()
+}
+
+// ObserveRateLimitThrottle records the time the request waited for the bandwidth limit of the scope.
+func ObserveRateLimitThrottle(scope string, wait time.Duration) {
+	rateLimitThrottled.WithLabelValues(scope).Add(wait.Seconds())
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var bucketUsage = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "neofs_s3",
+		Subsystem: "bucket",
+		Name:      "usage",
+		Help:      "Usage of buckets with usage tracking enabled as known by current NeoFS S3 Gate instance",
+	},
+	[]string{"bucket", "type"},
+)
+
+// Types of the bucket usage values.
+const (
+	usageSize           = "size_bytes"
+	usageObjects        = "objects"
+	usageCurrentObjects = "current_objects"
+	usageVersions       = "versions"
+	usageDeleteMarkers  = "delete_markers"
+	usageMultipartSize  = "multipart_bytes"
+)
+
+func init() {
+	prometheus.MustRegister(bucketUsage)
+}
+
+// SetBucketUsage updates usage gauges of the bucket.
+func SetBucketUsage(bucket string, usage *data.BucketUsage) {
+	bucketUsage.WithLabelValues(bucket, usageSize).Set(float64(usage.Size))
+	bucketUsage.WithLabelValues(bucket, usageObjects).Set(float64(usage.Objects))
+	bucketUsage.WithLabelValues(bucket, usageCurrentObjects).Set(float64(usage.CurrentObjects))
+	bucketUsage.WithLabelValues(bucket, usageVersions).Set(float64(usage.Versions()))
+	bucketUsage.WithLabelValues(bucket, usageDeleteMarkers).Set(float64(usage.DeleteMarkers))
+	bucketUsage.WithLabelValues(bucket, usageMultipartSize).Set(float64(usage.MultipartSize))
+}
+
+// DeleteBucketUsage removes usage gauges of the bucket.
+func DeleteBucketUsage(bucket string) {
+	bucketUsage.DeletePartialMatch(prometheus.Labels{"bucket": bucket})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -101,7 +102,8 @@ type (
 	}
 )
 
-func NewController(p *Options, l *zap.Logger) (*Controller, error) {
+// connect establishes connection to NATS server with the options.
+func connect(p *Options) (*nats.Conn, error) {
 	ncopts := []nats.Option{
 		nats.Timeout(p.Timeout),
 	}
@@ -118,6 +120,24 @@ func NewController(p *Options, l *zap.Logger) (*Controller, error) {
 		return nil, fmt.Errorf("connect to nats: %w", err)
 	}
 
+	return nc, nil
+}
+
+// connectionStatus returns an error if the connection isn't established, e.g. it's reconnecting.
+func connectionStatus(nc *nats.Conn) error {
+	if status := nc.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("nats connection is %s", strings.ToLower(status.String()))
+	}
+
+	return nil
+}
+
+func NewController(p *Options, l *zap.Logger) (*Controller, error) {
+	nc, err := connect(p)
+	if err != nil {
+		return nil, err
+	}
+
 	js, err := nc.JetStream()
 	if err != nil {
 		return nil, fmt.Errorf("get jet stream: %w", err)
@@ -131,6 +151,47 @@ func NewController(p *Options, l *zap.Logger) (*Controller, error) {
 	}, nil
 }
 
+// Reconnect connects to NATS server with new options and replaces the current connection.
+// Subscriptions are restored on the new connection, the previous connection is drained.
+func (c *Controller) Reconnect(p *Options) error {
+	nc, err := connect(p)
+	if err != nil {
+		return err
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		nc.Close()
+		return fmt.Errorf("get jet stream: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for topic, stream := range c.handlers {
+		if _, err = js.ChanSubscribe(topic, stream.ch); err != nil {
+			nc.Close()
+			return fmt.Errorf("could not subscribe to '%s': %w", topic, err)
+		}
+	}
+
+	prev := c.taskQueueConnection
+	c.taskQueueConnection, c.jsClient = nc, js
+
+	if err = prev.Drain(); err != nil {
+		c.logger.Warn("couldn't drain previous nats connection", zap.Error(err))
+	}
+
+	return nil
+}
+
+// Healthcheck checks that the connection to NATS server is established.
+func (c *Controller) Healthcheck(context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return connectionStatus(c.taskQueueConnection)
+}
+
 func (c *Controller) Subscribe(ctx context.Context, topic string, handler layer.MsgHandler) error {
 	ch := make(chan *nats.Msg, 1)
 
@@ -253,7 +314,11 @@ func prepareEvent(p *handler.SendNotificationParams) *Event {
 }
 
 func (c *Controller) publish(topic string, msg []byte) error {
-	if _, err := c.jsClient.Publish(topic, msg); err != nil {
+	c.mu.RLock()
+	js := c.jsClient
+	c.mu.RUnlock()
+
+	if _, err := js.Publish(topic, msg); err != nil {
 		return fmt.Errorf("couldn't send  event: %w", err)
 	}
 
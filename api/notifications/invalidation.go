@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
+	"go.uber.org/zap"
+)
+
+// DefaultInvalidationSubject is a default NATS subject of cache invalidation events.
+const DefaultInvalidationSubject = "s3-gw.cache.invalidation"
+
+// InvalidationBus is a cache.InvalidationBus which delivers events between gateway
+// instances via NATS core publish-subscribe. Events aren't persisted, instances which
+// are offline miss them and rely on the cache entries expiration.
+type InvalidationBus struct {
+	logger  *zap.Logger
+	subject string
+
+	mu       sync.RWMutex
+	conn     *nats.Conn
+	handlers []cache.InvalidationHandler
+}
+
+// NewInvalidationBus connects to NATS server with the options used for notifications.
+func NewInvalidationBus(p *Options, subject string, l *zap.Logger) (*InvalidationBus, error) {
+	nc, err := connect(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InvalidationBus{
+		logger:  l,
+		conn:    nc,
+		subject: subject,
+	}, nil
+}
+
+// Publish sends the event to all gateway instances subscribed to the subject.
+func (b *InvalidationBus) Publish(e cache.InvalidationEvent) error {
+	msg, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal invalidation event: %w", err)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.conn.Publish(b.subject, msg)
+}
+
+// Subscribe calls the handler for every event received from the subject.
+func (b *InvalidationBus) Subscribe(h cache.InvalidationHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.subscribe(b.conn, h); err != nil {
+		return err
+	}
+	b.handlers = append(b.handlers, h)
+
+	return nil
+}
+
+func (b *InvalidationBus) subscribe(nc *nats.Conn, h cache.InvalidationHandler) error {
+	_, err := nc.Subscribe(b.subject, func(msg *nats.Msg) {
+		var e cache.InvalidationEvent
+		if err := json.Unmarshal(msg.Data, &e); err != nil {
+			b.logger.Warn("couldn't unmarshal cache invalidation event", zap.Error(err))
+			return
+		}
+		h(e)
+	})
+	if err != nil {
+		return fmt.Errorf("subscribe to '%s': %w", b.subject, err)
+	}
+
+	return nil
+}
+
+// Reconnect connects to NATS server with new options and replaces the current connection.
+// Handlers are subscribed on the new connection, the previous connection is drained.
+func (b *InvalidationBus) Reconnect(p *Options) error {
+	nc, err := connect(p)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, h := range b.handlers {
+		if err = b.subscribe(nc, h); err != nil {
+			nc.Close()
+			return err
+		}
+	}
+
+	prev := b.conn
+	b.conn = nc
+
+	if err = prev.Drain(); err != nil {
+		b.logger.Warn("couldn't drain previous nats connection", zap.Error(err))
+	}
+
+	return nil
+}
+
+// Healthcheck checks that the connection to NATS server is established.
+func (b *InvalidationBus) Healthcheck(context.Context) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return connectionStatus(b.conn)
+}
+
+// Close drains subscriptions and closes the connection.
+func (b *InvalidationBus) Close() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.conn.Drain()
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	apiErrors "github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/ratelimit"
+)
+
+type (
+	throttledResponseWriter struct {
+		http.ResponseWriter
+		ctx      context.Context
+		throttle *ratelimit.Throttle
+	}
+
+	throttledBody struct {
+		io.ReadCloser
+		ctx      context.Context
+		throttle *ratelimit.Throttle
+	}
+)
+
+// listAPIs contains names of the routes which are limited as ratelimit.ClassList.
+var listAPIs = map[string]struct{}{
+	"ListBuckets":          {},
+	"ListObjectsV1":        {},
+	"ListObjectsV2":        {},
+	"ListObjectsV2M":       {},
+	"ListBucketVersions":   {},
+	"ListMultipartUploads": {},
+	"ListObjectParts":      {},
+}
+
+// apiClass returns the class of the request for rate limiting.
+func apiClass(method, name string) string {
+	if _, ok := listAPIs[name]; ok {
+		return ratelimit.ClassList
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ratelimit.ClassRead
+	default:
+		return ratelimit.ClassWrite
+	}
+}
+
+// ctxThrottle is a context key of the Throttle created by rateLimit.
+const ctxThrottle = contextKeyType("Throttle")
+
+// rateLimit rejects requests exceeding the global, class and bucket request rate limits with SlowDown
+// error and throttles request and response bodies to fit the bandwidth limits. It's used before
+// the authentication, so that the gateway doesn't spend resources on authentication of the excess requests.
+func rateLimit(l *ratelimit.Limiter) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqInfo := GetReqInfo(r.Context())
+			throttle, err := l.AllowRequest(ratelimit.Request{
+				Bucket: reqInfo.BucketName,
+				Class:  apiClass(r.Method, reqInfo.API),
+			})
+			if err != nil {
+				writeSlowDown(w, reqInfo, err)
+				return
+			}
+
+			if throttle != nil {
+				r = r.WithContext(context.WithValue(r.Context(), ctxThrottle, throttle))
+				r.Body = &throttledBody{ReadCloser: r.Body, ctx: r.Context(), throttle: throttle}
+				w = &throttledResponseWriter{ResponseWriter: w, ctx: r.Context(), throttle: throttle}
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitAccessKey is like rateLimit but applies the limits of the access key ID. It must be used
+// after the authentication, so that only verified access key IDs are limited.
+func rateLimitAccessKey(l *ratelimit.Limiter) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqInfo := GetReqInfo(r.Context())
+			prev, _ := r.Context().Value(ctxThrottle).(*ratelimit.Throttle)
+			throttle, err := l.AllowAccessKey(prev, reqInfo.AccessKeyID)
+			if err != nil {
+				writeSlowDown(w, reqInfo, err)
+				return
+			}
+
+			// bodies are already throttled by rateLimit, the same throttle is extended
+			if prev == nil && throttle != nil {
+				r.Body = &throttledBody{ReadCloser: r.Body, ctx: r.Context(), throttle: throttle}
+				w = &throttledResponseWriter{ResponseWriter: w, ctx: r.Context(), throttle: throttle}
+			}
+
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeSlowDown writes SlowDown error with Retry-After header for the exceeded rate limit.
+func writeSlowDown(w http.ResponseWriter, reqInfo *ReqInfo, err error) {
+	var exceeded *ratelimit.ExceededError
+	if errors.As(err, &exceeded) {
+		retryAfter := int(math.Ceil(exceeded.RetryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set(hdrRetryAfter, strconv.Itoa(retryAfter))
+	}
+	WriteErrorResponse(w, reqInfo, apiErrors.GetAPIError(apiErrors.ErrSlowDown))
+}
+
+func (w *throttledResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	if werr := w.throttle.Wait(w.ctx, n); err == nil {
+		err = werr
+	}
+	return n, err
+}
+
+// Flush calls the underlying Flush.
+func (w *throttledResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if werr := b.throttle.Wait(b.ctx, n); err == nil {
+		err = werr
+	}
+	return n, err
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket is refilled with rate tokens per second up to burst tokens.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate, burst float64, now time.Time) *tokenBucket {
+	if burst < rate {
+		burst = rate
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{rate: rate, burst: burst, tokens: burst, last: now}
+}
+
+func (b *tokenBucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// take takes n tokens if they are available, otherwise it returns the time after which they are.
+func (b *tokenBucket) take(now time.Time, n float64) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(now)
+	if b.tokens >= n {
+		b.tokens -= n
+		return 0, true
+	}
+
+	return b.wait(n - b.tokens), false
+}
+
+// refund returns tokens taken by take.
+func (b *tokenBucket) refund(n float64) {
+	b.mu.Lock()
+	b.tokens = math.Min(b.burst, b.tokens+n)
+	b.mu.Unlock()
+}
+
+// borrow takes n tokens even if they aren't available and returns the time after which
+// the debt is repaid.
+func (b *tokenBucket) borrow(now time.Time, n float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(now)
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return b.wait(-b.tokens)
+}
+
+// full checks if the bucket is refilled, so it can be dropped and recreated later without changing the limit.
+func (b *tokenBucket) full(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(now)
+	return b.tokens >= b.burst
+}
+
+func (b *tokenBucket) wait(missing float64) time.Duration {
+	return time.Duration(missing / b.rate * float64(time.Second))
+}
@@ -0,0 +1,312 @@
+// Package ratelimit limits the rate of S3 requests and the bandwidth with token buckets
+// set globally, per API class, per access key ID and per bucket.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
+)
+
+// API classes of the requests.
+const (
+	ClassRead  = "read"
+	ClassWrite = "write"
+	ClassList  = "list"
+)
+
+// Scopes of the limits reported in ExceededError and metrics.
+const (
+	ScopeGlobal    = "global"
+	ScopeClass     = "class"
+	ScopeAccessKey = "access_key"
+	ScopeBucket    = "bucket"
+)
+
+// sweepInterval is an interval the refilled limiters of access keys and buckets are dropped with.
+const sweepInterval = time.Minute
+
+// Limit contains the rate of requests and the bandwidth. Zero values mean no limit.
+type Limit struct {
+	// Requests is the number of requests per second.
+	Requests float64
+	// RequestsBurst is the number of requests allowed at once, it's at least Requests.
+	RequestsBurst int
+	// Bandwidth is the number of bytes per second received and sent.
+	Bandwidth int64
+	// BandwidthBurst is the number of bytes transferred without throttling, it's at least Bandwidth.
+	BandwidthBurst int64
+}
+
+// Config contains the limits of all scopes. The request must fit all the limits it falls under.
+type Config struct {
+	// Global limit is shared by all requests.
+	Global Limit
+	// Classes contain limits shared by all requests of ClassRead, ClassWrite and ClassList.
+	Classes map[string]Limit
+	// AccessKey is a limit of every access key ID unless it's set in AccessKeys.
+	// Anonymous requests share one limit.
+	AccessKey Limit
+	// AccessKeys contain limits of the specific access key IDs.
+	AccessKeys map[string]Limit
+	// Bucket is a limit of every bucket unless it's set in Buckets.
+	Bucket Limit
+	// Buckets contain limits of the specific buckets.
+	Buckets map[string]Limit
+}
+
+// Request describes the request to be limited.
+type Request struct {
+	AccessKeyID string
+	Bucket      string
+	Class       string
+}
+
+// ExceededError is returned when the request rate limit is exceeded.
+type ExceededError struct {
+	Scope      string
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s request rate limit exceeded, retry after %s", e.Scope, e.RetryAfter)
+}
+
+type limiter struct {
+	requests  *tokenBucket
+	bandwidth *tokenBucket
+}
+
+func newLimiter(l Limit, now time.Time) *limiter {
+	var res limiter
+	if l.Requests > 0 {
+		res.requests = newTokenBucket(l.Requests, float64(l.RequestsBurst), now)
+	}
+	if l.Bandwidth > 0 {
+		res.bandwidth = newTokenBucket(float64(l.Bandwidth), float64(l.BandwidthBurst), now)
+	}
+	if res.requests == nil && res.bandwidth == nil {
+		return nil
+	}
+
+	return &res
+}
+
+func (l *limiter) full(now time.Time) bool {
+	return (l.requests == nil || l.requests.full(now)) && (l.bandwidth == nil || l.bandwidth.full(now))
+}
+
+// keyedLimiters creates limiters of access keys or buckets on demand.
+type keyedLimiters struct {
+	def       Limit
+	overrides map[string]Limit
+
+	mu        sync.Mutex
+	limiters  map[string]*limiter
+	lastSweep time.Time
+}
+
+func (k *keyedLimiters) get(key string, now time.Time) *limiter {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if now.Sub(k.lastSweep) > sweepInterval {
+		for name, l := range k.limiters {
+			if l.full(now) {
+				delete(k.limiters, name)
+			}
+		}
+		k.lastSweep = now
+	}
+
+	if l, ok := k.limiters[key]; ok {
+		return l
+	}
+
+	limit, ok := k.overrides[key]
+	if !ok {
+		limit = k.def
+	}
+
+	l := newLimiter(limit, now)
+	if l != nil {
+		k.limiters[key] = l
+	}
+
+	return l
+}
+
+type state struct {
+	global     *limiter
+	classes    map[string]*limiter
+	accessKeys *keyedLimiters
+	buckets    *keyedLimiters
+}
+
+// Limiter checks the requests against the limits. It can be reconfigured at runtime with Update.
+type Limiter struct {
+	mu  sync.RWMutex
+	st  *state
+	now func() time.Time
+}
+
+// New creates Limiter with the given config.
+func New(cfg Config) *Limiter {
+	l := &Limiter{now: time.Now}
+	l.Update(cfg)
+	return l
+}
+
+// Update replaces the limits. Usage of the previous limits is discarded.
+func (l *Limiter) Update(cfg Config) {
+	now := l.now()
+	st := &state{
+		global:     newLimiter(cfg.Global, now),
+		classes:    make(map[string]*limiter, len(cfg.Classes)),
+		accessKeys: &keyedLimiters{def: cfg.AccessKey, overrides: cfg.AccessKeys, limiters: make(map[string]*limiter), lastSweep: now},
+		buckets:    &keyedLimiters{def: cfg.Bucket, overrides: cfg.Buckets, limiters: make(map[string]*limiter), lastSweep: now},
+	}
+	for class, limit := range cfg.Classes {
+		if cl := newLimiter(limit, now); cl != nil {
+			st.classes[class] = cl
+		}
+	}
+
+	l.mu.Lock()
+	l.st = st
+	l.mu.Unlock()
+}
+
+type scopedLimiter struct {
+	scope string
+	*limiter
+}
+
+// Allow takes a request token from every limit the request falls under. If one of them is exhausted,
+// no tokens are taken and ExceededError is returned. Returned Throttle limits the bandwidth of the request,
+// it's nil if the bandwidth isn't limited.
+func (l *Limiter) Allow(req Request) (*Throttle, error) {
+	st, now := l.state()
+	limiters := append(st.requestLimiters(req, now), st.accessKeyLimiters(req.AccessKeyID, now)...)
+	return l.take(limiters, now, nil)
+}
+
+// AllowRequest is like Allow but checks only the global, class and bucket limits which don't depend
+// on the access key ID of the request, so that requests exceeding them are rejected before the
+// authentication. The access key limit is checked with AllowAccessKey after the authentication.
+func (l *Limiter) AllowRequest(req Request) (*Throttle, error) {
+	st, now := l.state()
+	return l.take(st.requestLimiters(req, now), now, nil)
+}
+
+// AllowAccessKey takes a request token from the limit of the authenticated access key ID and adds its
+// bandwidth limit to the Throttle t returned by AllowRequest. Tokens taken by AllowRequest aren't
+// returned if the access key limit is exceeded. The resulting Throttle is nil if the bandwidth isn't limited.
+func (l *Limiter) AllowAccessKey(t *Throttle, accessKeyID string) (*Throttle, error) {
+	st, now := l.state()
+	return l.take(st.accessKeyLimiters(accessKeyID, now), now, t)
+}
+
+func (l *Limiter) state() (*state, time.Time) {
+	l.mu.RLock()
+	st := l.st
+	l.mu.RUnlock()
+
+	return st, l.now()
+}
+
+func (st *state) requestLimiters(req Request, now time.Time) []scopedLimiter {
+	limiters := make([]scopedLimiter, 0, 4)
+	if st.global != nil {
+		limiters = append(limiters, scopedLimiter{ScopeGlobal, st.global})
+	}
+	if cl := st.classes[req.Class]; cl != nil {
+		limiters = append(limiters, scopedLimiter{ScopeClass, cl})
+	}
+	if req.Bucket != "" {
+		if bl := st.buckets.get(req.Bucket, now); bl != nil {
+			limiters = append(limiters, scopedLimiter{ScopeBucket, bl})
+		}
+	}
+
+	return limiters
+}
+
+func (st *state) accessKeyLimiters(accessKeyID string, now time.Time) []scopedLimiter {
+	if kl := st.accessKeys.get(accessKeyID, now); kl != nil {
+		return []scopedLimiter{{ScopeAccessKey, kl}}
+	}
+
+	return nil
+}
+
+// take takes a request token from every limiter or none of them and appends bandwidth limiters to t.
+func (l *Limiter) take(limiters []scopedLimiter, now time.Time, t *Throttle) (*Throttle, error) {
+	for i, sl := range limiters {
+		if sl.requests == nil {
+			continue
+		}
+		if wait, ok := sl.requests.take(now, 1); !ok {
+			for _, prev := range limiters[:i] {
+				if prev.requests != nil {
+					prev.requests.refund(1)
+				}
+			}
+			metrics.ObserveRateLimitRejected(sl.scope)
+			return nil, &ExceededError{Scope: sl.scope, RetryAfter: wait}
+		}
+	}
+
+	for _, sl := range limiters {
+		if sl.bandwidth != nil {
+			if t == nil {
+				t = &Throttle{now: l.now}
+			}
+			t.limiters = append(t.limiters, sl)
+		}
+	}
+
+	return t, nil
+}
+
+// Throttle limits the bandwidth of the request.
+type Throttle struct {
+	limiters []scopedLimiter
+	now      func() time.Time
+}
+
+// Wait accounts n transferred bytes and waits until they fit all bandwidth limits of the request.
+func (t *Throttle) Wait(ctx context.Context, n int) error {
+	if n <= 0 {
+		return nil
+	}
+
+	var (
+		now   = t.now()
+		wait  time.Duration
+		scope string
+	)
+	for _, sl := range t.limiters {
+		if w := sl.bandwidth.borrow(now, float64(n)); w > wait {
+			wait, scope = w, sl.scope
+		}
+	}
+	if wait <= 0 {
+		return nil
+	}
+
+	metrics.ObserveRateLimitThrottle(scope, wait)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
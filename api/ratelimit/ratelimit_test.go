@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func newTestLimiter(cfg Config) (*Limiter, *testClock) {
+	clock := &testClock{now: time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)}
+	l := &Limiter{now: clock.Now}
+	l.Update(cfg)
+	return l, clock
+}
+
+func requireExceeded(t *testing.T, err error, scope string) *ExceededError {
+	var exceeded *ExceededError
+	require.True(t, errors.As(err, &exceeded), err)
+	require.Equal(t, scope, exceeded.Scope)
+	return exceeded
+}
+
+func TestLimiterRequests(t *testing.T) {
+	l, clock := newTestLimiter(Config{
+		Classes:    map[string]Limit{ClassList: {Requests: 1}},
+		AccessKey:  Limit{Requests: 2},
+		AccessKeys: map[string]Limit{"backup": {Requests: 10, RequestsBurst: 20}},
+		Buckets:    map[string]Limit{"small": {Requests: 1}},
+	})
+
+	user := Request{AccessKeyID: "user", Bucket: "bucket", Class: ClassRead}
+	for i := 0; i < 2; i++ {
+		_, err := l.Allow(user)
+		require.NoError(t, err)
+	}
+	exceeded := requireExceeded(t, func() error { _, err := l.Allow(user); return err }(), ScopeAccessKey)
+	require.Equal(t, 500*time.Millisecond, exceeded.RetryAfter)
+
+	// other keys have their own limits
+	for i := 0; i < 20; i++ {
+		_, err := l.Allow(Request{AccessKeyID: "backup", Bucket: "bucket", Class: ClassRead})
+		require.NoError(t, err)
+	}
+
+	clock.now = clock.now.Add(time.Second)
+	_, err := l.Allow(user)
+	require.NoError(t, err)
+
+	// list class token isn't taken when the bucket limit is exceeded
+	_, err = l.Allow(Request{AccessKeyID: "backup", Bucket: "small", Class: ClassRead})
+	require.NoError(t, err)
+	_, err = l.Allow(Request{AccessKeyID: "backup", Bucket: "small", Class: ClassList})
+	requireExceeded(t, err, ScopeBucket)
+	_, err = l.Allow(Request{AccessKeyID: "backup", Bucket: "bucket", Class: ClassList})
+	require.NoError(t, err)
+	_, err = l.Allow(Request{AccessKeyID: "backup", Bucket: "bucket", Class: ClassList})
+	requireExceeded(t, err, ScopeClass)
+
+	l.Update(Config{})
+	for i := 0; i < 10; i++ {
+		throttle, err := l.Allow(user)
+		require.NoError(t, err)
+		require.Nil(t, throttle)
+	}
+}
+
+func TestLimiterAccessKeyAfterRequest(t *testing.T) {
+	l, _ := newTestLimiter(Config{
+		Global:    Limit{Requests: 3, Bandwidth: 1000},
+		AccessKey: Limit{Requests: 1, Bandwidth: 100},
+	})
+
+	// access key limit isn't checked before the authentication
+	throttle, err := l.AllowRequest(Request{AccessKeyID: "user"})
+	require.NoError(t, err)
+	require.Len(t, throttle.limiters, 1)
+	require.Empty(t, l.st.accessKeys.limiters)
+
+	throttle, err = l.AllowAccessKey(throttle, "user")
+	require.NoError(t, err)
+	require.Len(t, throttle.limiters, 2)
+
+	// global token is spent even if the access key limit is exceeded
+	throttle, err = l.AllowRequest(Request{})
+	require.NoError(t, err)
+	_, err = l.AllowAccessKey(throttle, "user")
+	requireExceeded(t, err, ScopeAccessKey)
+
+	_, err = l.AllowRequest(Request{})
+	require.NoError(t, err)
+	_, err = l.AllowRequest(Request{})
+	requireExceeded(t, err, ScopeGlobal)
+
+	l.Update(Config{AccessKey: Limit{Bandwidth: 100}})
+	throttle, err = l.AllowRequest(Request{})
+	require.NoError(t, err)
+	require.Nil(t, throttle)
+	throttle, err = l.AllowAccessKey(throttle, "user")
+	require.NoError(t, err)
+	require.Len(t, throttle.limiters, 1)
+}
+
+func TestLimiterSweep(t *testing.T) {
+	l, clock := newTestLimiter(Config{AccessKey: Limit{Requests: 1}})
+
+	_, err := l.Allow(Request{AccessKeyID: "user"})
+	require.NoError(t, err)
+	require.Len(t, l.st.accessKeys.limiters, 1)
+
+	clock.now = clock.now.Add(2 * sweepInterval)
+	_, err = l.Allow(Request{AccessKeyID: "other"})
+	require.NoError(t, err)
+	require.Len(t, l.st.accessKeys.limiters, 1)
+	require.Contains(t, l.st.accessKeys.limiters, "other")
+}
+
+func TestThrottle(t *testing.T) {
+	l, clock := newTestLimiter(Config{
+		Global:    Limit{Bandwidth: 1000},
+		AccessKey: Limit{Bandwidth: 100_000, BandwidthBurst: 1_000_000},
+	})
+
+	throttle, err := l.Allow(Request{AccessKeyID: "user"})
+	require.NoError(t, err)
+	require.NotNil(t, throttle)
+	require.Len(t, throttle.limiters, 2)
+
+	ctx := context.Background()
+	require.NoError(t, throttle.Wait(ctx, 1000))
+
+	// the global bucket is empty, the wait takes 10ms
+	start := time.Now()
+	require.NoError(t, throttle.Wait(ctx, 10))
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+
+	// debt is repaid with time
+	clock.now = clock.now.Add(time.Second)
+	require.NoError(t, throttle.Wait(ctx, 900))
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.ErrorIs(t, throttle.Wait(ctx, 1000), context.Canceled)
+}
@@ -30,6 +30,8 @@ type (
 		API          string   // API name -- GetObject PutObject NewMultipartUpload etc.
 		BucketName   string   // Bucket name
 		ObjectName   string   // Object name
+		AccessKeyID  string   // Access key id of the request credentials
+		ErrorCode    string   // S3 error code of the response
 		URL          *url.URL // Request url
 		tags         []KeyVal // Any additional info not accommodated by above fields
 	}
@@ -0,0 +1,101 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/invoker"
+	"github.com/nspcc-dev/neo-go/pkg/rpcclient/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neofs-contract/nns"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+)
+
+// nnsClient looks up container names using Neo Name Service like ns.NNS does, but keeps
+// the RPC client, so the same connection is checked by the readiness probe and closed
+// when the resolver is replaced.
+type nnsClient struct {
+	client interface {
+		invoker.RPCInvoke
+		Init() error
+		GetContractStateByID(int32) (*state.Contract, error)
+		GetBlockCount() (uint32, error)
+		Close()
+	}
+
+	invoker  *invoker.Invoker
+	contract util.Uint160
+}
+
+var errNNSNotFound = errors.New("not found")
+
+// dialNNS connects to the RPC node. WebSocket is used for 'ws' and 'wss' schemes, otherwise HTTP.
+func dialNNS(address string) (*nnsClient, error) {
+	var (
+		c   nnsClient
+		err error
+	)
+
+	uri, err := url.Parse(address)
+	if err == nil && (uri.Scheme == "ws" || uri.Scheme == "wss") {
+		c.client, err = rpcclient.NewWS(context.Background(), address, rpcclient.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("create Neo WebSocket client: %w", err)
+		}
+	} else {
+		c.client, err = rpcclient.New(context.Background(), address, rpcclient.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("create Neo HTTP client: %w", err)
+		}
+	}
+
+	if err = c.client.Init(); err != nil {
+		c.client.Close()
+		return nil, fmt.Errorf("initialize Neo client: %w", err)
+	}
+
+	nnsContract, err := c.client.GetContractStateByID(1)
+	if err != nil {
+		c.client.Close()
+		return nil, fmt.Errorf("get NNS contract state: %w", err)
+	}
+
+	c.invoker = invoker.New(c.client, nil)
+	c.contract = nnsContract.Hash
+
+	return &c, nil
+}
+
+// resolveContainerName returns the first TXT record of the container name which is a valid container ID.
+func (c *nnsClient) resolveContainerName(name string) (cid.ID, error) {
+	item, err := unwrap.Item(c.invoker.Call(c.contract, "resolve", name+".container", int64(nns.TXT)))
+	if err != nil {
+		return cid.ID{}, fmt.Errorf("contract invocation: %w", err)
+	}
+
+	if _, ok := item.(stackitem.Null); !ok {
+		arr, ok := item.Value().([]stackitem.Item)
+		if !ok {
+			return cid.ID{}, errors.New("invalid cast to stack item slice")
+		}
+
+		var id cid.ID
+		for i := range arr {
+			bs, err := arr[i].TryBytes()
+			if err != nil {
+				return cid.ID{}, fmt.Errorf("convert array item to byte slice: %w", err)
+			}
+
+			if err = id.DecodeString(string(bs)); err == nil {
+				return id, nil
+			}
+		}
+	}
+
+	return cid.ID{}, errNNSNotFound
+}
@@ -11,8 +11,9 @@ import (
 )
 
 const (
-	NNSResolver = "nns"
-	DNSResolver = "dns"
+	NNSResolver   = "nns"
+	DNSResolver   = "dns"
+	LocalResolver = "local"
 )
 
 // ErrNoResolvers returns when trying to resolve container without any resolver.
@@ -27,6 +28,14 @@ type NeoFS interface {
 	SystemDNS(context.Context) (string, error)
 }
 
+// LocalNeoFS represents in-process NeoFS which is able to resolve container names itself.
+type LocalNeoFS interface {
+	NeoFS
+
+	// ResolveContainerName returns ID of the container with the provided domain name.
+	ResolveContainerName(context.Context, string) (cid.ID, error)
+}
+
 type Config struct {
 	NeoFS      NeoFS
 	RPCAddress string
@@ -40,6 +49,8 @@ type BucketResolver struct {
 type Resolver struct {
 	Name    string
 	resolve func(context.Context, string) (cid.ID, error)
+	check   func(context.Context) error
+	close   func()
 }
 
 func (r *Resolver) SetResolveFunc(fn func(context.Context, string) (cid.ID, error)) {
@@ -50,6 +61,22 @@ func (r *Resolver) Resolve(ctx context.Context, name string) (cid.ID, error) {
 	return r.resolve(ctx, name)
 }
 
+// Healthcheck checks that the remote backend of the resolver is available.
+// Resolvers without their own backend are always available.
+func (r *Resolver) Healthcheck(ctx context.Context) error {
+	if r.check == nil {
+		return nil
+	}
+	return r.check(ctx)
+}
+
+// Close releases the connection of the resolver to its remote backend.
+func (r *Resolver) Close() {
+	if r.close != nil {
+		r.close()
+	}
+}
+
 func NewBucketResolver(resolverNames []string, cfg *Config) (*BucketResolver, error) {
 	resolvers, err := createResolvers(resolverNames, cfg)
 	if err != nil {
@@ -62,18 +89,25 @@ func NewBucketResolver(resolverNames []string, cfg *Config) (*BucketResolver, er
 }
 
 func createResolvers(resolverNames []string, cfg *Config) ([]*Resolver, error) {
-	resolvers := make([]*Resolver, len(resolverNames))
-	for i, name := range resolverNames {
+	resolvers := make([]*Resolver, 0, len(resolverNames))
+	for _, name := range resolverNames {
 		cnrResolver, err := newResolver(name, cfg)
 		if err != nil {
+			closeResolvers(resolvers)
 			return nil, err
 		}
-		resolvers[i] = cnrResolver
+		resolvers = append(resolvers, cnrResolver)
 	}
 
 	return resolvers, nil
 }
 
+func closeResolvers(resolvers []*Resolver) {
+	for _, r := range resolvers {
+		r.Close()
+	}
+}
+
 func (r *BucketResolver) Resolve(ctx context.Context, bktName string) (cnrID cid.ID, err error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -99,6 +133,42 @@ func (r *BucketResolver) Resolve(ctx context.Context, bktName string) (cnrID cid
 	return cnrID, ErrNoResolvers
 }
 
+// ResolveResult is a result of the bucket name resolving by one resolver.
+type ResolveResult struct {
+	Resolver string
+	CID      cid.ID
+	Err      error
+}
+
+// ResolveAll resolves the bucket name by every configured resolver in order. It's intended
+// to debug resolving, Resolve returns the first successful result.
+func (r *BucketResolver) ResolveAll(ctx context.Context, bktName string) []ResolveResult {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	res := make([]ResolveResult, len(r.resolvers))
+	for i, resolver := range r.resolvers {
+		res[i].Resolver = resolver.Name
+		res[i].CID, res[i].Err = resolver.Resolve(ctx, bktName)
+	}
+
+	return res
+}
+
+// Healthcheck checks backends of all configured resolvers.
+func (r *BucketResolver) Healthcheck(ctx context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, resolver := range r.resolvers {
+		if err := resolver.Healthcheck(ctx); err != nil {
+			return fmt.Errorf("%s: %w", resolver.Name, err)
+		}
+	}
+
+	return nil
+}
+
 func (r *BucketResolver) UpdateResolvers(resolverNames []string, cfg *Config) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -112,11 +182,22 @@ func (r *BucketResolver) UpdateResolvers(resolverNames []string, cfg *Config) er
 		return err
 	}
 
+	// replaced resolvers aren't used anymore since the lock is taken
+	closeResolvers(r.resolvers)
 	r.resolvers = resolvers
 
 	return nil
 }
 
+// Close releases connections of all configured resolvers.
+func (r *BucketResolver) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	closeResolvers(r.resolvers)
+	r.resolvers = nil
+}
+
 func (r *BucketResolver) equals(resolverNames []string) bool {
 	if len(r.resolvers) != len(resolverNames) {
 		return false
@@ -136,6 +217,8 @@ func newResolver(name string, cfg *Config) (*Resolver, error) {
 		return NewDNSResolver(cfg.NeoFS)
 	case NNSResolver:
 		return NewNNSResolver(cfg.RPCAddress)
+	case LocalResolver:
+		return NewLocalResolver(cfg.NeoFS)
 	default:
 		return nil, fmt.Errorf("unknown resolver: %s", name)
 	}
@@ -173,22 +256,50 @@ func NewNNSResolver(address string) (*Resolver, error) {
 		return nil, fmt.Errorf("rpc address must not be empty for NNS resolver")
 	}
 
-	var nns ns.NNS
-
-	if err := nns.Dial(address); err != nil {
+	nns, err := dialNNS(address)
+	if err != nil {
 		return nil, fmt.Errorf("dial %s: %w", address, err)
 	}
 
 	resolveFunc := func(_ context.Context, name string) (cid.ID, error) {
-		cnrID, err := nns.ResolveContainerName(name)
+		cnrID, err := nns.resolveContainerName(name)
 		if err != nil {
 			return cid.ID{}, fmt.Errorf("couldn't resolve container '%s': %w", name, err)
 		}
 		return cnrID, nil
 	}
 
+	checkFunc := func(context.Context) error {
+		if _, err := nns.client.GetBlockCount(); err != nil {
+			return fmt.Errorf("get block count from %s: %w", address, err)
+		}
+		return nil
+	}
+
 	return &Resolver{
 		Name:    NNSResolver,
 		resolve: resolveFunc,
+		check:   checkFunc,
+		close:   nns.client.Close,
+	}, nil
+}
+
+func NewLocalResolver(neoFS NeoFS) (*Resolver, error) {
+	local, ok := neoFS.(LocalNeoFS)
+	if !ok {
+		return nil, fmt.Errorf("local resolver requires local NeoFS")
+	}
+
+	resolveFunc := func(ctx context.Context, name string) (cid.ID, error) {
+		cnrID, err := local.ResolveContainerName(ctx, name)
+		if err != nil {
+			return cid.ID{}, fmt.Errorf("couldn't resolve container '%s': %w", name, err)
+		}
+		return cnrID, nil
+	}
+
+	return &Resolver{
+		Name:    LocalResolver,
+		resolve: resolveFunc,
 	}, nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
 )
 
@@ -118,9 +119,12 @@ func WriteErrorResponse(w http.ResponseWriter, reqInfo *ReqInfo, err error) int
 
 		switch e.Code {
 		case "SlowDown", "XNeoFSServerNotInitialized", "XNeoFSReadQuorum", "XNeoFSWriteQuorum":
-			// Set retry-after header to indicate user-agents to retry request after 120secs.
+			// Set retry-after header to indicate user-agents to retry request after 120secs
+			// unless the exact time is known (e.g. set by rate limiter).
 			// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Retry-After
-			w.Header().Set(hdrRetryAfter, "120")
+			if w.Header().Get(hdrRetryAfter) == "" {
+				w.Header().Set(hdrRetryAfter, "120")
+			}
 		case "AccessDenied":
 			// TODO process when the request is from browser and also if browser
 		}
@@ -128,6 +132,10 @@ func WriteErrorResponse(w http.ResponseWriter, reqInfo *ReqInfo, err error) int
 
 	// Generates error response.
 	errorResponse := getAPIErrorResponse(reqInfo, err)
+	metrics.SetErrorCode(w, errorResponse.Code)
+	if reqInfo != nil {
+		reqInfo.ErrorCode = errorResponse.Code
+	}
 	encodedErrorResponse := EncodeResponse(errorResponse)
 	WriteResponse(w, code, encodedErrorResponse, MimeXML)
 	return code
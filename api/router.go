@@ -2,13 +2,19 @@ package api
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"net/url"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
 	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
+	"github.com/nspcc-dev/neofs-s3-gw/api/ratelimit"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/accesslog"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/metadata"
 )
@@ -84,6 +90,12 @@ type (
 		ListMultipartUploadsHandler(http.ResponseWriter, *http.Request)
 	}
 
+	// AccessLogger writes access log records of the served requests.
+	AccessLogger interface {
+		Enabled() bool
+		Log(*accesslog.Record)
+	}
+
 	// mimeType represents various MIME types used in API responses.
 	mimeType string
 
@@ -93,6 +105,19 @@ type (
 
 		statusCode int
 	}
+
+	accessLogResponseWriter struct {
+		sync.Once
+		http.ResponseWriter
+
+		statusCode int
+		written    uint64
+	}
+
+	accessLogBody struct {
+		io.ReadCloser
+		read uint64
+	}
 )
 
 const (
@@ -115,6 +140,33 @@ func (lrw *logResponseWriter) WriteHeader(code int) {
 	})
 }
 
+func (w *accessLogResponseWriter) WriteHeader(code int) {
+	w.Do(func() {
+		w.statusCode = code
+		w.ResponseWriter.WriteHeader(code)
+	})
+}
+
+func (w *accessLogResponseWriter) Write(p []byte) (int, error) {
+	w.Do(func() { w.statusCode = http.StatusOK })
+	n, err := w.ResponseWriter.Write(p)
+	w.written += uint64(n)
+	return n, err
+}
+
+// Flush calls the underlying Flush.
+func (w *accessLogResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (b *accessLogBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	atomic.AddUint64(&b.read, uint64(n))
+	return n, err
+}
+
 func setRequestID(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// generate random UUIDv4
@@ -170,6 +222,64 @@ func logErrorResponse(l *zap.Logger) mux.MiddlewareFunc {
 	}
 }
 
+// logAccess writes an access log record of every request.
+func logAccess(l AccessLogger) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !l.Enabled() {
+				h.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			if r.Context().Value(ctxRequestInfo) == nil {
+				// unknown API requests don't pass through setRequestID
+				r = r.WithContext(SetReqInfo(r.Context(), NewReqInfo(w, r, ObjectRequest{})))
+			}
+
+			lw := &accessLogResponseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+			body := &accessLogBody{ReadCloser: r.Body}
+			r.Body = body
+
+			h.ServeHTTP(lw, r)
+
+			reqInfo := GetReqInfo(r.Context())
+			l.Log(&accesslog.Record{
+				Time:          start,
+				RemoteIP:      reqInfo.RemoteHost,
+				AccessKeyID:   reqInfo.AccessKeyID,
+				RequestID:     reqInfo.RequestID,
+				Method:        r.Method,
+				URI:           accessLogURI(r.URL),
+				Proto:         r.Proto,
+				API:           reqInfo.API,
+				Bucket:        reqInfo.BucketName,
+				Object:        reqInfo.ObjectName,
+				Status:        lw.statusCode,
+				ErrorCode:     reqInfo.ErrorCode,
+				BytesReceived: atomic.LoadUint64(&body.read),
+				BytesSent:     lw.written,
+				Duration:      time.Since(start),
+				Referer:       r.Referer(),
+				UserAgent:     r.UserAgent(),
+			})
+		})
+	}
+}
+
+// accessLogURI returns the request URI with the signature of presigned request removed.
+func accessLogURI(u *url.URL) string {
+	query := u.Query()
+	if query.Get(auth.AmzSignature) == "" {
+		return u.RequestURI()
+	}
+
+	query.Set(auth.AmzSignature, "REDACTED")
+	res := *u
+	res.RawQuery = query.Encode()
+	return res.RequestURI()
+}
+
 // GetRequestID returns the request ID from the response writer or the context.
 func GetRequestID(v interface{}) string {
 	switch t := v.(type) {
@@ -182,22 +292,34 @@ func GetRequestID(v interface{}) string {
 	}
 }
 
-// Attach adds S3 API handlers from h to r for domains with m client limit using
-// center authentication and log logger.
-func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center auth.Center, log *zap.Logger) {
+// Attach adds S3 API handlers from h to r for domains with m client limit and limiter
+// rate limits using center authentication and log logger. Served requests are written to accessLog.
+func Attach(r *mux.Router, domains []string, m MaxClients, limiter *ratelimit.Limiter, h Handler, center auth.Center, log *zap.Logger, accessLog AccessLogger) {
 	api := r.PathPrefix(SlashSeparator).Subrouter()
 
 	api.Use(
 		// -- prepare request
 		setRequestID,
 
+		// -- write access log
+		logAccess(accessLog),
+
+		// -- start the request span
+		traceRequest,
+
 		// -- logging error requests
 		logErrorResponse(log),
+
+		// -- limit request rate and bandwidth before the authentication
+		rateLimit(limiter),
 	)
 
 	// Attach user authentication for all S3 routes.
 	AttachUserAuth(api, center, log)
 
+	// -- limit request rate and bandwidth of the authenticated access keys
+	api.Use(rateLimitAccessKey(limiter))
+
 	buckets := make([]*mux.Router, 0, len(domains)+1)
 	buckets = append(buckets, api.PathPrefix("/{bucket}").Subrouter())
 
@@ -483,6 +605,6 @@ func Attach(r *mux.Router, domains []string, m MaxClients, h Handler, center aut
 		Name("ListBuckets")
 
 	// If none of the routes match, add default error handler routes
-	api.NotFoundHandler = metrics.APIStats("notfound", errorResponseHandler)
-	api.MethodNotAllowedHandler = metrics.APIStats("methodnotallowed", errorResponseHandler)
+	api.NotFoundHandler = logAccess(accessLog)(metrics.APIStats("notfound", errorResponseHandler))
+	api.MethodNotAllowedHandler = logAccess(accessLog)(metrics.APIStats("methodnotallowed", errorResponseHandler))
 }
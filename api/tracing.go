@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/tracing"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+// traceResponseWriter remembers the status code and the size of the response.
+type traceResponseWriter struct {
+	sync.Once
+	http.ResponseWriter
+
+	statusCode int
+	written    int64
+}
+
+func (w *traceResponseWriter) WriteHeader(code int) {
+	w.Do(func() {
+		w.statusCode = code
+		w.ResponseWriter.WriteHeader(code)
+	})
+}
+
+func (w *traceResponseWriter) Write(p []byte) (int, error) {
+	w.Do(func() { w.statusCode = http.StatusOK })
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// Flush calls the underlying Flush.
+func (w *traceResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// traceRequest starts a span for every S3 request. The span continues the trace
+// from the W3C traceparent header of the request if it's set.
+func traceRequest(h http.Handler) http.Handler {
+	propagator := propagation.TraceContext{}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		reqInfo := GetReqInfo(ctx)
+
+		name := r.Method
+		if route := mux.CurrentRoute(r); route != nil && route.GetName() != "" {
+			name = route.GetName()
+		}
+
+		ctx, span := tracing.StartServerSpan(ctx, name,
+			semconv.HTTPMethodKey.String(r.Method),
+			// the query isn't traced since it contains signatures of presigned requests
+			semconv.HTTPTargetKey.String(r.URL.Path),
+			semconv.HTTPRequestContentLengthKey.Int64(r.ContentLength),
+			tracing.AttrRequestID.String(reqInfo.RequestID),
+			tracing.AttrBucket.String(reqInfo.BucketName),
+			tracing.AttrObject.String(reqInfo.ObjectName),
+		)
+		defer span.End()
+
+		tw := &traceResponseWriter{ResponseWriter: w}
+		h.ServeHTTP(tw, r.WithContext(ctx))
+
+		span.SetAttributes(
+			semconv.HTTPStatusCodeKey.Int(tw.statusCode),
+			semconv.HTTPResponseContentLengthKey.Int64(tw.written),
+		)
+		if tw.statusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(tw.statusCode))
+		}
+	})
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
 	"github.com/nspcc-dev/neofs-s3-gw/api/errors"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
 	"go.uber.org/zap"
 )
 
@@ -22,10 +25,11 @@ func AttachUserAuth(router *mux.Router, center auth.Center, log *zap.Logger) {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			var ctx context.Context
 			box, err := center.Authenticate(r)
+			GetReqInfo(r.Context()).AccessKeyID = auth.AccessKeyID(r)
 			if err != nil {
 				if err == auth.ErrNoAuthorizationHeader {
 					log.Debug("couldn't receive access box for gate key, random key will be used")
-					ctx = r.Context()
+					ctx = metrics.ContextWithUser(r.Context(), metrics.AnonymousUser)
 				} else {
 					log.Error("failed to pass authentication", zap.Error(err))
 					if _, ok := err.(errors.Error); !ok {
@@ -36,9 +40,20 @@ func AttachUserAuth(router *mux.Router, center auth.Center, log *zap.Logger) {
 				}
 			} else {
 				ctx = context.WithValue(r.Context(), BoxData, box)
+				ctx = metrics.ContextWithUser(ctx, boxOwner(box))
 			}
 
 			h.ServeHTTP(w, r.WithContext(ctx))
 		})
 	})
 }
+
+// boxOwner returns the user the access box is issued by.
+func boxOwner(box *accessbox.Box) string {
+	if box.Gate == nil || box.Gate.BearerToken == nil {
+		return metrics.AnonymousUser
+	}
+
+	issuer := bearer.ResolveIssuer(*box.Gate.BearerToken)
+	return issuer.EncodeToString()
+}
@@ -6,11 +6,10 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"strconv"
+	"reflect"
 	"sync"
 	"syscall"
 	"time"
@@ -22,34 +21,56 @@ import (
 	"github.com/nspcc-dev/neofs-s3-gw/api/cache"
 	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
 	"github.com/nspcc-dev/neofs-s3-gw/api/notifications"
+	"github.com/nspcc-dev/neofs-s3-gw/api/ratelimit"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/accesslog"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/audit"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/health"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/wallet"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
 	"github.com/spf13/viper"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/zap"
 )
 
 type (
 	// App is the main application structure.
 	App struct {
-		ctr  auth.Center
-		log  *zap.Logger
-		cfg  *viper.Viper
-		pool *pool.Pool
-		key  *keys.PrivateKey
-		nc   *notifications.Controller
-		obj  layer.Client
-		api  api.Handler
+		ctr   auth.Center
+		log   *zap.Logger
+		cfg   *viper.Viper
+		conns *neofs.ConnPool
+		peers []pool.NodeParam
+		key   *keys.PrivateKey
+		nc    *notifications.Controller
+		obj   layer.Client
+		api   api.Handler
+
+		neoFS         layer.NeoFS
+		resolverNeoFS resolver.NeoFS
+		poolStat      StatisticScraper
+		treeStat      TreeStatisticScraper
 
 		metrics        *appMetrics
 		bucketResolver *resolver.BucketResolver
-		tlsProvider    *certProvider
+		servers        []*server
+		inflight       *inflightRequests
 		services       []*Service
+		importer       *bucketImporter
+		invalidation   *notifications.InvalidationBus
+		natsOptions    *notifications.Options
 		settings       *appSettings
 		maxClients     api.MaxClients
+		rateLimiter    *ratelimit.Limiter
+		tracerProvider *sdktrace.TracerProvider
+		accessLog      *accesslog.Logger
+		auditLog       *audit.Logger
+		auditWriter    audit.BucketWriter
+		health         *health.Checker
 
 		webDone chan struct{}
 		wrkDone chan struct{}
@@ -84,48 +105,72 @@ type (
 		SetHealth(int32)
 		Unregister()
 	}
+
+	// configurableHandler is an API handler which config can be replaced at runtime.
+	configurableHandler interface {
+		UpdateConfig(*handler.Config)
+	}
 )
 
-func newApp(ctx context.Context, log *Logger, v *viper.Viper) *App {
-	conns, key := getPool(ctx, log.logger, v)
-
-	// prepare auth center
-	ctr := auth.New(neofs.NewAuthmateNeoFS(conns), key, v.GetStringSlice(cfgAllowedAccessKeyIDPrefixes), getAccessBoxCacheConfig(v, log.logger))
+// prevPoolCloseDelay is a time after which the connection pool replaced on config
+// reload is closed.
+const prevPoolCloseDelay = 10 * time.Minute
 
+func newApp(ctx context.Context, log *Logger, v *viper.Viper) *App {
 	app := &App{
-		ctr:  ctr,
-		log:  log.logger,
-		cfg:  v,
-		pool: conns,
-		key:  key,
+		log: log.logger,
+		cfg: v,
 
 		webDone: make(chan struct{}, 1),
 		wrkDone: make(chan struct{}, 1),
 
-		maxClients: newMaxClients(v),
-		settings:   &appSettings{LogLevel: log.lvl},
+		maxClients:  newMaxClients(v),
+		rateLimiter: ratelimit.New(getRateLimitConfig(v, log.logger)),
+		settings:    &appSettings{LogLevel: log.lvl},
+		inflight:    newInflightRequests(),
 	}
 
+	var authNeoFS *neofs.AuthmateNeoFS
+	if v.GetBool(cfgDevEnabled) {
+		authNeoFS = app.initDevNeoFS(ctx)
+	} else {
+		key := getKey(log.logger, v)
+		app.peers = fetchPeers(log.logger, v)
+		conns, err := newPool(ctx, v, key, app.peers)
+		if err != nil {
+			log.logger.Fatal("failed to init connection pool", zap.Error(err))
+		}
+
+		app.conns = neofs.NewConnPool(conns)
+		app.key = key
+		app.neoFS = neofs.NewNeoFS(app.conns)
+		app.resolverNeoFS = neofs.NewResolverNeoFS(app.conns)
+		app.poolStat = neofs.NewPoolStatistic(app.conns)
+		authNeoFS = neofs.NewAuthmateNeoFSFrom(app.neoFS)
+	}
+
+	// prepare auth center
+	app.ctr = auth.New(authNeoFS, app.key, v.GetStringSlice(cfgAllowedAccessKeyIDPrefixes), getAccessBoxCacheConfig(v, log.logger))
+	app.ctr.SetFormLimits(fetchFormLimits(log.logger, v))
+
 	app.init(ctx)
 
 	return app
 }
 
 func (a *App) init(ctx context.Context) {
+	a.initTracing(ctx)
+	a.initAccessLog()
 	a.initHandlers(ctx)
+	a.initHealth()
 	a.initMetrics()
-	a.initTLSProvider()
+	a.initServers()
 }
 
 func (a *App) initLayer(ctx context.Context) {
 	a.initResolver()
 
-	treeServiceEndpoint := a.cfg.GetString(cfgTreeServiceEndpoint)
-	treeService, err := neofs.NewTreeClient(ctx, treeServiceEndpoint, a.key)
-	if err != nil {
-		a.log.Fatal("failed to create tree service", zap.Error(err))
-	}
-	a.log.Info("init tree service", zap.String("endpoint", treeServiceEndpoint))
+	treeService := a.initTreeService(ctx)
 
 	// prepare random key for anonymous requests
 	randomKey, err := keys.NewPrivateKey()
@@ -133,21 +178,35 @@ func (a *App) initLayer(ctx context.Context) {
 		a.log.Fatal("couldn't generate random key", zap.Error(err))
 	}
 
+	a.natsOptions = getNotificationsOptions(a.cfg, a.log)
+
+	cachesCfg := getCacheOptions(a.cfg, a.log)
+	if a.cfg.GetBool(cfgCacheInvalidationEnabled) {
+		a.invalidation, err = notifications.NewInvalidationBus(a.natsOptions,
+			a.cfg.GetString(cfgCacheInvalidationSubject), a.log)
+		if err != nil {
+			a.log.Fatal("failed to enable cache invalidation", zap.Error(err))
+		}
+		cachesCfg.Invalidation = a.invalidation
+	}
+
 	layerCfg := &layer.Config{
-		Caches: getCacheOptions(a.cfg, a.log),
+		Caches: cachesCfg,
 		AnonKey: layer.AnonymousKey{
 			Key: randomKey,
 		},
-		Resolver:    a.bucketResolver,
-		TreeService: treeService,
+		Resolver:      a.bucketResolver,
+		TreeService:   treeService,
+		DeleteWorkers: a.cfg.GetInt(cfgTreeDeleteWorkers),
+		TrackUsage:    a.cfg.GetBool(cfgUsageTrackAll),
 	}
 
 	// prepare object layer
-	a.obj = layer.NewLayer(a.log, neofs.NewNeoFS(a.pool), layerCfg)
+	a.obj = layer.NewLayer(a.log, a.neoFS, layerCfg)
+	a.auditWriter = newAuditBucketWriter(a.log, a.neoFS, a.key, *layerCfg)
 
 	if a.cfg.GetBool(cfgEnableNATS) {
-		nopts := getNotificationsOptions(a.cfg, a.log)
-		a.nc, err = notifications.NewController(nopts, a.log)
+		a.nc, err = notifications.NewController(a.natsOptions, a.log)
 		if err != nil {
 			a.log.Fatal("failed to enable notifications", zap.Error(err))
 		}
@@ -158,21 +217,88 @@ func (a *App) initLayer(ctx context.Context) {
 	}
 }
 
+func (a *App) initTreeService(ctx context.Context) *neofs.TreeClient {
+	switch backend := a.cfg.GetString(cfgTreeBackend); backend {
+	case treeBackendGRPC:
+		endpoints := fetchTreeEndpoints(a.log, a.cfg)
+		if len(endpoints) == 0 {
+			a.log.Fatal("tree service endpoints must be provided",
+				zap.String("key", cfgTreeServiceEndpoint), zap.String("peers_key", cfgTreePeers))
+		}
+
+		healthCheckTimeout := a.cfg.GetDuration(cfgHealthcheckTimeout)
+		if healthCheckTimeout <= 0 {
+			healthCheckTimeout = defaultHealthcheckTimeout
+		}
+		rebalanceInterval := a.cfg.GetDuration(cfgRebalanceInterval)
+		if rebalanceInterval <= 0 {
+			rebalanceInterval = defaultRebalanceInterval
+		}
+
+		service, err := neofs.NewServiceClientMulti(ctx, neofs.PrmServiceClientMulti{
+			Key:                a.key,
+			Endpoints:          endpoints,
+			HealthcheckTimeout: healthCheckTimeout,
+			RebalanceInterval:  rebalanceInterval,
+			Logger:             a.log,
+		})
+		if err != nil {
+			a.log.Fatal("failed to create tree service", zap.Error(err))
+		}
+		a.treeStat = service
+		a.log.Info("init tree service", zap.Int("endpoints", len(endpoints)))
+		return neofs.NewTreeClientWithService(service)
+	case treeBackendLocal:
+		path := a.cfg.GetString(cfgTreeLocalPath)
+		if path == "" {
+			a.log.Fatal("path to local tree database must be provided", zap.String("key", cfgTreeLocalPath))
+		}
+		service, err := neofs.NewServiceClientLocal(path)
+		if err != nil {
+			a.log.Fatal("failed to create local tree service", zap.Error(err))
+		}
+		a.log.Info("init local tree service", zap.String("path", path))
+		return neofs.NewTreeClientWithService(service)
+	default:
+		a.log.Fatal("unknown tree backend", zap.String("backend", backend))
+		return nil
+	}
+}
+
 func (a *App) initHandlers(ctx context.Context) {
 	a.initLayer(ctx)
 
-	var err error
-	handlerOptions := getHandlerOptions(a.cfg, a.log)
+	handlerOptions, err := getHandlerOptions(a.cfg, a.log)
+	if err != nil {
+		a.log.Fatal("invalid API handler options", zap.Error(err))
+	}
+
+	a.initAuditLog()
 
-	a.api, err = handler.New(a.log, a.obj, a.nc, handlerOptions)
+	a.api, err = handler.New(a.log, a.obj, a.nc, a.auditLog, handlerOptions)
 	if err != nil {
 		a.log.Fatal("could not initialize API handler", zap.Error(err))
 	}
 }
 
 func (a *App) initMetrics() {
-	gateMetricsProvider := newGateMetrics(neofs.NewPoolStatistic(a.pool))
+	gateMetricsProvider := newGateMetrics(a.poolStat, a.treeStat)
 	a.metrics = newAppMetrics(a.log, gateMetricsProvider, a.cfg.GetBool(cfgPrometheusEnabled))
+	a.setLabelledMetricsConfig()
+}
+
+// setLabelledMetricsConfig configures request metrics labelled by the bucket and by the user.
+func (a *App) setLabelledMetricsConfig() {
+	metrics.SetBucketLabelsConfig(metrics.LabelConfig{
+		Enabled:   a.cfg.GetBool(cfgPrometheusBucketsEnabled),
+		Allowed:   a.cfg.GetStringSlice(cfgPrometheusBucketsAllowed),
+		MaxValues: a.cfg.GetInt(cfgPrometheusBucketsMaxValues),
+	})
+	metrics.SetUserLabelsConfig(metrics.LabelConfig{
+		Enabled:   a.cfg.GetBool(cfgPrometheusUsersEnabled),
+		Allowed:   a.cfg.GetStringSlice(cfgPrometheusUsersAllowed),
+		MaxValues: a.cfg.GetInt(cfgPrometheusUsersMaxValues),
+	})
 }
 
 func (a *App) initResolver() {
@@ -183,15 +309,9 @@ func (a *App) initResolver() {
 	}
 }
 
-func (a *App) initTLSProvider() {
-	a.tlsProvider = &certProvider{
-		Enabled: a.cfg.IsSet(cfgTLSCertFile) || a.cfg.IsSet(cfgTLSKeyFile),
-	}
-}
-
 func (a *App) getResolverConfig() ([]string, *resolver.Config) {
 	resolveCfg := &resolver.Config{
-		NeoFS:      neofs.NewResolverNeoFS(a.pool),
+		NeoFS:      a.resolverNeoFS,
 		RPCAddress: a.cfg.GetString(cfgRPCEndpoint),
 	}
 
@@ -209,6 +329,10 @@ func (a *App) getResolverConfig() ([]string, *resolver.Config) {
 }
 
 func newMaxClients(cfg *viper.Viper) api.MaxClients {
+	return api.NewMaxClientsMiddleware(getMaxClientsLimits(cfg))
+}
+
+func getMaxClientsLimits(cfg *viper.Viper) (int, time.Duration) {
 	maxClientsCount := cfg.GetInt(cfgMaxClientsCount)
 	if maxClientsCount <= 0 {
 		maxClientsCount = defaultMaxClientsCount
@@ -219,22 +343,28 @@ func newMaxClients(cfg *viper.Viper) api.MaxClients {
 		maxClientsDeadline = defaultMaxClientsDeadline
 	}
 
-	return api.NewMaxClientsMiddleware(maxClientsCount, maxClientsDeadline)
+	return maxClientsCount, maxClientsDeadline
 }
 
-func getPool(ctx context.Context, logger *zap.Logger, cfg *viper.Viper) (*pool.Pool, *keys.PrivateKey) {
-	var prm pool.InitParameters
-
+func getKey(logger *zap.Logger, cfg *viper.Viper) *keys.PrivateKey {
 	password := wallet.GetPassword(cfg, cfgWalletPassphrase)
 	key, err := wallet.GetKeyFromPath(cfg.GetString(cfgWalletPath), cfg.GetString(cfgWalletAddress), password)
 	if err != nil {
 		logger.Fatal("could not load NeoFS private key", zap.Error(err))
 	}
 
-	prm.SetKey(&key.PrivateKey)
 	logger.Info("using credentials", zap.String("NeoFS", hex.EncodeToString(key.PublicKey().Bytes())))
 
-	for _, peer := range fetchPeers(logger, cfg) {
+	return key
+}
+
+// newPool creates and dials the connection pool to the peers.
+func newPool(ctx context.Context, cfg *viper.Viper, key *keys.PrivateKey, peers []pool.NodeParam) (*pool.Pool, error) {
+	var prm pool.InitParameters
+
+	prm.SetKey(&key.PrivateKey)
+
+	for _, peer := range peers {
 		prm.AddNode(peer)
 	}
 
@@ -264,14 +394,14 @@ func getPool(ctx context.Context, logger *zap.Logger, cfg *viper.Viper) (*pool.P
 
 	p, err := pool.NewPool(prm)
 	if err != nil {
-		logger.Fatal("failed to create connection pool", zap.Error(err))
+		return nil, fmt.Errorf("create connection pool: %w", err)
 	}
 
 	if err = p.Dial(ctx); err != nil {
-		logger.Fatal("failed to dial connection pool", zap.Error(err))
+		return nil, fmt.Errorf("dial connection pool: %w", err)
 	}
 
-	return p, key
+	return p, nil
 }
 
 func newAppMetrics(logger *zap.Logger, provider GateMetricsCollector, enabled bool) *appMetrics {
@@ -390,43 +520,35 @@ func (a *App) Serve(ctx context.Context) {
 	domains := a.cfg.GetStringSlice(cfgListenDomains)
 	a.log.Info("fetch domains, prepare to use API", zap.Strings("domains", domains))
 	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
-	api.Attach(router, domains, a.maxClients, a.api, a.ctr, a.log)
+	api.Attach(router, domains, a.maxClients, a.rateLimiter, a.api, a.ctr, a.log, a.accessLog)
 
-	// Use mux.Router as http.Handler
-	srv := new(http.Server)
-	srv.Handler = router
-	srv.ErrorLog = zap.NewStdLog(a.log)
+	handler := a.inflight.track(router)
 
 	a.startServices()
 
-	go func() {
-		addr := a.cfg.GetString(cfgListenAddress)
-		a.log.Info("starting server", zap.String("bind", addr))
-
-		var lic net.ListenConfig
-		ln, err := lic.Listen(ctx, "tcp", addr)
-		if err != nil {
-			a.log.Fatal("could not prepare listener", zap.Error(err))
+	for _, s := range a.servers {
+		// Use mux.Router as http.Handler
+		s.srv = &http.Server{
+			Handler:     s.handler(handler),
+			ErrorLog:    zap.NewStdLog(a.log),
+			BaseContext: a.inflight.baseContext,
 		}
 
-		if a.tlsProvider.Enabled {
-			certFile := a.cfg.GetString(cfgTLSCertFile)
-			keyFile := a.cfg.GetString(cfgTLSKeyFile)
+		go func(s *server) {
+			certFile, _ := s.cert.FilePaths()
+			a.log.Info("starting server", zap.String("bind", s.address),
+				zap.Bool("tls", s.cert.Enabled), zap.String("cert", certFile), zap.Bool("h2c", s.h2c))
 
-			a.log.Info("using certificate", zap.String("cert", certFile), zap.String("key", keyFile))
-			if err = a.tlsProvider.UpdateCert(certFile, keyFile); err != nil {
-				a.log.Fatal("failed to update cert", zap.Error(err))
+			ln, err := s.listen(ctx)
+			if err != nil {
+				a.log.Fatal("could not prepare listener", zap.Error(err))
 			}
 
-			ln = tls.NewListener(ln, &tls.Config{
-				GetCertificate: a.tlsProvider.GetCertificate,
-			})
-		}
-
-		if err = srv.Serve(ln); err != nil && err != http.ErrServerClosed {
-			a.log.Fatal("listen and serve", zap.Error(err))
-		}
-	}()
+			if err = s.srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+				a.log.Fatal("listen and serve", zap.Error(err))
+			}
+		}(s)
+	}
 
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGHUP)
@@ -437,17 +559,26 @@ LOOP:
 		case <-ctx.Done():
 			break LOOP
 		case <-sigs:
-			a.configReload()
+			a.configReload(ctx)
 		}
 	}
 
-	ctx, cancel := shutdownContext()
-	defer cancel()
-
-	a.log.Info("stopping server", zap.Error(srv.Shutdown(ctx)))
+	a.health.SetShuttingDown()
+	a.waitNotReady()
+	a.drain()
 
 	a.metrics.Shutdown()
 	a.stopServices()
+	a.shutdownTracing()
+	a.closeAccessLog()
+	a.closeAuditLog()
+	a.bucketResolver.Close()
+
+	if a.invalidation != nil {
+		if err := a.invalidation.Close(); err != nil {
+			a.log.Warn("couldn't close cache invalidation bus", zap.Error(err))
+		}
+	}
 
 	close(a.webDone)
 }
@@ -456,7 +587,7 @@ func shutdownContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), defaultShutdownTimeout)
 }
 
-func (a *App) configReload() {
+func (a *App) configReload(ctx context.Context) {
 	a.log.Info("SIGHUP config reload started")
 
 	if !a.cfg.IsSet(cmdConfig) {
@@ -472,16 +603,19 @@ func (a *App) configReload() {
 		a.log.Warn("failed to reload resolvers", zap.Error(err))
 	}
 
-	if err := a.tlsProvider.UpdateCert(a.cfg.GetString(cfgTLSCertFile), a.cfg.GetString(cfgTLSKeyFile)); err != nil {
-		a.log.Warn("failed to reload TLS certs", zap.Error(err))
-	}
+	a.updateServers()
 
 	a.stopServices()
 	a.startServices()
 
 	a.updateSettings()
+	a.updatePeers(ctx)
+	a.updateNATS()
 
 	a.metrics.SetEnabled(a.cfg.GetBool(cfgPrometheusEnabled))
+	a.setLabelledMetricsConfig()
+	a.updateAccessLog()
+	a.updateAuditLog()
 	a.setHealthStatus()
 
 	a.log.Info("SIGHUP config reload completed")
@@ -493,6 +627,67 @@ func (a *App) updateSettings() {
 	} else {
 		a.settings.LogLevel.SetLevel(lvl)
 	}
+
+	a.maxClients.Update(getMaxClientsLimits(a.cfg))
+	a.rateLimiter.Update(getRateLimitConfig(a.cfg, a.log))
+	a.ctr.SetAllowedAccessKeyIDPrefixes(a.cfg.GetStringSlice(cfgAllowedAccessKeyIDPrefixes))
+	a.ctr.SetFormLimits(fetchFormLimits(a.log, a.cfg))
+	a.obj.UpdateCaches(getCacheOptions(a.cfg, a.log))
+
+	if handlerOptions, err := getHandlerOptions(a.cfg, a.log); err != nil {
+		a.log.Warn("API handler options won't be updated", zap.Error(err))
+	} else if h, ok := a.api.(configurableHandler); ok {
+		h.UpdateConfig(handlerOptions)
+	}
+}
+
+// updatePeers replaces the connection pool if the list of peers is changed. The previous
+// pool is closed after a delay to let requests in progress complete.
+func (a *App) updatePeers(ctx context.Context) {
+	if a.conns == nil {
+		return
+	}
+
+	peers := fetchPeers(a.log, a.cfg)
+	if reflect.DeepEqual(peers, a.peers) {
+		return
+	}
+
+	p, err := newPool(ctx, a.cfg, a.key, peers)
+	if err != nil {
+		a.log.Warn("connection pool peers won't be updated", zap.Error(err))
+		return
+	}
+
+	prev := a.conns.Replace(p)
+	a.peers = peers
+	time.AfterFunc(prevPoolCloseDelay, prev.Close)
+
+	a.log.Info("connection pool peers updated", zap.Int("peers", len(peers)))
+}
+
+// updateNATS reconnects to NATS server if the connection options are changed.
+func (a *App) updateNATS() {
+	opts := getNotificationsOptions(a.cfg, a.log)
+	if reflect.DeepEqual(opts, a.natsOptions) || (a.nc == nil && a.invalidation == nil) {
+		return
+	}
+
+	if a.nc != nil {
+		if err := a.nc.Reconnect(opts); err != nil {
+			a.log.Warn("notifications won't be reconnected", zap.Error(err))
+			return
+		}
+	}
+	if a.invalidation != nil {
+		if err := a.invalidation.Reconnect(opts); err != nil {
+			a.log.Warn("cache invalidation won't be reconnected", zap.Error(err))
+			return
+		}
+	}
+
+	a.natsOptions = opts
+	a.log.Info("reconnected to NATS server", zap.String("endpoint", opts.URL))
 }
 
 func (a *App) startServices() {
@@ -503,6 +698,17 @@ func (a *App) startServices() {
 	prometheusService := NewPrometheusService(a.cfg, a.log)
 	a.services = append(a.services, prometheusService)
 	go prometheusService.Start()
+
+	adminService := a.newAdminService()
+	a.services = append(a.services, adminService)
+	go adminService.Start()
+
+	healthService := a.newHealthService()
+	a.services = append(a.services, healthService)
+	go healthService.Start()
+
+	a.importer = newBucketImporter(a.cfg, a.log, a.obj, a.importer)
+	a.importer.Start()
 }
 
 func (a *App) stopServices() {
@@ -512,6 +718,10 @@ func (a *App) stopServices() {
 	for _, svc := range a.services {
 		svc.ShutDown(ctx)
 	}
+
+	if a.importer != nil {
+		a.importer.Stop()
+	}
 }
 
 func getNotificationsOptions(v *viper.Viper, l *zap.Logger) *notifications.Options {
@@ -595,7 +805,7 @@ func getAccessBoxCacheConfig(v *viper.Viper, l *zap.Logger) *cache.Config {
 	return cacheCfg
 }
 
-func getHandlerOptions(v *viper.Viper, l *zap.Logger) *handler.Config {
+func getHandlerOptions(v *viper.Viper, l *zap.Logger) (*handler.Config, error) {
 	var (
 		cfg             handler.Config
 		err             error
@@ -609,17 +819,14 @@ func getHandlerOptions(v *viper.Viper, l *zap.Logger) *handler.Config {
 	}
 
 	if err = cfg.DefaultPolicy.DecodeString(policyStr); err != nil {
-		l.Fatal("couldn't parse container default policy",
-			zap.Error(err))
+		return nil, fmt.Errorf("couldn't parse container default policy: %w", err)
 	}
 
 	if v.IsSet(cfgDefaultMaxAge) {
 		defaultMaxAge = v.GetInt(cfgDefaultMaxAge)
 
 		if defaultMaxAge <= 0 && defaultMaxAge != -1 {
-			l.Fatal("invalid defaultMaxAge",
-				zap.String("parameter", cfgDefaultMaxAge),
-				zap.String("value in config", strconv.Itoa(defaultMaxAge)))
+			return nil, fmt.Errorf("invalid '%s' value in config: %d", cfgDefaultMaxAge, defaultMaxAge)
 		}
 	}
 
@@ -629,8 +836,10 @@ func getHandlerOptions(v *viper.Viper, l *zap.Logger) *handler.Config {
 
 	cfg.DefaultMaxAge = defaultMaxAge
 	cfg.NotificatorEnabled = v.GetBool(cfgEnableNATS)
-	cfg.TLSEnabled = v.IsSet(cfgTLSKeyFile) && v.IsSet(cfgTLSCertFile)
+	cfg.TLSEnabled = tlsEnabled(v)
 	cfg.CopiesNumber = setCopiesNumber
+	cfg.StorageClasses = fetchStorageClasses(l, v)
+	cfg.Limits = fetchLimits(l, v)
 
-	return &cfg
+	return &cfg, nil
 }
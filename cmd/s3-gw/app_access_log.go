@@ -0,0 +1,49 @@
+package main
+
+import (
+	"github.com/nspcc-dev/neofs-s3-gw/internal/accesslog"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/logsink"
+	"go.uber.org/zap"
+)
+
+func (a *App) accessLogConfig() accesslog.Config {
+	return accesslog.Config{
+		Enabled: a.cfg.GetBool(cfgAccessLogEnabled),
+		Format:  a.cfg.GetString(cfgAccessLogFormat),
+		Sink: logsink.Config{
+			Sink:           a.cfg.GetString(cfgAccessLogSink),
+			FilePath:       a.cfg.GetString(cfgAccessLogFilePath),
+			FileMaxSize:    a.cfg.GetInt64(cfgAccessLogFileMaxSize) << 20,
+			FileMaxBackups: a.cfg.GetInt(cfgAccessLogFileMaxBackups),
+			SyslogAddress:  a.cfg.GetString(cfgAccessLogSyslogAddress),
+		},
+	}
+}
+
+// initAccessLog opens the sink of the access log if it's enabled.
+func (a *App) initAccessLog() {
+	cfg := a.accessLogConfig()
+
+	var err error
+	if a.accessLog, err = accesslog.New(cfg); err != nil {
+		a.log.Fatal("couldn't init access log", zap.Error(err))
+	}
+
+	if cfg.Enabled {
+		a.log.Info("access log is enabled", zap.String("format", cfg.Format), zap.String("sink", cfg.Sink.Sink))
+	}
+}
+
+// updateAccessLog reopens the sink of the access log with the reloaded config,
+// so the rotated file is recreated.
+func (a *App) updateAccessLog() {
+	if err := a.accessLog.Apply(a.accessLogConfig()); err != nil {
+		a.log.Warn("access log won't be updated", zap.Error(err))
+	}
+}
+
+func (a *App) closeAccessLog() {
+	if err := a.accessLog.Close(); err != nil {
+		a.log.Warn("couldn't close access log", zap.Error(err))
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/admin"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const redactedValue = "<redacted>"
+
+// secretSettings are suffixes of settings which aren't exposed by admin API. Suffixes are used
+// to cover every section with the same keys, e.g. TLS key files of all server listeners.
+var secretSettings = []string{
+	"passphrase", // cfgWalletPassphrase
+	"key_file",   // cfgTLSKeyFile, cfgNATSAuthPrivateKeyFile, server.N.tls.key_file
+	"token",      // cfgAdminToken
+	cfgTracingHeaders,
+}
+
+// newAdminService creates a new service for gateway administration API.
+// The service isn't started if the token isn't set.
+func (a *App) newAdminService() *Service {
+	v := a.cfg
+	log := a.log.With(zap.String("service", "Admin"))
+
+	enabled := v.GetBool(cfgAdminEnabled)
+	token := v.GetString(cfgAdminToken)
+	if enabled && len(token) == 0 {
+		log.Warn("admin API is disabled since token is empty")
+		enabled = false
+	}
+
+	cfg := &admin.Config{
+		Token:              token,
+		PoolErrorThreshold: v.GetUint32(cfgPoolErrorThreshold),
+		Settings:           func() map[string]interface{} { return redactedSettings(v) },
+		Resolver:           a.bucketResolver,
+		LogLevel:           a.settings.LogLevel,
+	}
+	if cfg.PoolErrorThreshold == 0 {
+		cfg.PoolErrorThreshold = defaultPoolErrorThreshold
+	}
+	if a.poolStat != nil {
+		cfg.PoolStatistic = a.poolStat.Statistic
+	}
+	if a.treeStat != nil {
+		cfg.TreeStatistic = a.treeStat.Statistic
+	}
+
+	return &Service{
+		Server: &http.Server{
+			Addr:    v.GetString(cfgAdminAddress),
+			Handler: admin.NewRouter(log, a.obj, cfg),
+		},
+		enabled:     enabled,
+		serviceType: "Admin",
+		log:         log,
+	}
+}
+
+// redactedSettings returns all settings as a nested map with the secret values replaced.
+func redactedSettings(v *viper.Viper) map[string]interface{} {
+	res := make(map[string]interface{})
+	for _, key := range v.AllKeys() {
+		value := redactValue(key, v.Get(key))
+
+		path := strings.Split(key, ".")
+		section := res
+		for _, name := range path[:len(path)-1] {
+			sub, ok := section[name].(map[string]interface{})
+			if !ok {
+				sub = make(map[string]interface{})
+				section[name] = sub
+			}
+			section = sub
+		}
+		section[path[len(path)-1]] = value
+	}
+
+	return res
+}
+
+// redactValue replaces the value of the secret setting. Values of lists and sections are
+// redacted recursively since the same settings can be set by nested YAML structures.
+func redactValue(key string, value interface{}) interface{} {
+	if isSecretSetting(key) {
+		if value == nil || value == "" {
+			return value
+		}
+		return redactedValue
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			res[k] = redactValue(key+"."+k, v)
+		}
+		return res
+	case map[interface{}]interface{}:
+		res := make(map[interface{}]interface{}, len(val))
+		for k, v := range val {
+			res[k] = redactValue(key+"."+fmt.Sprint(k), v)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(val))
+		for i, v := range val {
+			res[i] = redactValue(key+"."+strconv.Itoa(i), v)
+		}
+		return res
+	}
+
+	return value
+}
+
+func isSecretSetting(key string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretSettings {
+		if strings.HasSuffix(key, secret) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/audit"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/logsink"
+	"go.uber.org/zap"
+)
+
+// auditBucketWriter uploads audit objects on behalf of the gateway, so the audit bucket
+// can allow writes to the gateway key only and be read-only for everyone else.
+type auditBucketWriter struct {
+	obj layer.Client
+}
+
+func newAuditBucketWriter(log *zap.Logger, neoFS layer.NeoFS, key *keys.PrivateKey, cfg layer.Config) *auditBucketWriter {
+	// requests without credentials are signed with the anonymous key, it's replaced with the gateway one
+	cfg.AnonKey = layer.AnonymousKey{Key: key}
+	return &auditBucketWriter{obj: layer.NewLayer(log, neoFS, &cfg)}
+}
+
+func (w *auditBucketWriter) PutAuditObject(ctx context.Context, bucket, key string, payload []byte) error {
+	bktInfo, err := w.obj.GetBucketInfo(ctx, bucket)
+	if err != nil {
+		return fmt.Errorf("get audit bucket info: %w", err)
+	}
+
+	_, err = w.obj.PutObject(ctx, &layer.PutObjectParams{
+		BktInfo: bktInfo,
+		Object:  key,
+		Size:    int64(len(payload)),
+		Reader:  bytes.NewReader(payload),
+		Header:  map[string]string{api.ContentType: "application/x-ndjson"},
+	})
+	if err != nil {
+		return fmt.Errorf("put audit object: %w", err)
+	}
+
+	return nil
+}
+
+func (a *App) auditLogConfig() audit.Config {
+	return audit.Config{
+		Enabled: a.cfg.GetBool(cfgAuditEnabled),
+		Sink: logsink.Config{
+			Sink:           a.cfg.GetString(cfgAuditSink),
+			FilePath:       a.cfg.GetString(cfgAuditFilePath),
+			FileMaxSize:    a.cfg.GetInt64(cfgAuditFileMaxSize) << 20,
+			FileMaxBackups: a.cfg.GetInt(cfgAuditFileMaxBackups),
+			SyslogAddress:  a.cfg.GetString(cfgAuditSyslogAddress),
+		},
+		Bucket: audit.BucketConfig{
+			Name:          a.cfg.GetString(cfgAuditBucketName),
+			Prefix:        a.cfg.GetString(cfgAuditBucketPrefix),
+			FlushInterval: a.cfg.GetDuration(cfgAuditBucketFlushInterval),
+		},
+	}
+}
+
+// initAuditLog opens the sink of the audit log if it's enabled.
+func (a *App) initAuditLog() {
+	cfg := a.auditLogConfig()
+
+	var err error
+	if a.auditLog, err = audit.New(cfg, a.auditWriter, a.log); err != nil {
+		a.log.Fatal("couldn't init audit log", zap.Error(err))
+	}
+
+	if cfg.Enabled {
+		a.log.Info("audit log is enabled", zap.String("sink", cfg.Sink.Sink))
+	}
+}
+
+// updateAuditLog reopens the sink of the audit log with the reloaded config.
+// Events buffered for the audit bucket are uploaded.
+func (a *App) updateAuditLog() {
+	if err := a.auditLog.Apply(a.auditLogConfig()); err != nil {
+		a.log.Warn("audit log won't be updated", zap.Error(err))
+	}
+}
+
+func (a *App) closeAuditLog() {
+	if err := a.auditLog.Close(); err != nil {
+		a.log.Warn("couldn't close audit log", zap.Error(err))
+	}
+}
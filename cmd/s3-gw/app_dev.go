@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/authmate"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/wallet"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"go.uber.org/zap"
+)
+
+const (
+	devNeoFSDir         = "neofs"
+	devTreeFile         = "tree.db"
+	devGatewayKeyFile   = "gateway.key"
+	devUserKeyFile      = "user.key"
+	devCredentialsFile  = "credentials.json"
+	devCredentialsLabel = "s3-credentials"
+
+	devCredentialsLifetime = 10 * 365 * 24 * time.Hour
+)
+
+type devCredentials struct {
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+}
+
+// initDevNeoFS prepares in-process NeoFS, local tree service and S3 credentials
+// for the development mode. All data is stored in the directory from the config.
+func (a *App) initDevNeoFS(ctx context.Context) *neofs.AuthmateNeoFS {
+	dir := a.cfg.GetString(cfgDevPath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		a.log.Fatal("failed to create directory for development data", zap.Error(err))
+	}
+
+	var err error
+	if a.cfg.GetString(cfgWalletPath) != "" {
+		password := wallet.GetPassword(a.cfg, cfgWalletPassphrase)
+		a.key, err = wallet.GetKeyFromPath(a.cfg.GetString(cfgWalletPath), a.cfg.GetString(cfgWalletAddress), password)
+	} else {
+		a.key, err = loadOrGenerateKey(filepath.Join(dir, devGatewayKeyFile))
+	}
+	if err != nil {
+		a.log.Fatal("could not load NeoFS private key", zap.Error(err))
+	}
+
+	local, err := neofs.NewLocalNeoFS(filepath.Join(dir, devNeoFSDir), a.key, a.cfg.GetDuration(cfgDevEpochDuration))
+	if err != nil {
+		a.log.Fatal("failed to create in-process NeoFS", zap.Error(err))
+	}
+
+	a.neoFS = local
+	a.resolverNeoFS = local
+	a.poolStat = local
+
+	// these values must survive config reload
+	a.cfg.Set(cfgTreeBackend, treeBackendLocal)
+	a.cfg.Set(cfgTreeLocalPath, filepath.Join(dir, devTreeFile))
+	a.cfg.Set(cfgResolveOrder, []string{resolver.LocalResolver})
+
+	creds, err := a.devCredentials(ctx, local, dir)
+	if err != nil {
+		a.log.Fatal("failed to issue credentials for development mode", zap.Error(err))
+	}
+
+	a.log.Warn("gateway is running in development mode with in-process NeoFS, don't use it in production",
+		zap.String("path", dir))
+	a.log.Info("development mode credentials",
+		zap.String("access_key_id", creds.AccessKeyID),
+		zap.String("secret_access_key", creds.SecretAccessKey))
+
+	return neofs.NewAuthmateNeoFSFrom(local)
+}
+
+// devCredentials reads previously issued credentials or issues new ones
+// for the generated user key.
+func (a *App) devCredentials(ctx context.Context, local *neofs.LocalNeoFS, dir string) (*devCredentials, error) {
+	path := filepath.Join(dir, devCredentialsFile)
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		creds := new(devCredentials)
+		if err = json.Unmarshal(data, creds); err != nil {
+			return nil, fmt.Errorf("invalid credentials file: %w", err)
+		}
+		return creds, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read credentials file: %w", err)
+	}
+
+	userKey, err := loadOrGenerateKey(filepath.Join(dir, devUserKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("load user key: %w", err)
+	}
+
+	rules, err := devBearerRules()
+	if err != nil {
+		return nil, err
+	}
+
+	agent := authmate.New(a.log, neofs.NewAuthmateNeoFSFrom(local.WithKey(userKey)))
+
+	var buf bytes.Buffer
+	err = agent.IssueSecret(ctx, &buf, &authmate.IssueSecretOptions{
+		Container: authmate.ContainerOptions{
+			FriendlyName:    devCredentialsLabel,
+			PlacementPolicy: "REP 1",
+		},
+		NeoFSKey:        userKey,
+		GatesPublicKeys: []*keys.PublicKey{a.key.PublicKey()},
+		EACLRules:       rules,
+		Lifetime:        devCredentialsLifetime,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	creds := new(devCredentials)
+	if err = json.Unmarshal(buf.Bytes(), creds); err != nil {
+		return nil, fmt.Errorf("decode issued credentials: %w", err)
+	}
+
+	if err = os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+		return nil, fmt.Errorf("save credentials file: %w", err)
+	}
+
+	return creds, nil
+}
+
+// devBearerRules allows all object operations to the gateway, so user's rights
+// are limited by bucket ACL only.
+func devBearerRules() ([]byte, error) {
+	table := eacl.NewTable()
+	for op := eacl.OperationGet; op <= eacl.OperationRangeHash; op++ {
+		record := eacl.NewRecord()
+		record.SetOperation(op)
+		record.SetAction(eacl.ActionAllow)
+		eacl.AddFormedTarget(record, eacl.RoleOthers)
+		table.AddRecord(record)
+	}
+
+	return table.MarshalJSON()
+}
+
+func loadOrGenerateKey(path string) (*keys.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return keys.NewPrivateKeyFromWIF(strings.TrimSpace(string(data)))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key, err := keys.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return key, os.WriteFile(path, []byte(key.WIF()), 0600)
+}
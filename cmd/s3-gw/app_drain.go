@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type (
+	// inflightRequests tracks requests in progress, so they can be canceled and reported
+	// if they aren't finished until the drain deadline.
+	inflightRequests struct {
+		ctx    context.Context
+		cancel context.CancelFunc
+
+		mu       sync.Mutex
+		requests map[*inflightRequest]struct{}
+		wg       sync.WaitGroup
+	}
+
+	inflightRequest struct {
+		method  string
+		host    string
+		uri     string
+		remote  string
+		started time.Time
+	}
+)
+
+func newInflightRequests() *inflightRequests {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &inflightRequests{
+		ctx:      ctx,
+		cancel:   cancel,
+		requests: make(map[*inflightRequest]struct{}),
+	}
+}
+
+// baseContext is a base context of all requests, it's canceled by abort.
+func (f *inflightRequests) baseContext(net.Listener) context.Context {
+	return f.ctx
+}
+
+// track wraps h to register requests in progress.
+func (f *inflightRequests) track(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &inflightRequest{
+			method:  r.Method,
+			host:    r.Host,
+			uri:     r.RequestURI,
+			remote:  r.RemoteAddr,
+			started: time.Now(),
+		}
+
+		f.mu.Lock()
+		f.requests[req] = struct{}{}
+		f.wg.Add(1)
+		f.mu.Unlock()
+
+		defer func() {
+			f.mu.Lock()
+			delete(f.requests, req)
+			f.wg.Done()
+			f.mu.Unlock()
+		}()
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// abort cancels contexts of all requests and returns the requests in progress.
+func (f *inflightRequests) abort() []*inflightRequest {
+	f.cancel()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	res := make([]*inflightRequest, 0, len(f.requests))
+	for req := range f.requests {
+		res = append(res, req)
+	}
+
+	return res
+}
+
+// wait waits for all requests to be finished until the timeout.
+func (f *inflightRequests) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// waitNotReady keeps serving requests for the ready delay after the gateway became not ready,
+// so load balancers polling the readiness probe stop routing requests before listeners are closed.
+// There is no delay if the health service is disabled.
+func (a *App) waitNotReady() {
+	delay := a.cfg.GetDuration(cfgShutdownReadyDelay)
+	if delay <= 0 || !a.cfg.GetBool(cfgHealthEnabled) {
+		return
+	}
+
+	a.log.Info("waiting for load balancers to notice the gateway isn't ready", zap.Duration("delay", delay))
+	time.Sleep(delay)
+}
+
+// drain stops the servers gracefully: listeners are closed and active requests are allowed
+// to finish until the drain timeout. Then contexts of the remaining requests are canceled,
+// connections are closed and requests get abort timeout to clean up.
+func (a *App) drain() {
+	drainTimeout := a.cfg.GetDuration(cfgShutdownDrainTimeout)
+	if drainTimeout <= 0 {
+		drainTimeout = defaultShutdownTimeout
+	}
+	abortTimeout := a.cfg.GetDuration(cfgShutdownAbortTimeout)
+	if abortTimeout <= 0 {
+		abortTimeout = defaultShutdownAbortTimeout
+	}
+
+	a.log.Info("draining active requests", zap.Duration("timeout", drainTimeout))
+
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(a.servers))
+	)
+	for i, s := range a.servers {
+		wg.Add(1)
+		go func(i int, s *server) {
+			defer wg.Done()
+			errs[i] = s.srv.Shutdown(ctx)
+			a.log.Info("stopping server", zap.String("bind", s.address), zap.Error(errs[i]))
+		}(i, s)
+	}
+	wg.Wait()
+
+	drained := true
+	for _, err := range errs {
+		if err != nil {
+			drained = false
+		}
+	}
+	if drained {
+		return
+	}
+
+	for _, req := range a.inflight.abort() {
+		a.log.Warn("request is aborted on shutdown",
+			zap.String("method", req.method),
+			zap.String("host", req.host),
+			zap.String("uri", req.uri),
+			zap.String("remote", req.remote),
+			zap.Duration("duration", time.Since(req.started)))
+	}
+
+	for _, s := range a.servers {
+		if err := s.srv.Close(); err != nil {
+			a.log.Warn("couldn't close server", zap.String("bind", s.address), zap.Error(err))
+		}
+	}
+
+	if !a.inflight.wait(abortTimeout) {
+		a.log.Warn("aborted requests aren't finished in time", zap.Duration("timeout", abortTimeout))
+	}
+}
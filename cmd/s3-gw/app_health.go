@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/nspcc-dev/neofs-s3-gw/internal/health"
+	"go.uber.org/zap"
+)
+
+// Names of the readiness checks.
+const (
+	healthCheckNeoFS        = "neofs"
+	healthCheckTree         = "tree"
+	healthCheckResolver     = "resolver"
+	healthCheckNATS         = "nats"
+	healthCheckInvalidation = "cache_invalidation"
+)
+
+// initHealth creates checks of the gateway dependencies for the readiness probe.
+func (a *App) initHealth() {
+	var checks []health.Check
+	if a.conns != nil {
+		checks = append(checks, health.Check{Name: healthCheckNeoFS, Check: a.conns.Healthcheck})
+	}
+	if a.treeStat != nil {
+		checks = append(checks, health.Check{Name: healthCheckTree, Check: treeHealthcheck(a.treeStat)})
+	}
+	checks = append(checks, health.Check{Name: healthCheckResolver, Check: a.bucketResolver.Healthcheck})
+	if a.nc != nil {
+		checks = append(checks, health.Check{Name: healthCheckNATS, Check: a.nc.Healthcheck})
+	}
+	if a.invalidation != nil {
+		checks = append(checks, health.Check{Name: healthCheckInvalidation, Check: a.invalidation.Healthcheck})
+	}
+
+	a.health = health.NewChecker(checks)
+}
+
+// treeHealthcheck checks that at least one of the tree service endpoints is healthy.
+func treeHealthcheck(stat TreeStatisticScraper) func(context.Context) error {
+	return func(context.Context) error {
+		for _, endpoint := range stat.Statistic() {
+			if endpoint.Healthy {
+				return nil
+			}
+		}
+		return errors.New("no healthy tree service endpoints")
+	}
+}
+
+// newHealthService creates a new service for liveness and readiness probes.
+func (a *App) newHealthService() *Service {
+	return &Service{
+		Server: &http.Server{
+			Addr:    a.cfg.GetString(cfgHealthAddress),
+			Handler: health.NewHandler(a.health, a.cfg.GetDuration(cfgHealthTimeout)),
+		},
+		enabled:     a.cfg.GetBool(cfgHealthEnabled),
+		serviceType: "Health",
+		log:         a.log.With(zap.String("service", "Health")),
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// bucketImporter periodically indexes objects stored in bucket containers bypassing
+// the gateway (e.g. by NeoFS CLI or HTTP gateway), so they become visible through S3.
+// Only objects created since the previous import are searched, positions of the import
+// are stored in the bucket trees, so the import is continued after the gateway restart.
+type bucketImporter struct {
+	log      *zap.Logger
+	obj      layer.Client
+	buckets  []string
+	interval time.Duration
+	minAge   time.Duration
+	cursors  map[string]*layer.RebuildCursor
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newBucketImporter creates an importer of the configured buckets. Positions of the
+// previous importer are kept, positions of other buckets are loaded from their trees.
+func newBucketImporter(v *viper.Viper, l *zap.Logger, obj layer.Client, prev *bucketImporter) *bucketImporter {
+	interval := v.GetDuration(cfgImportInterval)
+	if interval <= 0 {
+		l.Error("invalid import interval, using default value",
+			zap.Duration("value in config", interval),
+			zap.Duration("default", defaultImportInterval))
+		interval = defaultImportInterval
+	}
+
+	minAge := v.GetDuration(cfgImportMinAge)
+	if minAge < 0 {
+		l.Error("invalid import min age, using default value",
+			zap.Duration("value in config", minAge),
+			zap.Duration("default", defaultImportMinAge))
+		minAge = defaultImportMinAge
+	}
+
+	cursors := make(map[string]*layer.RebuildCursor)
+	if prev != nil {
+		cursors = prev.cursors
+	}
+
+	i := &bucketImporter{
+		log:      l.With(zap.String("service", "Import")),
+		obj:      obj,
+		buckets:  v.GetStringSlice(cfgImportBuckets),
+		interval: interval,
+		minAge:   minAge,
+		cursors:  cursors,
+	}
+
+	ctx := context.Background()
+	for _, name := range i.buckets {
+		if _, ok := i.cursors[name]; ok {
+			continue
+		}
+
+		bktInfo, err := obj.GetBucketInfo(ctx, name)
+		if err != nil {
+			// the cursor is loaded on the first import of the bucket
+			i.log.Warn("couldn't get bucket to load import position", zap.String("bucket", name), zap.Error(err))
+			continue
+		}
+		i.loadCursor(ctx, bktInfo)
+	}
+
+	return i
+}
+
+// loadCursor loads the import position of the bucket from its tree.
+func (i *bucketImporter) loadCursor(ctx context.Context, bktInfo *data.BucketInfo) *layer.RebuildCursor {
+	cursor, err := i.obj.GetImportCursor(ctx, bktInfo)
+	if err != nil {
+		i.log.Warn("couldn't load import position", zap.String("bucket", bktInfo.Name), zap.Error(err))
+		return nil
+	}
+
+	i.cursors[bktInfo.Name] = cursor
+	return cursor
+}
+
+// Start runs import of the configured buckets in background.
+func (i *bucketImporter) Start() {
+	if len(i.buckets) == 0 {
+		return
+	}
+
+	var ctx context.Context
+	ctx, i.cancel = context.WithCancel(context.Background())
+
+	i.log.Info("bucket import is running", zap.Strings("buckets", i.buckets), zap.Duration("interval", i.interval),
+		zap.Duration("min_age", i.minAge))
+
+	i.wg.Add(1)
+	go i.run(ctx)
+}
+
+// Stop stops background import and waits for the current iteration to finish.
+func (i *bucketImporter) Stop() {
+	if i.cancel == nil {
+		return
+	}
+
+	i.cancel()
+	i.wg.Wait()
+}
+
+func (i *bucketImporter) run(ctx context.Context) {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		i.importBuckets(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (i *bucketImporter) importBuckets(ctx context.Context) {
+	for _, name := range i.buckets {
+		if ctx.Err() != nil {
+			return
+		}
+
+		bktInfo, err := i.obj.GetBucketInfo(ctx, name)
+		if err != nil {
+			i.log.Warn("couldn't get bucket to import", zap.String("bucket", name), zap.Error(err))
+			continue
+		}
+
+		cursor, ok := i.cursors[name]
+		if !ok {
+			// the full rescan isn't started until the stored position is known
+			if cursor = i.loadCursor(ctx, bktInfo); cursor == nil {
+				continue
+			}
+		}
+
+		prm := &layer.RebuildBucketParams{
+			BktInfo:      bktInfo,
+			MinAge:       i.minAge,
+			ExternalOnly: true,
+			Cursor:       cursor,
+		}
+		if _, err = i.obj.RebuildBucketTree(ctx, prm); err != nil {
+			i.log.Warn("couldn't import bucket objects", zap.String("bucket", name), zap.Error(err))
+			continue
+		}
+
+		// the position is stored after every import, so it's retried if the previous store fails
+		if err = i.obj.PutImportCursor(ctx, bktInfo, cursor); err != nil {
+			i.log.Warn("couldn't store import position", zap.String("bucket", name), zap.Error(err))
+		}
+	}
+}
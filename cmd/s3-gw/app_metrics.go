@@ -3,6 +3,7 @@ package main
 import (
 	"net/http"
 
+	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -14,6 +15,7 @@ const (
 	namespace      = "neofs_s3_gw"
 	stateSubsystem = "state"
 	poolSubsystem  = "pool"
+	treeSubsystem  = "tree"
 
 	methodGetBalance       = "get_balance"
 	methodPutContainer     = "put_container"
@@ -36,9 +38,14 @@ type StatisticScraper interface {
 	Statistic() pool.Statistic
 }
 
+type TreeStatisticScraper interface {
+	Statistic() []neofs.TreeEndpointStatistic
+}
+
 type GateMetrics struct {
 	stateMetrics
 	poolMetricsCollector
+	treeMetricsCollector
 }
 
 type stateMetrics struct {
@@ -54,22 +61,34 @@ type poolMetricsCollector struct {
 	requestDuration     *prometheus.GaugeVec
 }
 
-func newGateMetrics(scraper StatisticScraper) *GateMetrics {
+type treeMetricsCollector struct {
+	treeStatScraper TreeStatisticScraper
+	nodeHealth      *prometheus.GaugeVec
+	nodeRequests    *prometheus.GaugeVec
+	nodeErrors      *prometheus.GaugeVec
+}
+
+func newGateMetrics(scraper StatisticScraper, treeScraper TreeStatisticScraper) *GateMetrics {
 	stateMetric := newStateMetrics()
 	stateMetric.register()
 
 	poolMetric := newPoolMetricsCollector(scraper)
 	poolMetric.register()
 
+	treeMetric := newTreeMetricsCollector(treeScraper)
+	treeMetric.register()
+
 	return &GateMetrics{
 		stateMetrics:         *stateMetric,
 		poolMetricsCollector: *poolMetric,
+		treeMetricsCollector: *treeMetric,
 	}
 }
 
 func (g *GateMetrics) Unregister() {
 	g.stateMetrics.unregister()
 	prometheus.Unregister(&g.poolMetricsCollector)
+	prometheus.Unregister(&g.treeMetricsCollector)
 }
 
 func newStateMetrics() *stateMetrics {
@@ -222,6 +241,89 @@ func (m *poolMetricsCollector) updateRequestsDuration(node pool.NodeStatistic) {
 	m.requestDuration.WithLabelValues(node.Address(), methodCreateSession).Set(float64(node.AverageCreateSession().Milliseconds()))
 }
 
+func newTreeMetricsCollector(scraper TreeStatisticScraper) *treeMetricsCollector {
+	nodeHealth := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: treeSubsystem,
+			Name:      "node_health",
+			Help:      "Health of the tree service endpoint (1 is healthy, 0 is unhealthy)",
+		},
+		[]string{
+			"node",
+		},
+	)
+
+	nodeRequests := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: treeSubsystem,
+			Name:      "overall_node_requests",
+			Help:      "Total number of requests to specific tree service endpoint",
+		},
+		[]string{
+			"node",
+		},
+	)
+
+	nodeErrors := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: treeSubsystem,
+			Name:      "overall_node_errors",
+			Help:      "Total number of errors of specific tree service endpoint",
+		},
+		[]string{
+			"node",
+		},
+	)
+
+	return &treeMetricsCollector{
+		treeStatScraper: scraper,
+		nodeHealth:      nodeHealth,
+		nodeRequests:    nodeRequests,
+		nodeErrors:      nodeErrors,
+	}
+}
+
+func (m *treeMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	m.updateStatistic()
+	m.nodeHealth.Collect(ch)
+	m.nodeRequests.Collect(ch)
+	m.nodeErrors.Collect(ch)
+}
+
+func (m *treeMetricsCollector) Describe(descs chan<- *prometheus.Desc) {
+	m.nodeHealth.Describe(descs)
+	m.nodeRequests.Describe(descs)
+	m.nodeErrors.Describe(descs)
+}
+
+func (m *treeMetricsCollector) register() {
+	prometheus.MustRegister(m)
+}
+
+func (m *treeMetricsCollector) updateStatistic() {
+	m.nodeHealth.Reset()
+	m.nodeRequests.Reset()
+	m.nodeErrors.Reset()
+
+	// tree service statistics is available for grpc backend only
+	if m.treeStatScraper == nil {
+		return
+	}
+
+	for _, node := range m.treeStatScraper.Statistic() {
+		var health float64
+		if node.Healthy {
+			health = 1
+		}
+		m.nodeHealth.WithLabelValues(node.Address).Set(health)
+		m.nodeRequests.WithLabelValues(node.Address).Set(float64(node.Requests))
+		m.nodeErrors.WithLabelValues(node.Address).Set(float64(node.Errors))
+	}
+}
+
 // NewPrometheusService creates a new service for gathering prometheus metrics.
 func NewPrometheusService(v *viper.Viper, log *zap.Logger) *Service {
 	if log == nil {
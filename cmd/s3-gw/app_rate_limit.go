@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/ratelimit"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+func getRateLimitConfig(v *viper.Viper, l *zap.Logger) ratelimit.Config {
+	return ratelimit.Config{
+		Global: fetchRateLimit(v, cfgRateLimitGlobal),
+		Classes: map[string]ratelimit.Limit{
+			ratelimit.ClassRead:  fetchRateLimit(v, cfgRateLimitClass+"."+ratelimit.ClassRead),
+			ratelimit.ClassWrite: fetchRateLimit(v, cfgRateLimitClass+"."+ratelimit.ClassWrite),
+			ratelimit.ClassList:  fetchRateLimit(v, cfgRateLimitClass+"."+ratelimit.ClassList),
+		},
+		AccessKey:  fetchRateLimit(v, cfgRateLimitAccessKey),
+		AccessKeys: fetchRateLimitOverrides(v, l, cfgRateLimitAccessKeys, "id"),
+		Bucket:     fetchRateLimit(v, cfgRateLimitBucket),
+		Buckets:    fetchRateLimitOverrides(v, l, cfgRateLimitBuckets, "name"),
+	}
+}
+
+func fetchRateLimit(v *viper.Viper, key string) ratelimit.Limit {
+	return ratelimit.Limit{
+		Requests:       v.GetFloat64(key + ".requests"),
+		RequestsBurst:  v.GetInt(key + ".requests_burst"),
+		Bandwidth:      v.GetInt64(key + ".bandwidth"),
+		BandwidthBurst: v.GetInt64(key + ".bandwidth_burst"),
+	}
+}
+
+func fetchRateLimitOverrides(v *viper.Viper, l *zap.Logger, section, nameKey string) map[string]ratelimit.Limit {
+	limits := make(map[string]ratelimit.Limit)
+	for i := 0; ; i++ {
+		key := section + "." + strconv.Itoa(i)
+		name := v.GetString(key + "." + nameKey)
+		if name == "" {
+			break
+		}
+
+		if _, ok := limits[name]; ok {
+			l.Warn("skip, duplicated rate limit", zap.String("section", section), zap.String(nameKey, name))
+			continue
+		}
+
+		limits[name] = fetchRateLimit(v, key)
+	}
+
+	return limits
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// Client certificate verification modes.
+const (
+	clientAuthNone          = "none"
+	clientAuthVerifyIfGiven = "verify_if_given"
+	clientAuthRequire       = "require"
+)
+
+type (
+	serverConfig struct {
+		Address string
+		H2C     bool
+		TLS     serverTLSConfig
+	}
+
+	serverTLSConfig struct {
+		Enabled     bool
+		CertFile    string
+		KeyFile     string
+		ClientAuth  string
+		CAFiles     []string
+		ClientCerts []clientCertConfig
+	}
+
+	// clientCertConfig maps the client certificate with the subject or the public key
+	// to the access key id.
+	clientCertConfig struct {
+		Subject     string
+		PublicKey   string
+		AccessKeyID string
+	}
+
+	// server is a listener of S3 API with its own TLS settings. Settings except
+	// the address and h2c can be reloaded.
+	server struct {
+		address string
+		h2c     bool
+		cert    *certProvider
+		srv     *http.Server
+
+		mu          sync.RWMutex
+		tlsConfig   *tls.Config
+		clientCerts clientCerts
+	}
+
+	clientCerts struct {
+		subjects   map[string]string
+		publicKeys map[string]string
+	}
+)
+
+// fetchServerConfigs reads listeners from the server section. If it's empty, the only listener
+// is set by listen_address and tls sections.
+func fetchServerConfigs(v *viper.Viper) []serverConfig {
+	var res []serverConfig
+	for i := 0; ; i++ {
+		key := cfgServer + "." + strconv.Itoa(i) + "."
+		address := v.GetString(key + "address")
+		if address == "" {
+			break
+		}
+
+		res = append(res, serverConfig{
+			Address: address,
+			H2C:     v.GetBool(key + "h2c"),
+			TLS:     fetchServerTLSConfig(v, key+"tls."),
+		})
+	}
+
+	if len(res) == 0 {
+		res = append(res, serverConfig{
+			Address: v.GetString(cfgListenAddress),
+			TLS: serverTLSConfig{
+				Enabled:  v.IsSet(cfgTLSCertFile) || v.IsSet(cfgTLSKeyFile),
+				CertFile: v.GetString(cfgTLSCertFile),
+				KeyFile:  v.GetString(cfgTLSKeyFile),
+			},
+		})
+	}
+
+	return res
+}
+
+func fetchServerTLSConfig(v *viper.Viper, key string) serverTLSConfig {
+	res := serverTLSConfig{
+		CertFile:   v.GetString(key + "cert_file"),
+		KeyFile:    v.GetString(key + "key_file"),
+		ClientAuth: v.GetString(key + "client_auth"),
+		CAFiles:    v.GetStringSlice(key + "ca_files"),
+	}
+	res.Enabled = res.CertFile != "" || res.KeyFile != ""
+
+	for i := 0; ; i++ {
+		certKey := key + "client_certs." + strconv.Itoa(i) + "."
+		accessKeyID := v.GetString(certKey + "access_key_id")
+		if accessKeyID == "" {
+			break
+		}
+
+		res.ClientCerts = append(res.ClientCerts, clientCertConfig{
+			Subject:     v.GetString(certKey + "subject"),
+			PublicKey:   v.GetString(certKey + "public_key"),
+			AccessKeyID: accessKeyID,
+		})
+	}
+
+	return res
+}
+
+// tlsEnabled checks if at least one listener uses TLS.
+func tlsEnabled(v *viper.Viper) bool {
+	for _, cfg := range fetchServerConfigs(v) {
+		if cfg.TLS.Enabled {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *App) initServers() {
+	for _, cfg := range fetchServerConfigs(a.cfg) {
+		s, err := newServer(cfg)
+		if err != nil {
+			a.log.Fatal("failed to init server", zap.String("address", cfg.Address), zap.Error(err))
+		}
+		a.servers = append(a.servers, s)
+	}
+}
+
+// updateServers reloads TLS settings of the listeners. Listeners can't be added or removed
+// without restart.
+func (a *App) updateServers() {
+	cfgs := make(map[string]serverConfig)
+	for _, cfg := range fetchServerConfigs(a.cfg) {
+		cfgs[cfg.Address] = cfg
+	}
+	if len(cfgs) != len(a.servers) {
+		a.log.Warn("listeners can't be added or removed without restart")
+	}
+
+	for _, s := range a.servers {
+		cfg, ok := cfgs[s.address]
+		if !ok {
+			continue
+		}
+		if cfg.TLS.Enabled != s.cert.Enabled {
+			a.log.Warn("TLS can't be enabled or disabled without restart", zap.String("address", s.address))
+			continue
+		}
+		if err := s.update(cfg.TLS); err != nil {
+			a.log.Warn("failed to reload TLS settings", zap.String("address", s.address), zap.Error(err))
+		}
+	}
+}
+
+func newServer(cfg serverConfig) (*server, error) {
+	s := &server{
+		address: cfg.Address,
+		h2c:     cfg.H2C && !cfg.TLS.Enabled,
+		cert:    &certProvider{Enabled: cfg.TLS.Enabled},
+	}
+
+	if err := s.update(cfg.TLS); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// update replaces the certificate, client verification settings and client certificates mapping.
+// Settings aren't changed if any of them is invalid.
+func (s *server) update(cfg serverTLSConfig) error {
+	if !s.cert.Enabled {
+		return nil
+	}
+
+	clientAuth, err := parseClientAuth(cfg.ClientAuth)
+	if err != nil {
+		return err
+	}
+
+	var clientCAs *x509.CertPool
+	if clientAuth != tls.NoClientCert {
+		if clientCAs, err = loadCertPool(cfg.CAFiles); err != nil {
+			return err
+		}
+	}
+
+	certs, err := newClientCerts(cfg.ClientCerts)
+	if err != nil {
+		return err
+	}
+
+	if err = s.cert.UpdateCert(cfg.CertFile, cfg.KeyFile); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.tlsConfig = &tls.Config{
+		GetCertificate: s.cert.GetCertificate,
+		ClientAuth:     clientAuth,
+		ClientCAs:      clientCAs,
+		NextProtos:     []string{http2.NextProtoTLS, "http/1.1"},
+	}
+	s.clientCerts = certs
+	s.mu.Unlock()
+
+	return nil
+}
+
+func parseClientAuth(mode string) (tls.ClientAuthType, error) {
+	switch mode {
+	case "", clientAuthNone:
+		return tls.NoClientCert, nil
+	case clientAuthVerifyIfGiven:
+		return tls.VerifyClientCertIfGiven, nil
+	case clientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	default:
+		return tls.NoClientCert, fmt.Errorf("unknown client auth mode '%s'", mode)
+	}
+}
+
+func loadCertPool(files []string) (*x509.CertPool, error) {
+	if len(files) == 0 {
+		return nil, fmt.Errorf("CA files to verify client certificates aren't set")
+	}
+
+	pool := x509.NewCertPool()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificates found in CA file '%s'", file)
+		}
+	}
+
+	return pool, nil
+}
+
+func newClientCerts(cfgs []clientCertConfig) (clientCerts, error) {
+	res := clientCerts{
+		subjects:   make(map[string]string),
+		publicKeys: make(map[string]string),
+	}
+
+	for _, cfg := range cfgs {
+		switch {
+		case cfg.Subject != "" && cfg.PublicKey != "":
+			return res, fmt.Errorf("both subject and public key are set for access key id '%s'", cfg.AccessKeyID)
+		case cfg.Subject != "":
+			if _, ok := res.subjects[cfg.Subject]; ok {
+				return res, fmt.Errorf("duplicated client certificate subject '%s'", cfg.Subject)
+			}
+			res.subjects[cfg.Subject] = cfg.AccessKeyID
+		case cfg.PublicKey != "":
+			publicKey := strings.ToLower(cfg.PublicKey)
+			if hash, err := hex.DecodeString(publicKey); err != nil || len(hash) != 32 {
+				return res, fmt.Errorf("invalid client certificate public key hash '%s'", cfg.PublicKey)
+			}
+			if _, ok := res.publicKeys[publicKey]; ok {
+				return res, fmt.Errorf("duplicated client certificate public key hash '%s'", cfg.PublicKey)
+			}
+			res.publicKeys[publicKey] = cfg.AccessKeyID
+		default:
+			return res, fmt.Errorf("neither subject nor public key is set for access key id '%s'", cfg.AccessKeyID)
+		}
+	}
+
+	return res, nil
+}
+
+// accessKeyID returns the access key id the client certificate is mapped to.
+func (c clientCerts) accessKeyID(cert *x509.Certificate) string {
+	if accessKeyID, ok := c.publicKeys[auth.CertPublicKeyHash(cert)]; ok {
+		return accessKeyID
+	}
+
+	return c.subjects[cert.Subject.String()]
+}
+
+func (s *server) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tlsConfig, nil
+}
+
+// handler wraps h with the client certificate mapping for TLS listeners and with h2c support
+// for plaintext ones.
+func (s *server) handler(h http.Handler) http.Handler {
+	if s.h2c {
+		return h2c.NewHandler(h, &http2.Server{})
+	}
+	if !s.cert.Enabled {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 && len(r.TLS.VerifiedChains[0]) > 0 {
+			s.mu.RLock()
+			accessKeyID := s.clientCerts.accessKeyID(r.TLS.VerifiedChains[0][0])
+			s.mu.RUnlock()
+
+			if accessKeyID != "" {
+				r = r.WithContext(auth.ContextWithCertAccessKeyID(r.Context(), accessKeyID))
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// listen opens the listener, over TLS if it's enabled. HTTP/2 is negotiated over TLS.
+func (s *server) listen(ctx context.Context) (net.Listener, error) {
+	var lic net.ListenConfig
+	ln, err := lic.Listen(ctx, "tcp", s.address)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cert.Enabled {
+		ln = tls.NewListener(ln, &tls.Config{
+			GetConfigForClient: s.getConfigForClient,
+		})
+	}
+
+	return ln, nil
+}
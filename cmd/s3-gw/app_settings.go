@@ -9,7 +9,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nspcc-dev/neofs-s3-gw/api/auth"
+	"github.com/nspcc-dev/neofs-s3-gw/api/handler"
+	"github.com/nspcc-dev/neofs-s3-gw/api/notifications"
 	"github.com/nspcc-dev/neofs-s3-gw/api/resolver"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/accesslog"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/audit"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/health"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/logsink"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/tracing"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
 	"github.com/spf13/pflag"
@@ -24,10 +33,33 @@ const (
 	defaultConnectTimeout     = 10 * time.Second
 	defaultShutdownTimeout    = 15 * time.Second
 
+	defaultShutdownAbortTimeout = 5 * time.Second
+	defaultShutdownReadyDelay   = 5 * time.Second
+
 	defaultPoolErrorThreshold uint32 = 100
 
 	defaultMaxClientsCount    = 100
 	defaultMaxClientsDeadline = time.Second * 30
+
+	treeBackendGRPC  = "grpc"
+	treeBackendLocal = "local"
+
+	defaultTreeDeleteWorkers = 16
+
+	defaultDevPath          = "s3-gw-dev"
+	defaultDevEpochDuration = time.Minute
+
+	defaultImportInterval = time.Minute
+	defaultImportMinAge   = 10 * time.Minute
+
+	defaultLabelledMetricsMaxValues = 100
+
+	defaultTracingEndpoint      = "localhost:4317"
+	defaultTracingSamplingRatio = 1.0
+	defaultTracingTimeout       = tracing.DefaultTimeout
+
+	defaultAccessLogFileMaxSize    = 100 // megabytes
+	defaultAccessLogFileMaxBackups = 5
 )
 
 const ( // Settings.
@@ -66,6 +98,8 @@ const ( // Settings.
 	cfgAccessBoxCacheSize         = "cache.accessbox.size"
 	cfgAccessControlCacheLifetime = "cache.accesscontrol.lifetime"
 	cfgAccessControlCacheSize     = "cache.accesscontrol.size"
+	cfgCacheInvalidationEnabled   = "cache.invalidation.enabled"
+	cfgCacheInvalidationSubject   = "cache.invalidation.subject"
 
 	// NATS.
 	cfgEnableNATS             = "nats.enabled"
@@ -85,19 +119,110 @@ const ( // Settings.
 	cfgMaxClientsCount    = "max_clients_count"
 	cfgMaxClientsDeadline = "max_clients_deadline"
 
+	// Rate limits.
+	cfgRateLimitGlobal     = "rate_limit.global"
+	cfgRateLimitClass      = "rate_limit.class"
+	cfgRateLimitAccessKey  = "rate_limit.access_key"
+	cfgRateLimitAccessKeys = "rate_limit.access_keys"
+	cfgRateLimitBucket     = "rate_limit.bucket"
+	cfgRateLimitBuckets    = "rate_limit.buckets"
+
+	// Request size limits.
+	cfgLimitsMaxBodySize      = "limits.max_body_size"
+	cfgLimitsMaxPolicySize    = "limits.max_policy_size"
+	cfgLimitsMaxPutSize       = "limits.max_put_size"
+	cfgLimitsMaxMetadataSize  = "limits.max_metadata_size"
+	cfgLimitsMaxDeleteObjects = "limits.max_delete_objects"
+	cfgLimitsMaxParts         = "limits.max_parts"
+	cfgLimitsMaxFormMemory    = "limits.max_form_memory"
+
 	// Metrics / Profiler / Web.
-	cfgPrometheusEnabled = "prometheus.enabled"
-	cfgPrometheusAddress = "prometheus.address"
-	cfgPProfEnabled      = "pprof.enabled"
-	cfgPProfAddress      = "pprof.address"
+	cfgPrometheusEnabled          = "prometheus.enabled"
+	cfgPrometheusAddress          = "prometheus.address"
+	cfgPrometheusBucketsEnabled   = "prometheus.buckets.enabled"
+	cfgPrometheusBucketsAllowed   = "prometheus.buckets.allowed"
+	cfgPrometheusBucketsMaxValues = "prometheus.buckets.max"
+	cfgPrometheusUsersEnabled     = "prometheus.users.enabled"
+	cfgPrometheusUsersAllowed     = "prometheus.users.allowed"
+	cfgPrometheusUsersMaxValues   = "prometheus.users.max"
+	cfgPProfEnabled               = "pprof.enabled"
+	cfgPProfAddress               = "pprof.address"
+
+	// Admin API.
+	cfgAdminEnabled = "admin.enabled"
+	cfgAdminAddress = "admin.address"
+	cfgAdminToken   = "admin.token"
+
+	// Health probes.
+	cfgHealthEnabled = "health.enabled"
+	cfgHealthAddress = "health.address"
+	cfgHealthTimeout = "health.timeout"
+
+	// Shutdown.
+	cfgShutdownDrainTimeout = "shutdown.drain_timeout"
+	cfgShutdownAbortTimeout = "shutdown.abort_timeout"
+	cfgShutdownReadyDelay   = "shutdown.ready_delay"
+
+	// Tracing.
+	cfgTracingEnabled       = "tracing.enabled"
+	cfgTracingExporter      = "tracing.exporter"
+	cfgTracingEndpoint      = "tracing.endpoint"
+	cfgTracingFile          = "tracing.file"
+	cfgTracingSamplingRatio = "tracing.sampling_ratio"
+	cfgTracingInsecure      = "tracing.insecure"
+	cfgTracingCAFile        = "tracing.ca_file"
+	cfgTracingHeaders       = "tracing.headers"
+	cfgTracingCompression   = "tracing.compression"
+	cfgTracingTimeout       = "tracing.timeout"
+
+	// Access log.
+	cfgAccessLogEnabled        = "access_log.enabled"
+	cfgAccessLogFormat         = "access_log.format"
+	cfgAccessLogSink           = "access_log.sink"
+	cfgAccessLogFilePath       = "access_log.file.path"
+	cfgAccessLogFileMaxSize    = "access_log.file.max_size"
+	cfgAccessLogFileMaxBackups = "access_log.file.max_backups"
+	cfgAccessLogSyslogAddress  = "access_log.syslog.address"
+
+	// Audit log.
+	cfgAuditEnabled             = "audit.enabled"
+	cfgAuditSink                = "audit.sink"
+	cfgAuditFilePath            = "audit.file.path"
+	cfgAuditFileMaxSize         = "audit.file.max_size"
+	cfgAuditFileMaxBackups      = "audit.file.max_backups"
+	cfgAuditSyslogAddress       = "audit.syslog.address"
+	cfgAuditBucketName          = "audit.bucket.name"
+	cfgAuditBucketPrefix        = "audit.bucket.prefix"
+	cfgAuditBucketFlushInterval = "audit.bucket.flush_interval"
 
 	cfgListenAddress = "listen_address"
 	cfgListenDomains = "listen_domains"
 
+	// Listeners.
+	cfgServer = "server"
+
 	// Peers.
 	cfgPeers = "peers"
 
+	// Development mode.
+	cfgDevEnabled       = "dev.enabled"
+	cfgDevPath          = "dev.path"
+	cfgDevEpochDuration = "dev.epoch_duration"
+
+	// Import.
+	cfgImportBuckets  = "import.buckets"
+	cfgImportInterval = "import.interval"
+	cfgImportMinAge   = "import.min_age"
+
+	// Usage.
+	cfgUsageTrackAll = "usage.track_all"
+
+	// Tree.
+	cfgTreeBackend         = "tree.backend"
 	cfgTreeServiceEndpoint = "tree.service"
+	cfgTreeLocalPath       = "tree.local.path"
+	cfgTreePeers           = "tree.peers"
+	cfgTreeDeleteWorkers   = "tree.delete_workers"
 
 	// NeoGo.
 	cfgRPCEndpoint = "rpc_endpoint"
@@ -114,11 +239,15 @@ const ( // Settings.
 	cmdConfig  = "config"
 	cmdPProf   = "pprof"
 	cmdMetrics = "metrics"
+	cmdDev     = "dev"
 
 	// Configuration of parameters of requests to NeoFS.
 	// Number of the object copies to consider PUT to NeoFS successful.
 	cfgSetCopiesNumber = "neofs.set_copies_number"
 
+	// Storage classes.
+	cfgStorageClasses = "storage_classes"
+
 	// List of allowed AccessKeyID prefixes.
 	cfgAllowedAccessKeyIDPrefixes = "allowed_access_key_id_prefixes"
 
@@ -164,6 +293,137 @@ func fetchPeers(l *zap.Logger, v *viper.Viper) []pool.NodeParam {
 	return nodes
 }
 
+func fetchTreeEndpoints(l *zap.Logger, v *viper.Viper) []neofs.TreeEndpoint {
+	var endpoints []neofs.TreeEndpoint
+	for i := 0; ; i++ {
+		key := cfgTreePeers + "." + strconv.Itoa(i) + "."
+		address := v.GetString(key + "address")
+		weight := v.GetFloat64(key + "weight")
+		priority := v.GetInt(key + "priority")
+
+		if address == "" {
+			break
+		}
+		if weight <= 0 { // unspecified or wrong
+			weight = 1
+		}
+		if priority <= 0 { // unspecified or wrong
+			priority = 1
+		}
+
+		endpoints = append(endpoints, neofs.TreeEndpoint{
+			Address:  address,
+			Priority: priority,
+			Weight:   weight,
+		})
+
+		l.Info("added tree service endpoint",
+			zap.String("address", address),
+			zap.Int("priority", priority),
+			zap.Float64("weight", weight))
+	}
+
+	if len(endpoints) == 0 {
+		if address := v.GetString(cfgTreeServiceEndpoint); address != "" {
+			endpoints = append(endpoints, neofs.TreeEndpoint{Address: address, Priority: 1, Weight: 1})
+		}
+	}
+
+	return endpoints
+}
+
+func fetchStorageClasses(l *zap.Logger, v *viper.Viper) map[string]uint32 {
+	storageClasses := make(map[string]uint32)
+	for i := 0; ; i++ {
+		key := cfgStorageClasses + "." + strconv.Itoa(i) + "."
+		name := v.GetString(key + "name")
+		copiesNumber := v.GetUint32(key + "copies_number")
+
+		if name == "" {
+			break
+		}
+
+		if _, ok := storageClasses[name]; ok {
+			l.Warn("skip, duplicated storage class", zap.String("name", name))
+			continue
+		}
+
+		storageClasses[name] = copiesNumber
+
+		l.Info("added storage class",
+			zap.String("name", name),
+			zap.Uint32("copies number", copiesNumber))
+	}
+
+	return storageClasses
+}
+
+// fetchTracingHeaders returns headers sent to the OTLP receiver, headers are configured as 'key=value' strings.
+func fetchTracingHeaders(l *zap.Logger, v *viper.Viper) map[string]string {
+	headers := make(map[string]string)
+	for _, header := range v.GetStringSlice(cfgTracingHeaders) {
+		kv := strings.SplitN(header, "=", 2)
+		key := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || key == "" {
+			l.Warn("skip, invalid tracing header, must be 'key=value'", zap.String("header", header))
+			continue
+		}
+		headers[strings.ToLower(key)] = strings.TrimSpace(kv[1])
+	}
+
+	return headers
+}
+
+// fetchLimits returns limits of request sizes. Limits can be only tightened,
+// S3 limits are used if the configured values are invalid or greater.
+func fetchLimits(l *zap.Logger, v *viper.Viper) handler.Limits {
+	limits := handler.DefaultLimits()
+
+	limits.MaxBodySize = getLimit(l, v, cfgLimitsMaxBodySize, limits.MaxBodySize)
+	limits.MaxPolicySize = getLimit(l, v, cfgLimitsMaxPolicySize, limits.MaxPolicySize)
+	limits.MaxPutSize = getLimit(l, v, cfgLimitsMaxPutSize, limits.MaxPutSize)
+	limits.MaxMetadataSize = int(getLimit(l, v, cfgLimitsMaxMetadataSize, int64(limits.MaxMetadataSize)))
+	limits.MaxDeleteObjects = int(getLimit(l, v, cfgLimitsMaxDeleteObjects, int64(limits.MaxDeleteObjects)))
+	limits.MaxParts = int(getLimit(l, v, cfgLimitsMaxParts, int64(limits.MaxParts)))
+
+	return limits
+}
+
+// fetchFormLimits returns the max size of the file uploaded with POST form and the max size of the form
+// kept in memory while it's parsed.
+func fetchFormLimits(l *zap.Logger, v *viper.Viper) (int64, int64) {
+	maxMemory := int64(auth.DefaultMaxFormMemory)
+	if v.IsSet(cfgLimitsMaxFormMemory) {
+		if val := v.GetInt64(cfgLimitsMaxFormMemory); val > 0 {
+			maxMemory = val
+		} else {
+			l.Error("invalid limit, using default value",
+				zap.String("parameter", cfgLimitsMaxFormMemory),
+				zap.Int64("value in config", val),
+				zap.Int64("default", maxMemory))
+		}
+	}
+
+	return getLimit(l, v, cfgLimitsMaxPutSize, handler.DefaultMaxPutSize), maxMemory
+}
+
+func getLimit(l *zap.Logger, v *viper.Viper, cfgEntry string, maxValue int64) int64 {
+	if !v.IsSet(cfgEntry) {
+		return maxValue
+	}
+
+	limit := v.GetInt64(cfgEntry)
+	if limit <= 0 || limit > maxValue {
+		l.Error("invalid limit, using default value",
+			zap.String("parameter", cfgEntry),
+			zap.Int64("value in config", limit),
+			zap.Int64("default", maxValue))
+		return maxValue
+	}
+
+	return limit
+}
+
 func newSettings() *viper.Viper {
 	v := viper.New()
 
@@ -180,6 +440,7 @@ func newSettings() *viper.Viper {
 
 	flags.Bool(cmdPProf, false, "enable pprof")
 	flags.Bool(cmdMetrics, false, "enable prometheus metrics")
+	flags.Bool(cmdDev, false, "run standalone gateway with in-process NeoFS and tree service for development")
 
 	help := flags.BoolP(cmdHelp, "h", false, "show help")
 	versionFlag := flags.BoolP(cmdVersion, "v", false, "show version")
@@ -216,6 +477,48 @@ func newSettings() *viper.Viper {
 
 	v.SetDefault(cfgPProfAddress, "localhost:8085")
 	v.SetDefault(cfgPrometheusAddress, "localhost:8086")
+	v.SetDefault(cfgPrometheusBucketsMaxValues, defaultLabelledMetricsMaxValues)
+	v.SetDefault(cfgPrometheusUsersMaxValues, defaultLabelledMetricsMaxValues)
+	v.SetDefault(cfgAdminAddress, "localhost:8087")
+	v.SetDefault(cfgHealthAddress, "localhost:8088")
+	v.SetDefault(cfgHealthTimeout, health.DefaultTimeout)
+	v.SetDefault(cfgShutdownDrainTimeout, defaultShutdownTimeout)
+	v.SetDefault(cfgShutdownAbortTimeout, defaultShutdownAbortTimeout)
+	v.SetDefault(cfgShutdownReadyDelay, defaultShutdownReadyDelay)
+
+	// cache:
+	v.SetDefault(cfgCacheInvalidationSubject, notifications.DefaultInvalidationSubject)
+
+	// tree:
+	v.SetDefault(cfgTreeBackend, treeBackendGRPC)
+	v.SetDefault(cfgTreeDeleteWorkers, defaultTreeDeleteWorkers)
+
+	// dev:
+	v.SetDefault(cfgDevPath, defaultDevPath)
+	v.SetDefault(cfgDevEpochDuration, defaultDevEpochDuration)
+
+	// import:
+	v.SetDefault(cfgImportInterval, defaultImportInterval)
+	v.SetDefault(cfgImportMinAge, defaultImportMinAge)
+
+	// tracing:
+	v.SetDefault(cfgTracingExporter, tracing.ExporterOTLP)
+	v.SetDefault(cfgTracingEndpoint, defaultTracingEndpoint)
+	v.SetDefault(cfgTracingSamplingRatio, defaultTracingSamplingRatio)
+	v.SetDefault(cfgTracingTimeout, defaultTracingTimeout)
+
+	// access log:
+	v.SetDefault(cfgAccessLogFormat, accesslog.FormatJSON)
+	v.SetDefault(cfgAccessLogSink, logsink.Stdout)
+	v.SetDefault(cfgAccessLogFileMaxSize, defaultAccessLogFileMaxSize)
+	v.SetDefault(cfgAccessLogFileMaxBackups, defaultAccessLogFileMaxBackups)
+
+	// audit log:
+	v.SetDefault(cfgAuditSink, logsink.Stdout)
+	v.SetDefault(cfgAuditFileMaxSize, defaultAccessLogFileMaxSize)
+	v.SetDefault(cfgAuditFileMaxBackups, defaultAccessLogFileMaxBackups)
+	v.SetDefault(cfgAuditBucketPrefix, audit.DefaultBucketPrefix)
+	v.SetDefault(cfgAuditBucketFlushInterval, audit.DefaultFlushInterval)
 
 	// Binding flags
 	if err := v.BindPFlag(cfgPProfEnabled, flags.Lookup(cmdPProf)); err != nil {
@@ -224,6 +527,9 @@ func newSettings() *viper.Viper {
 	if err := v.BindPFlag(cfgPrometheusEnabled, flags.Lookup(cmdMetrics)); err != nil {
 		panic(err)
 	}
+	if err := v.BindPFlag(cfgDevEnabled, flags.Lookup(cmdDev)); err != nil {
+		panic(err)
+	}
 
 	if err := v.BindPFlags(flags); err != nil {
 		panic(err)
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+
+	"github.com/nspcc-dev/neofs-s3-gw/internal/tracing"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/version"
+	"go.uber.org/zap"
+)
+
+// initTracing sets the tracer provider exporting spans of S3 requests if tracing is enabled.
+func (a *App) initTracing(ctx context.Context) {
+	if !a.cfg.GetBool(cfgTracingEnabled) {
+		return
+	}
+
+	cfg := tracing.Config{
+		Exporter:      a.cfg.GetString(cfgTracingExporter),
+		Endpoint:      a.cfg.GetString(cfgTracingEndpoint),
+		FilePath:      a.cfg.GetString(cfgTracingFile),
+		SamplingRatio: a.cfg.GetFloat64(cfgTracingSamplingRatio),
+		Insecure:      a.cfg.GetBool(cfgTracingInsecure),
+		CAFile:        a.cfg.GetString(cfgTracingCAFile),
+		Headers:       fetchTracingHeaders(a.log, a.cfg),
+		Compression:   a.cfg.GetString(cfgTracingCompression),
+		Timeout:       a.cfg.GetDuration(cfgTracingTimeout),
+		Service:       "neofs-s3-gw",
+		Version:       version.Version,
+	}
+	if cfg.SamplingRatio < 0 || cfg.SamplingRatio > 1 {
+		a.log.Fatal("invalid tracing sampling ratio, must be in range [0, 1]",
+			zap.Float64("ratio", cfg.SamplingRatio))
+	}
+
+	provider, err := tracing.NewProvider(ctx, cfg)
+	if err != nil {
+		a.log.Fatal("couldn't init tracing", zap.Error(err))
+	}
+
+	tracing.SetProvider(provider)
+	a.tracerProvider = provider
+
+	a.log.Info("tracing is enabled", zap.String("exporter", cfg.Exporter))
+}
+
+// shutdownTracing exports the remaining spans and stops the tracer provider.
+func (a *App) shutdownTracing() {
+	if a.tracerProvider == nil {
+		return
+	}
+
+	ctx, cancel := shutdownContext()
+	defer cancel()
+
+	if err := a.tracerProvider.Shutdown(ctx); err != nil {
+		a.log.Warn("couldn't shutdown tracer provider", zap.Error(err))
+	}
+}
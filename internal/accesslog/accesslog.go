@@ -0,0 +1,230 @@
+// Package accesslog writes records of the served S3 requests in JSON or Apache combined
+// format to the log sink.
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/internal/logsink"
+)
+
+// Formats of the access log records.
+const (
+	FormatJSON     = "json"
+	FormatCombined = "combined"
+)
+
+// ErrUnknownFormat is returned when the format of access log records isn't supported.
+var ErrUnknownFormat = errors.New("unknown access log format")
+
+// Config contains access log parameters.
+type Config struct {
+	// Enabled turns the access log on.
+	Enabled bool
+
+	// Format of the records: FormatJSON or FormatCombined.
+	Format string
+
+	// Sink the records are written to.
+	Sink logsink.Config
+}
+
+// Record describes the served S3 request.
+type Record struct {
+	Time          time.Time
+	RemoteIP      string
+	AccessKeyID   string
+	RequestID     string
+	Method        string
+	URI           string
+	Proto         string
+	API           string
+	Bucket        string
+	Object        string
+	Status        int
+	ErrorCode     string
+	BytesReceived uint64
+	BytesSent     uint64
+	Duration      time.Duration
+	Referer       string
+	UserAgent     string
+}
+
+type jsonRecord struct {
+	Time          string  `json:"time"`
+	RemoteIP      string  `json:"remote_ip"`
+	AccessKeyID   string  `json:"access_key_id,omitempty"`
+	RequestID     string  `json:"request_id"`
+	Method        string  `json:"method"`
+	URI           string  `json:"uri"`
+	Proto         string  `json:"proto"`
+	API           string  `json:"api,omitempty"`
+	Bucket        string  `json:"bucket,omitempty"`
+	Object        string  `json:"object,omitempty"`
+	Status        int     `json:"status"`
+	ErrorCode     string  `json:"error_code,omitempty"`
+	BytesReceived uint64  `json:"bytes_received"`
+	BytesSent     uint64  `json:"bytes_sent"`
+	Duration      float64 `json:"duration_seconds"`
+	Referer       string  `json:"referer,omitempty"`
+	UserAgent     string  `json:"user_agent,omitempty"`
+}
+
+// Logger writes access log records. It can be reconfigured at runtime with Apply.
+type Logger struct {
+	mu      sync.Mutex
+	enabled bool
+	format  string
+	sink    io.WriteCloser
+}
+
+// New creates Logger with the given config.
+func New(cfg Config) (*Logger, error) {
+	l := new(Logger)
+	if err := l.Apply(cfg); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Apply replaces the format and the sink of the logger. The previous sink is closed.
+func (l *Logger) Apply(cfg Config) error {
+	var sink io.WriteCloser
+	if cfg.Enabled {
+		switch cfg.Format {
+		case FormatJSON, FormatCombined:
+		default:
+			return fmt.Errorf("%w: %s", ErrUnknownFormat, cfg.Format)
+		}
+
+		var err error
+		if sink, err = logsink.Open(cfg.Sink); err != nil {
+			return fmt.Errorf("open access log sink: %w", err)
+		}
+	}
+
+	l.mu.Lock()
+	prev := l.sink
+	l.enabled = cfg.Enabled
+	l.format = cfg.Format
+	l.sink = sink
+	l.mu.Unlock()
+
+	if prev != nil {
+		return prev.Close()
+	}
+
+	return nil
+}
+
+// Enabled checks if the records are written.
+func (l *Logger) Enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enabled
+}
+
+// Log writes the record if the access log is enabled.
+func (l *Logger) Log(rec *Record) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.enabled {
+		return
+	}
+
+	var line []byte
+	if l.format == FormatCombined {
+		line = encodeCombined(rec)
+	} else {
+		line = encodeJSON(rec)
+	}
+
+	// the record can't be reported anywhere else if the sink fails
+	_, _ = l.sink.Write(line)
+}
+
+// Close closes the sink of the logger, no records are written after that.
+func (l *Logger) Close() error {
+	return l.Apply(Config{})
+}
+
+func encodeJSON(rec *Record) []byte {
+	data, _ := json.Marshal(jsonRecord{
+		Time:          rec.Time.UTC().Format(time.RFC3339Nano),
+		RemoteIP:      rec.RemoteIP,
+		AccessKeyID:   rec.AccessKeyID,
+		RequestID:     rec.RequestID,
+		Method:        rec.Method,
+		URI:           rec.URI,
+		Proto:         rec.Proto,
+		API:           rec.API,
+		Bucket:        rec.Bucket,
+		Object:        rec.Object,
+		Status:        rec.Status,
+		ErrorCode:     rec.ErrorCode,
+		BytesReceived: rec.BytesReceived,
+		BytesSent:     rec.BytesSent,
+		Duration:      rec.Duration.Seconds(),
+		Referer:       rec.Referer,
+		UserAgent:     rec.UserAgent,
+	})
+
+	return append(data, '\n')
+}
+
+// encodeCombined writes the record in Apache combined log format with the access key id as
+// the user followed by request id, API name, bucket, URL-encoded object, S3 error code,
+// received bytes and duration in milliseconds.
+func encodeCombined(rec *Record) []byte {
+	var buf bytes.Buffer
+
+	buf.WriteString(orDash(rec.RemoteIP))
+	buf.WriteString(" - ")
+	buf.WriteString(orDash(rec.AccessKeyID))
+	buf.WriteString(" [")
+	buf.WriteString(rec.Time.Format("02/Jan/2006:15:04:05 -0700"))
+	buf.WriteString("] ")
+	buf.WriteString(strconv.Quote(rec.Method + " " + rec.URI + " " + rec.Proto))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.Itoa(rec.Status))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatUint(rec.BytesSent, 10))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.Quote(orDash(rec.Referer)))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.Quote(orDash(rec.UserAgent)))
+	buf.WriteByte(' ')
+	buf.WriteString(orDash(rec.RequestID))
+	buf.WriteByte(' ')
+	buf.WriteString(orDash(rec.API))
+	buf.WriteByte(' ')
+	buf.WriteString(orDash(rec.Bucket))
+	buf.WriteByte(' ')
+	buf.WriteString(orDash(url.PathEscape(rec.Object)))
+	buf.WriteByte(' ')
+	buf.WriteString(orDash(rec.ErrorCode))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatUint(rec.BytesReceived, 10))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(rec.Duration.Milliseconds(), 10))
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,85 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/internal/logsink"
+	"github.com/stretchr/testify/require"
+)
+
+func testRecord() *Record {
+	return &Record{
+		Time:          time.Date(2022, 11, 1, 10, 20, 30, 0, time.UTC),
+		RemoteIP:      "10.0.0.1",
+		AccessKeyID:   "access0key",
+		RequestID:     "req-id",
+		Method:        "PUT",
+		URI:           "/bucket/dir/my object",
+		Proto:         "HTTP/1.1",
+		API:           "PutObject",
+		Bucket:        "bucket",
+		Object:        "dir/my object",
+		Status:        404,
+		ErrorCode:     "NoSuchBucket",
+		BytesReceived: 10,
+		BytesSent:     20,
+		Duration:      1500 * time.Millisecond,
+		UserAgent:     `agent "quoted"`,
+	}
+}
+
+func TestEncode(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		var rec map[string]interface{}
+		require.NoError(t, json.Unmarshal(encodeJSON(testRecord()), &rec))
+
+		require.Equal(t, "2022-11-01T10:20:30Z", rec["time"])
+		require.Equal(t, "access0key", rec["access_key_id"])
+		require.Equal(t, "NoSuchBucket", rec["error_code"])
+		require.Equal(t, 404.0, rec["status"])
+		require.Equal(t, 1.5, rec["duration_seconds"])
+		require.NotContains(t, rec, "referer")
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		line := string(encodeCombined(testRecord()))
+		require.Equal(t, `10.0.0.1 - access0key [01/Nov/2022:10:20:30 +0000] "PUT /bucket/dir/my object HTTP/1.1" 404 20 "-" "agent \"quoted\"" `+
+			"req-id PutObject bucket dir%2Fmy%20object NoSuchBucket 10 1500\n", line)
+
+		rec := testRecord()
+		rec.AccessKeyID, rec.Bucket, rec.Object, rec.ErrorCode = "", "", "", ""
+		line = string(encodeCombined(rec))
+		require.True(t, strings.HasPrefix(line, "10.0.0.1 - - ["))
+		require.True(t, strings.HasSuffix(line, "PutObject - - - 10 1500\n"))
+	})
+}
+
+func TestLoggerConfig(t *testing.T) {
+	_, err := New(Config{Enabled: true, Format: "xml", Sink: logsink.Config{Sink: logsink.Stdout}})
+	require.ErrorIs(t, err, ErrUnknownFormat)
+
+	_, err = New(Config{Enabled: true, Format: FormatJSON, Sink: logsink.Config{Sink: "kafka"}})
+	require.ErrorIs(t, err, logsink.ErrUnknownSink)
+
+	l, err := New(Config{})
+	require.NoError(t, err)
+	require.False(t, l.Enabled())
+	l.Log(testRecord())
+
+	path := filepath.Join(t.TempDir(), "access.log")
+	require.NoError(t, l.Apply(Config{Enabled: true, Format: FormatCombined, Sink: logsink.Config{Sink: logsink.File, FilePath: path}}))
+	require.True(t, l.Enabled())
+	l.Log(testRecord())
+
+	require.NoError(t, l.Close())
+	l.Log(testRecord())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(data), "\n"))
+}
@@ -0,0 +1,141 @@
+// Package audit writes events about changes of bucket and object security settings
+// (ACL, policy, versioning, object lock, CORS, notifications, bucket creation and deletion)
+// in JSON lines format to the log sink or to the objects of the audit bucket.
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/internal/logsink"
+	"go.uber.org/zap"
+)
+
+// SinkBucket is a sink which stores the events in the objects of the audit bucket.
+// The other sinks are described in logsink package.
+const SinkBucket = "bucket"
+
+// DefaultSyslogTag is a syslog tag of audit events.
+const DefaultSyslogTag = "neofs-s3-gw-audit"
+
+// Config contains audit log parameters.
+type Config struct {
+	// Enabled turns the audit log on.
+	Enabled bool
+
+	// Sink the events are written to. Sink.Sink can be SinkBucket in addition to logsink sinks.
+	Sink logsink.Config
+
+	// Bucket parameters of SinkBucket.
+	Bucket BucketConfig
+}
+
+// Event describes the change of bucket or object settings.
+type Event struct {
+	Time        time.Time   `json:"time"`
+	RequestID   string      `json:"request_id"`
+	Action      string      `json:"action"`
+	Bucket      string      `json:"bucket,omitempty"`
+	Object      string      `json:"object,omitempty"`
+	VersionID   string      `json:"version_id,omitempty"`
+	Owner       string      `json:"owner,omitempty"`
+	PublicKey   string      `json:"public_key,omitempty"`
+	AccessKeyID string      `json:"access_key_id,omitempty"`
+	RemoteIP    string      `json:"remote_ip,omitempty"`
+	Before      interface{} `json:"before,omitempty"`
+	After       interface{} `json:"after,omitempty"`
+}
+
+// Logger writes audit events. It can be reconfigured at runtime with Apply.
+type Logger struct {
+	log    *zap.Logger
+	writer BucketWriter
+
+	mu      sync.Mutex
+	enabled bool
+	sink    io.WriteCloser
+}
+
+// New creates Logger with the given config. The writer is used by SinkBucket only and can be nil
+// if the sink isn't used.
+func New(cfg Config, writer BucketWriter, log *zap.Logger) (*Logger, error) {
+	l := &Logger{log: log, writer: writer}
+	if err := l.Apply(cfg); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Apply replaces the sink of the logger. The previous sink is closed, so the events
+// buffered by SinkBucket are flushed.
+func (l *Logger) Apply(cfg Config) error {
+	var sink io.WriteCloser
+	if cfg.Enabled {
+		var err error
+		if sink, err = l.openSink(cfg); err != nil {
+			return fmt.Errorf("open audit log sink: %w", err)
+		}
+	}
+
+	l.mu.Lock()
+	prev := l.sink
+	l.enabled = cfg.Enabled
+	l.sink = sink
+	l.mu.Unlock()
+
+	if prev != nil {
+		return prev.Close()
+	}
+
+	return nil
+}
+
+func (l *Logger) openSink(cfg Config) (io.WriteCloser, error) {
+	if cfg.Sink.Sink == SinkBucket {
+		return newBucketSink(cfg.Bucket, l.writer, l.log)
+	}
+
+	if cfg.Sink.SyslogTag == "" {
+		cfg.Sink.SyslogTag = DefaultSyslogTag
+	}
+
+	return logsink.Open(cfg.Sink)
+}
+
+// Enabled checks if the events are written.
+func (l *Logger) Enabled() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.enabled
+}
+
+// Audit writes the event if the audit log is enabled.
+func (l *Logger) Audit(e *Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.enabled {
+		return
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		l.log.Error("couldn't encode audit event", zap.String("action", e.Action),
+			zap.String("request_id", e.RequestID), zap.Error(err))
+		return
+	}
+
+	if _, err = l.sink.Write(append(data, '\n')); err != nil {
+		l.log.Error("couldn't write audit event", zap.String("action", e.Action),
+			zap.String("request_id", e.RequestID), zap.Error(err))
+	}
+}
+
+// Close closes the sink of the logger, no events are written after that.
+func (l *Logger) Close() error {
+	return l.Apply(Config{})
+}
@@ -0,0 +1,117 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/internal/logsink"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type bucketWriterMock struct {
+	mu      sync.Mutex
+	fail    bool
+	objects map[string][]byte
+}
+
+func (w *bucketWriterMock) PutAuditObject(_ context.Context, bucket, key string, payload []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.fail {
+		return errors.New("put error")
+	}
+	if w.objects == nil {
+		w.objects = make(map[string][]byte)
+	}
+	w.objects[bucket+"/"+key] = append([]byte(nil), payload...)
+	return nil
+}
+
+func testEvent(action string) *Event {
+	return &Event{
+		Time:      time.Date(2022, 11, 1, 10, 20, 30, 0, time.UTC),
+		RequestID: "req-id",
+		Action:    action,
+		Bucket:    "bucket",
+		Owner:     "NbUgTSFvPmsRxmGeWpuuGeJUoRoi6PErcM",
+		Before:    map[string]string{"Status": "Suspended"},
+		After:     map[string]string{"Status": "Enabled"},
+	}
+}
+
+func TestLoggerFile(t *testing.T) {
+	l, err := New(Config{}, nil, zap.NewNop())
+	require.NoError(t, err)
+	require.False(t, l.Enabled())
+	l.Audit(testEvent("PutBucketVersioning"))
+
+	_, err = New(Config{Enabled: true, Sink: logsink.Config{Sink: "kafka"}}, nil, zap.NewNop())
+	require.ErrorIs(t, err, logsink.ErrUnknownSink)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	require.NoError(t, l.Apply(Config{Enabled: true, Sink: logsink.Config{Sink: logsink.File, FilePath: path}}))
+	require.True(t, l.Enabled())
+	l.Audit(testEvent("PutBucketVersioning"))
+	require.NoError(t, l.Close())
+	l.Audit(testEvent("PutBucketVersioning"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(data), "\n"))
+
+	var event map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &event))
+	require.Equal(t, "2022-11-01T10:20:30Z", event["time"])
+	require.Equal(t, "PutBucketVersioning", event["action"])
+	require.Equal(t, map[string]interface{}{"Status": "Suspended"}, event["before"])
+	require.Equal(t, map[string]interface{}{"Status": "Enabled"}, event["after"])
+	require.NotContains(t, event, "object")
+}
+
+func TestLoggerBucket(t *testing.T) {
+	cfg := Config{
+		Enabled: true,
+		Sink:    logsink.Config{Sink: SinkBucket},
+		Bucket:  BucketConfig{Name: "audit-bucket", FlushInterval: time.Hour},
+	}
+
+	_, err := New(cfg, nil, zap.NewNop())
+	require.Error(t, err)
+
+	writer := &bucketWriterMock{fail: true}
+	l, err := New(cfg, writer, zap.NewNop())
+	require.NoError(t, err)
+
+	l.Audit(testEvent("CreateBucket"))
+	l.Audit(testEvent("PutBucketPolicy"))
+
+	sink := l.sink.(*bucketSink)
+	sink.flush()
+	require.Empty(t, writer.objects)
+
+	writer.fail = false
+	require.NoError(t, l.Close())
+	require.Len(t, writer.objects, 1)
+
+	for key, payload := range writer.objects {
+		require.True(t, strings.HasPrefix(key, "audit-bucket/"+DefaultBucketPrefix+"2"), key)
+		require.True(t, strings.HasSuffix(key, "-"+sink.id+".jsonl"), key)
+
+		lines := bytes.Split(bytes.TrimSpace(payload), []byte{'\n'})
+		require.Len(t, lines, 2)
+
+		var event Event
+		require.NoError(t, json.Unmarshal(lines[1], &event))
+		require.Equal(t, "PutBucketPolicy", event.Action)
+	}
+}
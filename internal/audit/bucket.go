@@ -0,0 +1,181 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultBucketPrefix is a prefix of the audit objects keys if it isn't set in BucketConfig.
+	DefaultBucketPrefix = "audit/"
+
+	// DefaultFlushInterval is an interval of the audit objects uploading if it isn't set in BucketConfig.
+	DefaultFlushInterval = time.Minute
+
+	// flushSize is the size of the buffered events after which they are uploaded without waiting for the interval.
+	flushSize = 4 << 20
+
+	// maxBufferSize is the size of the buffered events after which new events are dropped
+	// if the audit objects can't be uploaded.
+	maxBufferSize = 64 << 20
+)
+
+// BucketWriter uploads objects to the audit bucket.
+type BucketWriter interface {
+	PutAuditObject(ctx context.Context, bucket, key string, payload []byte) error
+}
+
+// BucketConfig contains parameters of SinkBucket.
+type BucketConfig struct {
+	// Name of the audit bucket.
+	Name string
+
+	// Prefix of the audit objects keys.
+	Prefix string
+
+	// FlushInterval is an interval the buffered events are uploaded with.
+	FlushInterval time.Duration
+}
+
+// bucketSink buffers the events and periodically uploads them as a new object of the audit bucket.
+// Objects are never overwritten, each of them has a unique key
+// <prefix>YYYY/MM/DD/<upload time>-<sink id>.jsonl, so the bucket is an append-only event stream.
+type bucketSink struct {
+	cfg    BucketConfig
+	id     string
+	writer BucketWriter
+	log    *zap.Logger
+
+	mu      sync.Mutex
+	buf     []byte
+	dropped int
+
+	flushMu sync.Mutex
+	flushCh chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func newBucketSink(cfg BucketConfig, writer BucketWriter, log *zap.Logger) (*bucketSink, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("audit bucket name is empty")
+	}
+	if writer == nil {
+		return nil, errors.New("audit bucket writer is nil")
+	}
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultBucketPrefix
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+
+	s := &bucketSink{
+		cfg:     cfg,
+		id:      uuid.New().String(),
+		writer:  writer,
+		log:     log,
+		flushCh: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	go s.run()
+
+	return s, nil
+}
+
+func (s *bucketSink) run() {
+	defer close(s.stopped)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		case <-s.flushCh:
+		}
+		s.flush()
+	}
+}
+
+// Write buffers the event, it never blocks on the upload.
+func (s *bucketSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.buf)+len(p) > maxBufferSize {
+		s.dropped++
+		return 0, errors.New("audit buffer is full")
+	}
+
+	s.buf = append(s.buf, p...)
+	if len(s.buf) >= flushSize {
+		select {
+		case s.flushCh <- struct{}{}:
+		default:
+		}
+	}
+
+	return len(p), nil
+}
+
+func (s *bucketSink) flush() {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	payload, dropped := s.buf, s.dropped
+	s.buf, s.dropped = nil, 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		s.log.Error("audit events were dropped", zap.Int("count", dropped))
+	}
+	if len(payload) == 0 {
+		return
+	}
+
+	now := time.Now().UTC()
+	key := s.cfg.Prefix + now.Format("2006/01/02/20060102T150405.000000000Z") + "-" + s.id + ".jsonl"
+
+	if err := s.writer.PutAuditObject(context.Background(), s.cfg.Name, key, payload); err != nil {
+		s.log.Warn("couldn't upload audit events, they will be retried",
+			zap.String("bucket", s.cfg.Name), zap.String("key", key), zap.Error(err))
+
+		s.mu.Lock()
+		if len(payload)+len(s.buf) <= maxBufferSize {
+			s.buf = append(payload, s.buf...)
+		} else {
+			s.dropped += bytes.Count(payload, []byte{'\n'})
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	s.log.Debug("audit events are uploaded", zap.String("bucket", s.cfg.Name), zap.String("key", key))
+}
+
+// Close stops the periodic upload and uploads the buffered events.
+func (s *bucketSink) Close() error {
+	close(s.done)
+	<-s.stopped
+	s.flush()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.buf) > 0 {
+		return errors.New("couldn't upload buffered audit events")
+	}
+
+	return nil
+}
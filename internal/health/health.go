@@ -0,0 +1,153 @@
+// Package health serves liveness and readiness probes of the gateway. Readiness is
+// determined by the checks of the gateway dependencies: NeoFS nodes, tree service,
+// bucket name resolvers and NATS.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Statuses of the probes and the checks.
+const (
+	StatusOK           = "ok"
+	StatusFailed       = "failed"
+	StatusShuttingDown = "shutting_down"
+)
+
+// DefaultTimeout is a default timeout of the readiness probe checks.
+const DefaultTimeout = 5 * time.Second
+
+// Paths of the probes.
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// Check is a named check of the gateway dependency.
+type Check struct {
+	Name  string
+	Check func(context.Context) error
+}
+
+// CheckResult is a result of the check in the readiness probe response.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Response is a response of the probes.
+type Response struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Checker runs the checks of the readiness probe.
+type Checker struct {
+	checks       []Check
+	shuttingDown uint32
+}
+
+// NewChecker creates Checker with the checks.
+func NewChecker(checks []Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// SetShuttingDown makes the gateway not ready, so load balancers stop routing requests to it.
+func (c *Checker) SetShuttingDown() {
+	atomic.StoreUint32(&c.shuttingDown, 1)
+}
+
+// Ready runs all checks concurrently and reports if all of them passed. Checks which
+// aren't finished until the context is done are failed.
+func (c *Checker) Ready(ctx context.Context) (*Response, bool) {
+	if atomic.LoadUint32(&c.shuttingDown) == 1 {
+		return &Response{Status: StatusShuttingDown}, false
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results = make([]CheckResult, len(c.checks))
+	)
+	for i := range c.checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c.checks[i].Check)
+		}(i)
+	}
+	wg.Wait()
+
+	res := &Response{Status: StatusOK, Checks: make(map[string]CheckResult, len(c.checks))}
+	for i, check := range c.checks {
+		res.Checks[check.Name] = results[i]
+		if results[i].Status != StatusOK {
+			res.Status = StatusFailed
+		}
+	}
+
+	return res, res.Status == StatusOK
+}
+
+// runCheck runs the check, it's considered failed if it isn't finished within the context.
+func runCheck(ctx context.Context, check func(context.Context) error) CheckResult {
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := CheckResult{Status: StatusOK, Duration: time.Since(start).String()}
+	if err != nil {
+		res.Status = StatusFailed
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// NewHandler creates a handler of the liveness and the readiness probes. The liveness probe
+// succeeds while the process serves requests, checks of the readiness probe are made within
+// the timeout.
+func NewHandler(c *Checker, timeout time.Duration) http.Handler {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivenessPath, func(w http.ResponseWriter, _ *http.Request) {
+		writeResponse(w, http.StatusOK, &Response{Status: StatusOK})
+	})
+	mux.HandleFunc(ReadinessPath, func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		res, ready := c.Ready(ctx)
+		status := http.StatusOK
+		if !ready {
+			status = http.StatusServiceUnavailable
+		}
+		writeResponse(w, status, res)
+	})
+
+	return mux
+}
+
+func writeResponse(w http.ResponseWriter, status int, res *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(res)
+}
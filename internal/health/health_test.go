@@ -0,0 +1,70 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func probe(t *testing.T, h http.Handler, path string) (int, *Response) {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var res Response
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&res))
+	return w.Code, &res
+}
+
+func TestHandler(t *testing.T) {
+	var treeErr error
+	checker := NewChecker([]Check{
+		{Name: "neofs", Check: func(context.Context) error { return nil }},
+		{Name: "tree", Check: func(context.Context) error { return treeErr }},
+	})
+	h := NewHandler(checker, time.Second)
+
+	code, res := probe(t, h, ReadinessPath)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, res.Status)
+	require.Len(t, res.Checks, 2)
+	require.Equal(t, StatusOK, res.Checks["tree"].Status)
+
+	treeErr = errors.New("no healthy tree service endpoints")
+	code, res = probe(t, h, ReadinessPath)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusFailed, res.Status)
+	require.Equal(t, StatusOK, res.Checks["neofs"].Status)
+	require.Equal(t, StatusFailed, res.Checks["tree"].Status)
+	require.Equal(t, treeErr.Error(), res.Checks["tree"].Error)
+
+	checker.SetShuttingDown()
+	code, res = probe(t, h, ReadinessPath)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusShuttingDown, res.Status)
+
+	code, res = probe(t, h, LivenessPath)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusOK, res.Status)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	checker := NewChecker([]Check{
+		{Name: "stuck", Check: func(context.Context) error { <-block; return nil }},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	res, ready := checker.Ready(ctx)
+	require.False(t, ready)
+	require.Equal(t, context.DeadlineExceeded.Error(), res.Checks["stuck"].Error)
+}
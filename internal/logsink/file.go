@@ -0,0 +1,119 @@
+package logsink
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+)
+
+// rotatingFile is a log file which is renamed to <path>.1 when its size exceeds the limit,
+// the previously rotated files are shifted to <path>.2, <path>.3 and so on.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	f    *os.File
+	size int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+
+	f.f = file
+	f.size = info.Size()
+	return nil
+}
+
+// Write writes p to the file. If the rotation fails, p is written to the reopened original file
+// and the rotation error is returned, the rotation is retried on the next write.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	var rotateErr error
+	if f.f == nil {
+		// the file wasn't reopened after the previous rotation
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	} else if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if rotateErr = f.rotate(); rotateErr != nil && f.f == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := f.f.Write(p)
+	f.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// rotate shifts the backups and opens the new file. The original file is reopened if the shift fails.
+func (f *rotatingFile) rotate() error {
+	err := f.f.Close()
+	f.f = nil
+	if err != nil {
+		err = fmt.Errorf("close log file: %w", err)
+	} else {
+		err = f.shift()
+	}
+
+	if openErr := f.open(); openErr != nil {
+		if err != nil {
+			return fmt.Errorf("%w, reopen: %s", err, openErr)
+		}
+		return openErr
+	}
+
+	return err
+}
+
+func (f *rotatingFile) shift() error {
+	var err error
+	if f.maxBackups > 0 {
+		for i := f.maxBackups - 1; i > 0; i-- {
+			if err = os.Rename(f.backupPath(i), f.backupPath(i+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("rotate log file: %w", err)
+			}
+		}
+		err = os.Rename(f.path, f.backupPath(1))
+	} else {
+		err = os.Remove(f.path)
+	}
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("rotate log file: %w", err)
+	}
+
+	return nil
+}
+
+func (f *rotatingFile) backupPath(i int) string {
+	return f.path + "." + strconv.Itoa(i)
+}
+
+func (f *rotatingFile) Close() error {
+	if f.f == nil {
+		return nil
+	}
+	return f.f.Close()
+}
@@ -0,0 +1,72 @@
+// Package logsink opens destinations the gateway writes access and audit logs to.
+package logsink
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Supported sinks.
+const (
+	Stdout = "stdout"
+	File   = "file"
+	Syslog = "syslog"
+)
+
+// DefaultSyslogTag is a syslog tag of the records if it isn't set in Config.
+const DefaultSyslogTag = "neofs-s3-gw"
+
+// ErrUnknownSink is returned when the sink isn't supported.
+var ErrUnknownSink = errors.New("unknown log sink")
+
+// Config contains sink parameters.
+type Config struct {
+	// Sink the records are written to: Stdout, File or Syslog.
+	Sink string
+
+	// FilePath is a path to the log file of File sink.
+	FilePath string
+
+	// FileMaxSize is the size of the log file in bytes after which it's rotated. The file isn't rotated if it's zero.
+	FileMaxSize int64
+
+	// FileMaxBackups is the number of the rotated files kept.
+	FileMaxBackups int
+
+	// SyslogAddress is a path to the local syslog socket of Syslog sink. Default system socket is used if it's empty.
+	SyslogAddress string
+
+	// SyslogTag is a tag of the syslog records, DefaultSyslogTag is used if it's empty.
+	SyslogTag string
+}
+
+// Open opens the sink. Every Write call of the returned writer should contain one complete record.
+func Open(cfg Config) (io.WriteCloser, error) {
+	switch cfg.Sink {
+	case Stdout:
+		return nopCloser{os.Stdout}, nil
+	case File:
+		if cfg.FilePath == "" {
+			return nil, errors.New("log file path is empty")
+		}
+		return openRotatingFile(cfg.FilePath, cfg.FileMaxSize, cfg.FileMaxBackups)
+	case Syslog:
+		tag := cfg.SyslogTag
+		if tag == "" {
+			tag = DefaultSyslogTag
+		}
+		return openSyslog(cfg.SyslogAddress, tag)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSink, cfg.Sink)
+	}
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
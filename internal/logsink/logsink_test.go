@@ -0,0 +1,105 @@
+package logsink
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	_, err := Open(Config{Sink: "kafka"})
+	require.ErrorIs(t, err, ErrUnknownSink)
+
+	_, err = Open(Config{Sink: File})
+	require.Error(t, err)
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	f, err := openRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, line := range []string{"line1\n", "line2\n", "line3\n", "line4\n"} {
+		_, err = f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, f.Close())
+
+	for file, content := range map[string]string{
+		path:        "line4\n",
+		path + ".1": "line3\n",
+		path + ".2": "line2\n",
+	} {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, content, string(data))
+	}
+
+	_, err = os.Stat(path + ".3")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// the size of the existing file is taken into account
+	f, err = openRotatingFile(path, 10, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("line5\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "line5\n", string(data))
+}
+
+func TestRotatingFileFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	f, err := openRotatingFile(path, 10, 1)
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("line1\n"))
+	require.NoError(t, err)
+
+	// the file can't be renamed to the directory, so the original file is written
+	require.NoError(t, os.Mkdir(path+".1", 0o755))
+	n, err := f.Write([]byte("line2\n"))
+	require.Error(t, err)
+	require.Equal(t, len("line2\n"), n)
+
+	// the rotation is retried on the next write
+	require.NoError(t, os.Remove(path+".1"))
+	_, err = f.Write([]byte("line3\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	for file, content := range map[string]string{
+		path:        "line3\n",
+		path + ".1": "line1\nline2\n",
+	} {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, content, string(data))
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	addr := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w, err := Open(Config{Sink: Syslog, SyslogAddress: addr, SyslogTag: "s3-gw-test"})
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`{"request_id":"req-id"}`))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	require.Contains(t, string(buf[:n]), "s3-gw-test")
+	require.Contains(t, string(buf[:n]), `"request_id":"req-id"`)
+}
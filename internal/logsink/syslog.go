@@ -0,0 +1,33 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logsink
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+)
+
+// openSyslog connects to the local syslog socket, default system socket is used if address is empty.
+func openSyslog(address, tag string) (io.WriteCloser, error) {
+	const priority = syslog.LOG_INFO | syslog.LOG_LOCAL0
+
+	if address == "" {
+		w, err := syslog.New(priority, tag)
+		if err != nil {
+			return nil, fmt.Errorf("connect to syslog: %w", err)
+		}
+		return w, nil
+	}
+
+	w, err := syslog.Dial("unixgram", address, priority, tag)
+	if err != nil {
+		// syslog daemons may listen on stream socket
+		if w, err = syslog.Dial("unix", address, priority, tag); err != nil {
+			return nil, fmt.Errorf("connect to syslog '%s': %w", address, err)
+		}
+	}
+
+	return w, nil
+}
@@ -0,0 +1,13 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package logsink
+
+import (
+	"errors"
+	"io"
+)
+
+func openSyslog(string, string) (io.WriteCloser, error) {
+	return nil, errors.New("syslog isn't supported on this platform")
+}
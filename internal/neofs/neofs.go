@@ -8,12 +8,16 @@ import (
 	"io"
 	"math"
 	"strconv"
+	"sync"
 	"time"
 
 	objectv2 "github.com/nspcc-dev/neofs-api-go/v2/object"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
 	"github.com/nspcc-dev/neofs-s3-gw/authmate"
 	"github.com/nspcc-dev/neofs-s3-gw/creds/tokens"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/tracing"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
 	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
 	"github.com/nspcc-dev/neofs-sdk-go/container"
 	"github.com/nspcc-dev/neofs-sdk-go/container/acl"
@@ -24,13 +28,14 @@ import (
 	"github.com/nspcc-dev/neofs-sdk-go/pool"
 	"github.com/nspcc-dev/neofs-sdk-go/session"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // NeoFS represents virtual connection to the NeoFS network.
 // It is used to provide an interface to dependent packages
 // which work with NeoFS.
 type NeoFS struct {
-	pool  *pool.Pool
+	conns *ConnPool
 	await pool.WaitParams
 }
 
@@ -39,18 +44,61 @@ const (
 	defaultPollTimeout  = 120 * time.Second // same as default value from pool
 )
 
-// NewNeoFS creates new NeoFS using provided pool.Pool.
-func NewNeoFS(p *pool.Pool) *NeoFS {
+// ConnPool holds the connection pool which can be replaced at runtime, e.g. when
+// the list of peers is changed. Requests in progress keep using the previous pool.
+type ConnPool struct {
+	mu   sync.RWMutex
+	pool *pool.Pool
+}
+
+// NewConnPool creates new ConnPool using provided pool.Pool.
+func NewConnPool(p *pool.Pool) *ConnPool {
+	return &ConnPool{pool: p}
+}
+
+// Pool returns the current connection pool.
+func (x *ConnPool) Pool() *pool.Pool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.pool
+}
+
+// Replace sets the new connection pool and returns the previous one.
+func (x *ConnPool) Replace(p *pool.Pool) *pool.Pool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	prev := x.pool
+	x.pool = p
+	return prev
+}
+
+// Healthcheck requests network info from one of the healthy nodes of the current pool.
+// It fails without requests if all nodes are unhealthy.
+func (x *ConnPool) Healthcheck(ctx context.Context) error {
+	if _, err := x.Pool().NetworkInfo(ctx); err != nil {
+		return fmt.Errorf("network info: %w", err)
+	}
+
+	return nil
+}
+
+// NewNeoFS creates new NeoFS using provided ConnPool.
+func NewNeoFS(p *ConnPool) *NeoFS {
 	var await pool.WaitParams
 	await.SetPollInterval(defaultPollInterval)
 	await.SetTimeout(defaultPollTimeout)
 
 	return &NeoFS{
-		pool:  p,
+		conns: p,
 		await: await,
 	}
 }
 
+func (x *NeoFS) pool() *pool.Pool {
+	return x.conns.Pool()
+}
+
 // TimeToEpoch implements neofs.NeoFS interface method.
 func (x *NeoFS) TimeToEpoch(ctx context.Context, futureTime time.Time) (uint64, uint64, error) {
 	now := time.Now()
@@ -60,7 +108,9 @@ func (x *NeoFS) TimeToEpoch(ctx context.Context, futureTime time.Time) (uint64,
 			futureTime.Format(time.RFC3339), now.Format(time.RFC3339))
 	}
 
-	networkInfo, err := x.pool.NetworkInfo(ctx)
+	start := time.Now()
+	networkInfo, err := x.pool().NetworkInfo(ctx)
+	observeRequest(methodNetworkInfo, start, err)
 	if err != nil {
 		return 0, 0, fmt.Errorf("get network info via client: %w", err)
 	}
@@ -93,7 +143,9 @@ func (x *NeoFS) Container(ctx context.Context, idCnr cid.ID) (*container.Contain
 	var prm pool.PrmContainerGet
 	prm.SetContainerID(idCnr)
 
-	res, err := x.pool.GetContainer(ctx, prm)
+	start := time.Now()
+	res, err := x.pool().GetContainer(ctx, prm)
+	observeRequest(methodGetContainer, start, err)
 	if err != nil {
 		return nil, fmt.Errorf("read container via connection pool: %w", err)
 	}
@@ -130,7 +182,7 @@ func (x *NeoFS) CreateContainer(ctx context.Context, prm layer.PrmContainerCreat
 		cnr.SetAttribute(prm.AdditionalAttributes[i][0], prm.AdditionalAttributes[i][1])
 	}
 
-	err := pool.SyncContainerWithNetwork(ctx, &cnr, x.pool)
+	err := pool.SyncContainerWithNetwork(ctx, &cnr, x.pool())
 	if err != nil {
 		return cid.ID{}, fmt.Errorf("sync container with the network state: %w", err)
 	}
@@ -144,7 +196,9 @@ func (x *NeoFS) CreateContainer(ctx context.Context, prm layer.PrmContainerCreat
 	}
 
 	// send request to save the container
-	idCnr, err := x.pool.PutContainer(ctx, prmPut)
+	start := time.Now()
+	idCnr, err := x.pool().PutContainer(ctx, prmPut)
+	observeRequest(methodPutContainer, start, err)
 	if err != nil {
 		return cid.ID{}, fmt.Errorf("save container via connection pool: %w", err)
 	}
@@ -157,7 +211,9 @@ func (x *NeoFS) UserContainers(ctx context.Context, id user.ID) ([]cid.ID, error
 	var prm pool.PrmContainerList
 	prm.SetOwnerID(id)
 
-	r, err := x.pool.ListContainers(ctx, prm)
+	start := time.Now()
+	r, err := x.pool().ListContainers(ctx, prm)
+	observeRequest(methodListContainer, start, err)
 	if err != nil {
 		return nil, fmt.Errorf("list user containers via connection pool: %w", err)
 	}
@@ -175,7 +231,9 @@ func (x *NeoFS) SetContainerEACL(ctx context.Context, table eacl.Table, sessionT
 		prm.WithinSession(*sessionToken)
 	}
 
-	err := x.pool.SetEACL(ctx, prm)
+	start := time.Now()
+	err := x.pool().SetEACL(ctx, prm)
+	observeRequest(methodSetContainerEACL, start, err)
 	if err != nil {
 		return fmt.Errorf("save eACL via connection pool: %w", err)
 	}
@@ -188,7 +246,9 @@ func (x *NeoFS) ContainerEACL(ctx context.Context, id cid.ID) (*eacl.Table, erro
 	var prm pool.PrmContainerEACL
 	prm.SetContainerID(id)
 
-	res, err := x.pool.GetEACL(ctx, prm)
+	start := time.Now()
+	res, err := x.pool().GetEACL(ctx, prm)
+	observeRequest(methodGetContainerEACL, start, err)
 	if err != nil {
 		return nil, fmt.Errorf("read eACL via connection pool: %w", err)
 	}
@@ -206,7 +266,9 @@ func (x *NeoFS) DeleteContainer(ctx context.Context, id cid.ID, token *session.C
 		prm.SetSessionToken(*token)
 	}
 
-	err := x.pool.DeleteContainer(ctx, prm)
+	start := time.Now()
+	err := x.pool().DeleteContainer(ctx, prm)
+	observeRequest(methodDeleteContainer, start, err)
 	if err != nil {
 		return fmt.Errorf("delete container via connection pool: %w", err)
 	}
@@ -215,7 +277,13 @@ func (x *NeoFS) DeleteContainer(ctx context.Context, id cid.ID, token *session.C
 }
 
 // CreateObject implements neofs.NeoFS interface method.
-func (x *NeoFS) CreateObject(ctx context.Context, prm layer.PrmObjectCreate) (oid.ID, error) {
+func (x *NeoFS) CreateObject(ctx context.Context, prm layer.PrmObjectCreate) (_ oid.ID, err error) {
+	ctx, span := tracing.StartSpan(ctx, "neofs.CreateObject",
+		tracing.AttrContainer.String(prm.Container.EncodeToString()),
+		tracing.AttrObject.String(prm.Filepath),
+		tracing.AttrSize.Int64(int64(prm.PayloadSize)))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	attrNum := len(prm.Attributes) + 1 // + creation time
 
 	if prm.Filepath != "" {
@@ -267,7 +335,9 @@ func (x *NeoFS) CreateObject(ctx context.Context, prm layer.PrmObjectCreate) (oi
 		prmPut.UseKey(prm.PrivateKey)
 	}
 
-	idObj, err := x.pool.PutObject(ctx, prmPut)
+	start := time.Now()
+	idObj, err := x.pool().PutObject(ctx, prmPut)
+	observeRequest(methodPutObject, start, err)
 	if err != nil {
 		reason, ok := isErrAccessDenied(err)
 		if ok {
@@ -297,7 +367,15 @@ func (x payloadReader) Read(p []byte) (int, error) {
 }
 
 // ReadObject implements neofs.NeoFS interface method.
-func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer.ObjectPart, error) {
+func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (_ *layer.ObjectPart, err error) {
+	ctx, span := tracing.StartSpan(ctx, "neofs.ReadObject",
+		tracing.AttrContainer.String(prm.Container.EncodeToString()),
+		tracing.AttrObjectID.String(prm.Object.EncodeToString()),
+		attribute.Bool("neofs.with_header", prm.WithHeader),
+		attribute.Bool("neofs.with_payload", prm.WithPayload),
+		attribute.Int64Slice("neofs.payload_range", []int64{int64(prm.PayloadRange[0]), int64(prm.PayloadRange[1])}))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	var addr oid.Address
 	addr.SetContainer(prm.Container)
 	addr.SetObject(prm.Object)
@@ -311,9 +389,12 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 		prmGet.UseKey(prm.PrivateKey)
 	}
 
+	start := time.Now()
+
 	if prm.WithHeader {
 		if prm.WithPayload {
-			res, err := x.pool.GetObject(ctx, prmGet)
+			res, err := x.pool().GetObject(ctx, prmGet)
+			observeRequest(methodGetObject, start, err)
 			if err != nil {
 				if reason, ok := isErrAccessDenied(err); ok {
 					return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
@@ -324,7 +405,7 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 
 			defer res.Payload.Close()
 
-			payload, err := io.ReadAll(res.Payload)
+			payload, err := io.ReadAll(newFirstByteReader(res.Payload, methodGetObject, start))
 			if err != nil {
 				return nil, fmt.Errorf("read full object payload: %w", err)
 			}
@@ -345,7 +426,8 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 			prmHead.UseKey(prm.PrivateKey)
 		}
 
-		hdr, err := x.pool.HeadObject(ctx, prmHead)
+		hdr, err := x.pool().HeadObject(ctx, prmHead)
+		observeRequest(methodHeadObject, start, err)
 		if err != nil {
 			if reason, ok := isErrAccessDenied(err); ok {
 				return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
@@ -358,7 +440,8 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 			Head: &hdr,
 		}, nil
 	} else if prm.PayloadRange[0]+prm.PayloadRange[1] == 0 {
-		res, err := x.pool.GetObject(ctx, prmGet)
+		res, err := x.pool().GetObject(ctx, prmGet)
+		observeRequest(methodGetObject, start, err)
 		if err != nil {
 			if reason, ok := isErrAccessDenied(err); ok {
 				return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
@@ -368,7 +451,7 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 		}
 
 		return &layer.ObjectPart{
-			Payload: res.Payload,
+			Payload: newFirstByteReader(res.Payload, methodGetObject, start),
 		}, nil
 	}
 
@@ -383,7 +466,8 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 		prmRange.UseKey(prm.PrivateKey)
 	}
 
-	res, err := x.pool.ObjectRange(ctx, prmRange)
+	res, err := x.pool().ObjectRange(ctx, prmRange)
+	observeRequest(methodRangeObject, start, err)
 	if err != nil {
 		if reason, ok := isErrAccessDenied(err); ok {
 			return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
@@ -393,12 +477,17 @@ func (x *NeoFS) ReadObject(ctx context.Context, prm layer.PrmObjectRead) (*layer
 	}
 
 	return &layer.ObjectPart{
-		Payload: payloadReader{&res},
+		Payload: payloadReader{newFirstByteReader(&res, methodRangeObject, start)},
 	}, nil
 }
 
 // DeleteObject implements neofs.NeoFS interface method.
-func (x *NeoFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) error {
+func (x *NeoFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "neofs.DeleteObject",
+		tracing.AttrContainer.String(prm.Container.EncodeToString()),
+		tracing.AttrObjectID.String(prm.Object.EncodeToString()))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	var addr oid.Address
 	addr.SetContainer(prm.Container)
 	addr.SetObject(prm.Object)
@@ -412,7 +501,9 @@ func (x *NeoFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) err
 		prmDelete.UseKey(prm.PrivateKey)
 	}
 
-	err := x.pool.DeleteObject(ctx, prmDelete)
+	start := time.Now()
+	err = x.pool().DeleteObject(ctx, prmDelete)
+	observeRequest(methodDeleteObject, start, err)
 	if err != nil {
 		if reason, ok := isErrAccessDenied(err); ok {
 			return fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
@@ -424,6 +515,115 @@ func (x *NeoFS) DeleteObject(ctx context.Context, prm layer.PrmObjectDelete) err
 	return nil
 }
 
+// SearchObjects implements neofs.NeoFS interface method.
+func (x *NeoFS) SearchObjects(ctx context.Context, prm layer.PrmObjectSearch) (_ []oid.ID, err error) {
+	ctx, span := tracing.StartSpan(ctx, "neofs.SearchObjects",
+		tracing.AttrContainer.String(prm.Container.EncodeToString()))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	filters := object.NewSearchFilters()
+	filters.AddRootFilter()
+	if prm.WithAttribute != "" {
+		filters.AddFilter(prm.WithAttribute, "", object.MatchCommonPrefix)
+	}
+	if prm.CreationEpoch != 0 {
+		filters.AddFilter(objectv2.FilterHeaderCreationEpoch, strconv.FormatUint(prm.CreationEpoch, 10), object.MatchStringEqual)
+	}
+
+	var prmSearch pool.PrmObjectSearch
+	prmSearch.SetContainerID(prm.Container)
+	prmSearch.SetFilters(filters)
+
+	if prm.BearerToken != nil {
+		prmSearch.UseBearer(*prm.BearerToken)
+	} else {
+		prmSearch.UseKey(prm.PrivateKey)
+	}
+
+	start := time.Now()
+	res, err := x.pool().SearchObjects(ctx, prmSearch)
+	if err != nil {
+		observeRequest(methodSearchObject, start, err)
+		if reason, ok := isErrAccessDenied(err); ok {
+			return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
+		}
+
+		return nil, fmt.Errorf("init object search via connection pool: %w", err)
+	}
+
+	var ids []oid.ID
+	err = res.Iterate(func(id oid.ID) bool {
+		ids = append(ids, id)
+		return false
+	})
+	observeRequest(methodSearchObject, start, err)
+	if err != nil {
+		if reason, ok := isErrAccessDenied(err); ok {
+			return nil, fmt.Errorf("%w: %s", layer.ErrAccessDenied, reason)
+		}
+
+		return nil, fmt.Errorf("read object search result: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Methods of NeoFS requests reported in metrics.
+const (
+	methodNetworkInfo      = "network_info"
+	methodGetContainer     = "get_container"
+	methodPutContainer     = "put_container"
+	methodListContainer    = "list_container"
+	methodDeleteContainer  = "delete_container"
+	methodGetContainerEACL = "get_container_eacl"
+	methodSetContainerEACL = "set_container_eacl"
+	methodPutObject        = "put_object"
+	methodGetObject        = "get_object"
+	methodHeadObject       = "head_object"
+	methodRangeObject      = "range_object"
+	methodDeleteObject     = "delete_object"
+	methodSearchObject     = "search_object"
+)
+
+// observeRequest records metrics of the NeoFS request started at the given time.
+// Access denial and missing objects or containers are responses of the storage
+// nodes, so they aren't counted as failed requests.
+func observeRequest(method string, start time.Time, err error) {
+	failed := err != nil && !isErrNotFound(err)
+	if failed {
+		_, denied := isErrAccessDenied(err)
+		failed = !denied
+	}
+
+	metrics.ObserveNeoFSRequest(method, time.Since(start), failed)
+}
+
+func isErrNotFound(err error) bool {
+	return client.IsErrObjectNotFound(err) || client.IsErrObjectAlreadyRemoved(err) ||
+		client.IsErrContainerNotFound(err) || client.IsErrEACLNotFound(err)
+}
+
+// firstByteReader wraps the payload reader and reports the time to the first byte of the payload.
+type firstByteReader struct {
+	io.ReadCloser
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+func newFirstByteReader(r io.ReadCloser, method string, start time.Time) *firstByteReader {
+	return &firstByteReader{ReadCloser: r, method: method, start: start}
+}
+
+func (x *firstByteReader) Read(p []byte) (int, error) {
+	n, err := x.ReadCloser.Read(p)
+	if n > 0 {
+		x.once.Do(func() { metrics.ObserveNeoFSFirstByte(x.method, time.Since(x.start)) })
+	}
+
+	return n, err
+}
+
 func isErrAccessDenied(err error) (string, bool) {
 	unwrappedErr := errors.Unwrap(err)
 	for unwrappedErr != nil {
@@ -444,17 +644,17 @@ func isErrAccessDenied(err error) (string, bool) {
 // ResolverNeoFS represents virtual connection to the NeoFS network.
 // It implements resolver.NeoFS.
 type ResolverNeoFS struct {
-	pool *pool.Pool
+	conns *ConnPool
 }
 
-// NewResolverNeoFS creates new ResolverNeoFS using provided pool.Pool.
-func NewResolverNeoFS(p *pool.Pool) *ResolverNeoFS {
-	return &ResolverNeoFS{pool: p}
+// NewResolverNeoFS creates new ResolverNeoFS using provided ConnPool.
+func NewResolverNeoFS(p *ConnPool) *ResolverNeoFS {
+	return &ResolverNeoFS{conns: p}
 }
 
 // SystemDNS implements resolver.NeoFS interface method.
 func (x *ResolverNeoFS) SystemDNS(ctx context.Context) (string, error) {
-	networkInfo, err := x.pool.NetworkInfo(ctx)
+	networkInfo, err := x.conns.Pool().NetworkInfo(ctx)
 	if err != nil {
 		return "", fmt.Errorf("read network info via client: %w", err)
 	}
@@ -469,12 +669,17 @@ func (x *ResolverNeoFS) SystemDNS(ctx context.Context) (string, error) {
 
 // AuthmateNeoFS is a mediator which implements authmate.NeoFS through pool.Pool.
 type AuthmateNeoFS struct {
-	neoFS *NeoFS
+	neoFS layer.NeoFS
 }
 
 // NewAuthmateNeoFS creates new AuthmateNeoFS using provided pool.Pool.
 func NewAuthmateNeoFS(p *pool.Pool) *AuthmateNeoFS {
-	return &AuthmateNeoFS{neoFS: NewNeoFS(p)}
+	return NewAuthmateNeoFSFrom(NewNeoFS(NewConnPool(p)))
+}
+
+// NewAuthmateNeoFSFrom creates new AuthmateNeoFS using provided layer.NeoFS, e.g. LocalNeoFS.
+func NewAuthmateNeoFSFrom(neoFS layer.NeoFS) *AuthmateNeoFS {
+	return &AuthmateNeoFS{neoFS: neoFS}
 }
 
 // ContainerExists implements authmate.NeoFS interface method.
@@ -536,15 +741,15 @@ func (x *AuthmateNeoFS) CreateObject(ctx context.Context, prm tokens.PrmObjectCr
 
 // PoolStatistic is a mediator which implements authmate.NeoFS through pool.Pool.
 type PoolStatistic struct {
-	pool *pool.Pool
+	conns *ConnPool
 }
 
-// NewPoolStatistic creates new PoolStatistic using provided pool.Pool.
-func NewPoolStatistic(p *pool.Pool) *PoolStatistic {
-	return &PoolStatistic{pool: p}
+// NewPoolStatistic creates new PoolStatistic using provided ConnPool.
+func NewPoolStatistic(p *ConnPool) *PoolStatistic {
+	return &PoolStatistic{conns: p}
 }
 
 // Statistic implements interface method.
 func (x *PoolStatistic) Statistic() pool.Statistic {
-	return x.pool.Statistic()
+	return x.conns.Pool().Statistic()
 }
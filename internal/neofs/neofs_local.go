@@ -0,0 +1,978 @@
+package neofs
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	v2acl "github.com/nspcc-dev/neofs-api-go/v2/acl"
+	objectv2 "github.com/nspcc-dev/neofs-api-go/v2/object"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"github.com/nspcc-dev/neofs-sdk-go/checksum"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
+	"github.com/nspcc-dev/neofs-sdk-go/container"
+	"github.com/nspcc-dev/neofs-sdk-go/container/acl"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	neofsecdsa "github.com/nspcc-dev/neofs-sdk-go/crypto/ecdsa"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/object"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/pool"
+	"github.com/nspcc-dev/neofs-sdk-go/session"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/nspcc-dev/neofs-sdk-go/version"
+)
+
+// LocalNeoFS is an in-process NeoFS stand-in storing containers and objects
+// in the local file system. It implements layer.NeoFS and resolver.NeoFS
+// and follows NeoFS semantics: basic ACL, eACL and bearer token rules,
+// container session tokens, epochs, object expiration and locks.
+//
+// Requests without bearer token and private key are signed with the key
+// LocalNeoFS was created with, like pool.Pool does.
+type LocalNeoFS struct {
+	root          string
+	key           *keys.PrivateKey
+	genesis       time.Time
+	epochDuration time.Duration
+
+	mu *sync.RWMutex
+}
+
+const (
+	localContainersDir = "containers"
+	localObjectsDir    = "objects"
+	localLocksDir      = "locks"
+	localTmpDir        = "tmp"
+	localGenesisFile   = "genesis"
+	localContainerFile = "container"
+	localEACLFile      = "eacl"
+	localPayloadSuffix = ".payload"
+
+	// LocalSystemDNS is a system DNS zone reported by LocalNeoFS.
+	LocalSystemDNS = "container"
+)
+
+// NewLocalNeoFS opens (or creates) LocalNeoFS storage in the provided directory.
+// Epochs are counted from the storage creation time and last for epochDuration.
+func NewLocalNeoFS(root string, key *keys.PrivateKey, epochDuration time.Duration) (*LocalNeoFS, error) {
+	if epochDuration <= 0 {
+		return nil, errors.New("epoch duration must be positive")
+	}
+
+	for _, dir := range []string{localContainersDir, localTmpDir} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0700); err != nil {
+			return nil, fmt.Errorf("create storage directory: %w", err)
+		}
+	}
+
+	genesis, err := readGenesis(filepath.Join(root, localGenesisFile))
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalNeoFS{
+		root:          root,
+		key:           key,
+		genesis:       genesis,
+		epochDuration: epochDuration,
+		mu:            new(sync.RWMutex),
+	}, nil
+}
+
+func readGenesis(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		now := time.Now()
+		if err = writeFileAtomic(path, []byte(strconv.FormatInt(now.UnixNano(), 10))); err != nil {
+			return time.Time{}, fmt.Errorf("save genesis time: %w", err)
+		}
+		return now, nil
+	} else if err != nil {
+		return time.Time{}, fmt.Errorf("read genesis time: %w", err)
+	}
+
+	nanos, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid genesis time: %w", err)
+	}
+	return time.Unix(0, nanos), nil
+}
+
+// WithKey returns LocalNeoFS sharing the same storage but signing requests
+// without bearer token and private key with the provided key.
+func (x *LocalNeoFS) WithKey(key *keys.PrivateKey) *LocalNeoFS {
+	res := *x
+	res.key = key
+	return &res
+}
+
+// CurrentEpoch returns the current epoch of LocalNeoFS.
+func (x *LocalNeoFS) CurrentEpoch() uint64 {
+	return uint64(time.Since(x.genesis)/x.epochDuration) + 1
+}
+
+// TimeToEpoch implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) TimeToEpoch(_ context.Context, futureTime time.Time) (uint64, uint64, error) {
+	now := time.Now()
+	dur := futureTime.Sub(now)
+	if dur < 0 {
+		return 0, 0, fmt.Errorf("time '%s' must be in the future (after %s)",
+			futureTime.Format(time.RFC3339), now.Format(time.RFC3339))
+	}
+
+	curr := x.CurrentEpoch()
+	msPerEpoch := uint64(x.epochDuration.Milliseconds())
+	if msPerEpoch == 0 {
+		msPerEpoch = 1
+	}
+
+	epochLifetime := uint64(dur.Milliseconds()) / msPerEpoch
+	if uint64(dur.Milliseconds())%msPerEpoch != 0 {
+		epochLifetime++
+	}
+
+	var epoch uint64
+	if epochLifetime >= math.MaxUint64-curr {
+		epoch = math.MaxUint64
+	} else {
+		epoch = curr + epochLifetime
+	}
+
+	return curr, epoch, nil
+}
+
+// SystemDNS implements resolver.NeoFS interface method.
+func (x *LocalNeoFS) SystemDNS(context.Context) (string, error) {
+	return LocalSystemDNS, nil
+}
+
+// ResolveContainerName returns ID of the container with the provided domain name.
+func (x *LocalNeoFS) ResolveContainerName(_ context.Context, name string) (cid.ID, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var res cid.ID
+	err := x.iterateContainers(func(id cid.ID, cnr container.Container) bool {
+		if domain := container.ReadDomain(cnr); domain.Name() == name {
+			res = id
+			return true
+		}
+		return false
+	})
+	if err != nil {
+		return cid.ID{}, err
+	}
+	if res.Equals(cid.ID{}) {
+		return cid.ID{}, fmt.Errorf("container '%s' not found", name)
+	}
+
+	return res, nil
+}
+
+// Statistic returns empty statistic since LocalNeoFS has no connections.
+func (x *LocalNeoFS) Statistic() pool.Statistic {
+	return pool.Statistic{}
+}
+
+// Container implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) Container(_ context.Context, idCnr cid.ID) (*container.Container, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	cnr, err := x.readContainer(idCnr)
+	if err != nil {
+		return nil, fmt.Errorf("read container: %w", err)
+	}
+
+	return cnr, nil
+}
+
+// CreateContainer implements neofs.NeoFS interface method.
+//
+// If prm.BasicACL is zero, 'eacl-public-read-write' is used.
+func (x *LocalNeoFS) CreateContainer(_ context.Context, prm layer.PrmContainerCreate) (cid.ID, error) {
+	if prm.BasicACL == basicACLZero {
+		prm.BasicACL = acl.PublicRWExtended
+	}
+
+	if err := x.checkContainerSession(prm.SessionToken, session.VerbContainerPut, nil, prm.Creator); err != nil {
+		return cid.ID{}, err
+	}
+
+	var cnr container.Container
+	cnr.Init()
+	cnr.SetPlacementPolicy(prm.Policy)
+	cnr.SetOwner(prm.Creator)
+	cnr.SetBasicACL(prm.BasicACL)
+	container.SetCreationTime(&cnr, time.Now())
+
+	if prm.Name != "" {
+		var d container.Domain
+		d.SetName(prm.Name)
+
+		container.WriteDomain(&cnr, d)
+		container.SetName(&cnr, prm.Name)
+	}
+
+	for i := range prm.AdditionalAttributes {
+		cnr.SetAttribute(prm.AdditionalAttributes[i][0], prm.AdditionalAttributes[i][1])
+	}
+
+	data := cnr.Marshal()
+
+	// storage nodes reject invalid containers, so do it here too
+	if err := new(container.Container).Unmarshal(data); err != nil {
+		return cid.ID{}, fmt.Errorf("invalid container: %w", err)
+	}
+
+	var idCnr cid.ID
+	container.CalculateIDFromBinary(&idCnr, data)
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if prm.Name != "" {
+		var taken bool
+		err := x.iterateContainers(func(_ cid.ID, other container.Container) bool {
+			taken = container.ReadDomain(other).Name() == prm.Name
+			return taken
+		})
+		if err != nil {
+			return cid.ID{}, err
+		}
+		if taken {
+			return cid.ID{}, fmt.Errorf("container with name '%s' already exists", prm.Name)
+		}
+	}
+
+	dir := x.containerDir(idCnr)
+	for _, sub := range []string{localObjectsDir, localLocksDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return cid.ID{}, fmt.Errorf("create container directory: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(filepath.Join(dir, localContainerFile), data); err != nil {
+		return cid.ID{}, fmt.Errorf("save container: %w", err)
+	}
+
+	return idCnr, nil
+}
+
+// UserContainers implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) UserContainers(_ context.Context, id user.ID) ([]cid.ID, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var res []cid.ID
+	err := x.iterateContainers(func(idCnr cid.ID, cnr container.Container) bool {
+		if cnr.Owner().Equals(id) {
+			res = append(res, idCnr)
+		}
+		return false
+	})
+
+	return res, err
+}
+
+// SetContainerEACL implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) SetContainerEACL(_ context.Context, table eacl.Table, sessionToken *session.Container) error {
+	idCnr, ok := table.CID()
+	if !ok {
+		return errors.New("missing container ID in eACL table")
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	cnr, err := x.readContainer(idCnr)
+	if err != nil {
+		return fmt.Errorf("read container: %w", err)
+	}
+
+	if !cnr.BasicACL().Extendable() {
+		return errors.New("container's basic ACL is not extendable")
+	}
+
+	if err = x.checkContainerSession(sessionToken, session.VerbContainerSetEACL, &idCnr, cnr.Owner()); err != nil {
+		return err
+	}
+
+	data, err := table.Marshal()
+	if err != nil {
+		return fmt.Errorf("marshal eACL: %w", err)
+	}
+
+	if err = writeFileAtomic(filepath.Join(x.containerDir(idCnr), localEACLFile), data); err != nil {
+		return fmt.Errorf("save eACL: %w", err)
+	}
+
+	return nil
+}
+
+// ContainerEACL implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) ContainerEACL(_ context.Context, idCnr cid.ID) (*eacl.Table, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	if _, err := x.readContainer(idCnr); err != nil {
+		return nil, fmt.Errorf("read container: %w", err)
+	}
+
+	table, err := x.readEACL(idCnr)
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return nil, fmt.Errorf("read eACL: %w", apistatus.EACLNotFound{})
+	}
+
+	return table, nil
+}
+
+// DeleteContainer implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) DeleteContainer(_ context.Context, idCnr cid.ID, token *session.Container) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	cnr, err := x.readContainer(idCnr)
+	if err != nil {
+		return fmt.Errorf("read container: %w", err)
+	}
+
+	if err = x.checkContainerSession(token, session.VerbContainerDelete, &idCnr, cnr.Owner()); err != nil {
+		return err
+	}
+
+	if err = os.RemoveAll(x.containerDir(idCnr)); err != nil {
+		return fmt.Errorf("remove container: %w", err)
+	}
+
+	return nil
+}
+
+// CreateObject implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) CreateObject(_ context.Context, prm layer.PrmObjectCreate) (oid.ID, error) {
+	epoch := x.CurrentEpoch()
+
+	attrs := make([]object.Attribute, 0, len(prm.Attributes)+2)
+	var a *object.Attribute
+
+	a = object.NewAttribute()
+	a.SetKey(object.AttributeTimestamp)
+	a.SetValue(strconv.FormatInt(time.Now().Unix(), 10))
+	attrs = append(attrs, *a)
+
+	for i := range prm.Attributes {
+		a = object.NewAttribute()
+		a.SetKey(prm.Attributes[i][0])
+		a.SetValue(prm.Attributes[i][1])
+		attrs = append(attrs, *a)
+	}
+
+	if prm.Filepath != "" {
+		a = object.NewAttribute()
+		a.SetKey(object.AttributeFilePath)
+		a.SetValue(prm.Filepath)
+		attrs = append(attrs, *a)
+	}
+
+	ver := version.Current()
+
+	obj := object.New()
+	obj.SetVersion(&ver)
+	obj.SetContainerID(prm.Container)
+	obj.SetOwnerID(&prm.Creator)
+	obj.SetAttributes(attrs...)
+	obj.SetCreationEpoch(epoch)
+
+	payload := prm.Payload
+	if len(prm.Locks) > 0 {
+		lock := new(object.Lock)
+		lock.WriteMembers(prm.Locks)
+		objectv2.WriteLock(obj.ToV2(), (objectv2.Lock)(*lock))
+		payload = bytes.NewReader(obj.Payload())
+		obj.SetPayload(nil)
+	}
+	if payload == nil {
+		payload = bytes.NewReader(nil)
+	}
+
+	x.mu.RLock()
+	cnr, err := x.readContainer(prm.Container)
+	x.mu.RUnlock()
+	if err != nil {
+		return oid.ID{}, fmt.Errorf("read container: %w", err)
+	}
+
+	if err = x.checkObjectAccess(prm.Container, *cnr, prm.PrmAuth, acl.OpObjectPut, obj); err != nil {
+		return oid.ID{}, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(x.root, localTmpDir), "payload")
+	if err != nil {
+		return oid.ID{}, fmt.Errorf("create payload file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), payload)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return oid.ID{}, fmt.Errorf("save payload: %w", err)
+	}
+
+	var sum [sha256.Size]byte
+	copy(sum[:], hash.Sum(nil))
+
+	var cs checksum.Checksum
+	cs.SetSHA256(sum)
+	obj.SetPayloadChecksum(cs)
+	obj.SetPayloadSize(uint64(size))
+
+	if err = object.CalculateAndSetID(obj); err != nil {
+		return oid.ID{}, fmt.Errorf("calculate object ID: %w", err)
+	}
+	idObj, _ := obj.ID()
+
+	hdr, err := obj.Marshal()
+	if err != nil {
+		return oid.ID{}, fmt.Errorf("marshal object header: %w", err)
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	// container could be removed while payload was being saved
+	if _, err = x.readContainer(prm.Container); err != nil {
+		return oid.ID{}, fmt.Errorf("read container: %w", err)
+	}
+
+	path := x.objectPath(prm.Container, idObj)
+	if err = os.Rename(tmp.Name(), path+localPayloadSuffix); err != nil {
+		return oid.ID{}, fmt.Errorf("save payload: %w", err)
+	}
+	if err = writeFileAtomic(path, hdr); err != nil {
+		return oid.ID{}, fmt.Errorf("save object header: %w", err)
+	}
+
+	for _, locked := range prm.Locks {
+		dir := filepath.Join(x.containerDir(prm.Container), localLocksDir, locked.EncodeToString())
+		if err = os.MkdirAll(dir, 0700); err != nil {
+			return oid.ID{}, fmt.Errorf("save lock: %w", err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, idObj.EncodeToString()), nil, 0600); err != nil {
+			return oid.ID{}, fmt.Errorf("save lock: %w", err)
+		}
+	}
+
+	return idObj, nil
+}
+
+type localPayload struct {
+	io.Reader
+	io.Closer
+}
+
+// ReadObject implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) ReadObject(_ context.Context, prm layer.PrmObjectRead) (*layer.ObjectPart, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	cnr, err := x.readContainer(prm.Container)
+	if err != nil {
+		return nil, fmt.Errorf("read container: %w", err)
+	}
+
+	hdr, err := x.readHeader(prm.Container, prm.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	op := acl.OpObjectGet
+	if prm.WithHeader && !prm.WithPayload {
+		op = acl.OpObjectHead
+	} else if !prm.WithHeader && prm.PayloadRange[0]+prm.PayloadRange[1] > 0 {
+		op = acl.OpObjectRange
+	}
+
+	if err = x.checkObjectAccess(prm.Container, *cnr, prm.PrmAuth, op, hdr); err != nil {
+		return nil, err
+	}
+
+	if prm.WithHeader && !prm.WithPayload {
+		return &layer.ObjectPart{Head: hdr}, nil
+	}
+
+	f, err := os.Open(x.objectPath(prm.Container, prm.Object) + localPayloadSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("open payload: %w", err)
+	}
+
+	if prm.WithHeader {
+		defer f.Close()
+
+		payload, err := io.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("read full object payload: %w", err)
+		}
+		hdr.SetPayload(payload)
+
+		return &layer.ObjectPart{Head: hdr}, nil
+	}
+
+	if op != acl.OpObjectRange {
+		return &layer.ObjectPart{Payload: f}, nil
+	}
+
+	off, ln := prm.PayloadRange[0], prm.PayloadRange[1]
+	if off+ln < off || off+ln > hdr.PayloadSize() {
+		f.Close()
+		return nil, fmt.Errorf("read payload range: %w", apistatus.ObjectOutOfRange{})
+	}
+
+	return &layer.ObjectPart{
+		Payload: localPayload{
+			Reader: io.NewSectionReader(f, int64(off), int64(ln)),
+			Closer: f,
+		},
+	}, nil
+}
+
+// DeleteObject implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) DeleteObject(_ context.Context, prm layer.PrmObjectDelete) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	cnr, err := x.readContainer(prm.Container)
+	if err != nil {
+		return fmt.Errorf("read container: %w", err)
+	}
+
+	hdr, err := x.readHeader(prm.Container, prm.Object)
+	if err != nil && !client.IsErrObjectNotFound(err) {
+		return err
+	}
+
+	if err = x.checkObjectAccess(prm.Container, *cnr, prm.PrmAuth, acl.OpObjectDelete, hdr); err != nil {
+		return err
+	}
+
+	if hdr == nil {
+		return nil
+	}
+
+	locked, err := x.isLocked(prm.Container, prm.Object)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return fmt.Errorf("mark object removal: %w", apistatus.ObjectLocked{})
+	}
+
+	return x.removeObject(prm.Container, prm.Object)
+}
+
+// SearchObjects implements neofs.NeoFS interface method.
+func (x *LocalNeoFS) SearchObjects(_ context.Context, prm layer.PrmObjectSearch) ([]oid.ID, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	cnr, err := x.readContainer(prm.Container)
+	if err != nil {
+		return nil, fmt.Errorf("read container: %w", err)
+	}
+
+	if err = x.checkObjectAccess(prm.Container, *cnr, prm.PrmAuth, acl.OpObjectSearch, nil); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(x.containerDir(prm.Container), localObjectsDir))
+	if err != nil {
+		return nil, fmt.Errorf("list objects: %w", err)
+	}
+
+	var res []oid.ID
+	for _, entry := range entries {
+		var idObj oid.ID
+		if err = idObj.DecodeString(entry.Name()); err != nil {
+			continue // payload file
+		}
+
+		hdr, err := x.readHeader(prm.Container, idObj)
+		if err != nil {
+			if client.IsErrObjectNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if prm.WithAttribute != "" && !hasAttribute(hdr, prm.WithAttribute) {
+			continue
+		}
+
+		res = append(res, idObj)
+	}
+
+	return res, nil
+}
+
+func hasAttribute(obj *object.Object, key string) bool {
+	for _, attr := range obj.Attributes() {
+		if attr.Key() == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (x *LocalNeoFS) removeObject(idCnr cid.ID, idObj oid.ID) error {
+	path := x.objectPath(idCnr, idObj)
+	for _, p := range []string{path, path + localPayloadSuffix} {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove object: %w", err)
+		}
+	}
+
+	if err := os.RemoveAll(filepath.Join(x.containerDir(idCnr), localLocksDir, idObj.EncodeToString())); err != nil {
+		return fmt.Errorf("remove object locks: %w", err)
+	}
+
+	return nil
+}
+
+// isLocked checks if there is a lock object which is not expired for the object.
+func (x *LocalNeoFS) isLocked(idCnr cid.ID, idObj oid.ID) (bool, error) {
+	entries, err := os.ReadDir(filepath.Join(x.containerDir(idCnr), localLocksDir, idObj.EncodeToString()))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("read object locks: %w", err)
+	}
+
+	for _, entry := range entries {
+		var idLock oid.ID
+		if err = idLock.DecodeString(entry.Name()); err != nil {
+			continue
+		}
+
+		if _, err = x.readHeader(idCnr, idLock); err == nil {
+			return true, nil
+		} else if !client.IsErrObjectNotFound(err) {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// readHeader reads object header. Expired objects are considered to be removed.
+func (x *LocalNeoFS) readHeader(idCnr cid.ID, idObj oid.ID) (*object.Object, error) {
+	data, err := os.ReadFile(x.objectPath(idCnr, idObj))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read object header: %w", apistatus.ObjectNotFound{})
+	} else if err != nil {
+		return nil, fmt.Errorf("read object header: %w", err)
+	}
+
+	obj := object.New()
+	if err = obj.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("unmarshal object header: %w", err)
+	}
+
+	for _, attr := range obj.Attributes() {
+		if attr.Key() != objectv2.SysAttributeExpEpoch {
+			continue
+		}
+		exp, err := strconv.ParseUint(attr.Value(), 10, 64)
+		if err == nil && exp < x.CurrentEpoch() {
+			return nil, fmt.Errorf("read object header: %w", apistatus.ObjectNotFound{})
+		}
+	}
+
+	return obj, nil
+}
+
+func (x *LocalNeoFS) readContainer(idCnr cid.ID) (*container.Container, error) {
+	data, err := os.ReadFile(filepath.Join(x.containerDir(idCnr), localContainerFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, apistatus.ContainerNotFound{}
+	} else if err != nil {
+		return nil, err
+	}
+
+	var cnr container.Container
+	if err = cnr.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("unmarshal container: %w", err)
+	}
+
+	return &cnr, nil
+}
+
+// readEACL returns nil if there is no eACL table for the container.
+func (x *LocalNeoFS) readEACL(idCnr cid.ID) (*eacl.Table, error) {
+	data, err := os.ReadFile(filepath.Join(x.containerDir(idCnr), localEACLFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read eACL: %w", err)
+	}
+
+	table := eacl.NewTable()
+	if err = table.Unmarshal(data); err != nil {
+		return nil, fmt.Errorf("unmarshal eACL: %w", err)
+	}
+
+	return table, nil
+}
+
+// iterateContainers calls f for each stored container until f returns true.
+func (x *LocalNeoFS) iterateContainers(f func(cid.ID, container.Container) bool) error {
+	entries, err := os.ReadDir(filepath.Join(x.root, localContainersDir))
+	if err != nil {
+		return fmt.Errorf("list containers: %w", err)
+	}
+
+	for _, entry := range entries {
+		var idCnr cid.ID
+		if err = idCnr.DecodeString(entry.Name()); err != nil {
+			continue
+		}
+
+		cnr, err := x.readContainer(idCnr)
+		if err != nil {
+			continue
+		}
+
+		if f(idCnr, *cnr) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (x *LocalNeoFS) containerDir(idCnr cid.ID) string {
+	return filepath.Join(x.root, localContainersDir, idCnr.EncodeToString())
+}
+
+func (x *LocalNeoFS) objectPath(idCnr cid.ID, idObj oid.ID) string {
+	return filepath.Join(x.containerDir(idCnr), localObjectsDir, idObj.EncodeToString())
+}
+
+func (x *LocalNeoFS) senderKey(prm layer.PrmAuth) *ecdsa.PrivateKey {
+	if prm.BearerToken == nil && prm.PrivateKey != nil {
+		return prm.PrivateKey
+	}
+	return &x.key.PrivateKey
+}
+
+// checkContainerSession checks that container operation is performed by the owner
+// or within the session issued by the owner.
+func (x *LocalNeoFS) checkContainerSession(tok *session.Container, verb session.ContainerVerb, idCnr *cid.ID, owner user.ID) error {
+	if tok == nil {
+		var sender user.ID
+		user.IDFromKey(&sender, x.key.PrivateKey.PublicKey)
+		if !sender.Equals(owner) {
+			return fmt.Errorf("%w: request is not signed by the container owner", layer.ErrAccessDenied)
+		}
+		return nil
+	}
+
+	switch {
+	case !tok.VerifySignature():
+		return fmt.Errorf("%w: invalid session token signature", layer.ErrAccessDenied)
+	case !tok.AssertVerb(verb):
+		return fmt.Errorf("%w: session token is not for the operation", layer.ErrAccessDenied)
+	case idCnr != nil && !tok.AppliedTo(*idCnr):
+		return fmt.Errorf("%w: session token is not for the container", layer.ErrAccessDenied)
+	case !session.IssuedBy(*tok, owner):
+		return fmt.Errorf("%w: session token is not issued by the container owner", layer.ErrAccessDenied)
+	case !tok.AssertAuthKey((*neofsecdsa.PublicKey)(&x.key.PrivateKey.PublicKey)):
+		return fmt.Errorf("%w: session token is issued for another key", layer.ErrAccessDenied)
+	case tok.InvalidAt(x.CurrentEpoch()):
+		return fmt.Errorf("%w: session token is expired", layer.ErrAccessDenied)
+	}
+
+	return nil
+}
+
+// checkObjectAccess checks basic ACL and eACL rules for the object operation
+// the same way as storage nodes do. The hdr can be nil if object doesn't exist.
+func (x *LocalNeoFS) checkObjectAccess(idCnr cid.ID, cnr container.Container, prm layer.PrmAuth, op acl.Op, hdr *object.Object) error {
+	key := x.senderKey(prm)
+
+	var sender user.ID
+	user.IDFromKey(&sender, key.PublicKey)
+
+	role, eaclRole := acl.RoleOthers, eacl.RoleOthers
+	if sender.Equals(cnr.Owner()) {
+		role, eaclRole = acl.RoleOwner, eacl.RoleUser
+	}
+
+	basicACL := cnr.BasicACL()
+	if !basicACL.IsOpAllowed(op, role) {
+		return fmt.Errorf("%w: access to operation %s is denied by basic ACL check", layer.ErrAccessDenied, op)
+	}
+
+	if !basicACL.Extendable() {
+		return nil
+	}
+
+	var table *eacl.Table
+	if prm.BearerToken != nil && basicACL.AllowedBearerRules(op) {
+		if err := x.checkBearer(*prm.BearerToken, idCnr, cnr, sender); err != nil {
+			return err
+		}
+		bTable := prm.BearerToken.EACLTable()
+		table = &bTable
+	} else {
+		var err error
+		if table, err = x.readEACL(idCnr); err != nil {
+			return err
+		}
+	}
+
+	if table == nil {
+		return nil
+	}
+
+	unit := new(eacl.ValidationUnit).
+		WithContainerID(&idCnr).
+		WithRole(eaclRole).
+		WithOperation(eaclOperation(op)).
+		WithSenderKey((*keys.PublicKey)(&key.PublicKey).Bytes()).
+		WithHeaderSource(localHeaderSource{obj: hdr}).
+		WithEACLTable(table)
+
+	if action, _ := eacl.NewValidator().CalculateAction(unit); action != eacl.ActionAllow {
+		return fmt.Errorf("%w: access to operation %s is denied by extended ACL check", layer.ErrAccessDenied, op)
+	}
+
+	return nil
+}
+
+func (x *LocalNeoFS) checkBearer(tok bearer.Token, idCnr cid.ID, cnr container.Container, sender user.ID) error {
+	switch {
+	case !tok.VerifySignature():
+		return fmt.Errorf("%w: invalid bearer token signature", layer.ErrAccessDenied)
+	case tok.InvalidAt(x.CurrentEpoch()):
+		return fmt.Errorf("%w: bearer token is expired", layer.ErrAccessDenied)
+	case !tok.AssertContainer(idCnr):
+		return fmt.Errorf("%w: bearer token is not for the container", layer.ErrAccessDenied)
+	case !bearer.ResolveIssuer(tok).Equals(cnr.Owner()):
+		return fmt.Errorf("%w: bearer token is not issued by the container owner", layer.ErrAccessDenied)
+	case !tok.AssertUser(sender):
+		return fmt.Errorf("%w: bearer token is issued for another user", layer.ErrAccessDenied)
+	}
+
+	return nil
+}
+
+func eaclOperation(op acl.Op) eacl.Operation {
+	switch op {
+	case acl.OpObjectGet:
+		return eacl.OperationGet
+	case acl.OpObjectHead:
+		return eacl.OperationHead
+	case acl.OpObjectPut:
+		return eacl.OperationPut
+	case acl.OpObjectDelete:
+		return eacl.OperationDelete
+	case acl.OpObjectSearch:
+		return eacl.OperationSearch
+	case acl.OpObjectRange:
+		return eacl.OperationRange
+	case acl.OpObjectHash:
+		return eacl.OperationRangeHash
+	default:
+		return eacl.OperationUnknown
+	}
+}
+
+type localHeader struct {
+	key, value string
+}
+
+func (h localHeader) Key() string {
+	return h.key
+}
+
+func (h localHeader) Value() string {
+	return h.value
+}
+
+// localHeaderSource provides object headers for eACL filters.
+// There are no request X-headers in LocalNeoFS.
+type localHeaderSource struct {
+	obj *object.Object
+}
+
+func (s localHeaderSource) HeadersOfType(typ eacl.FilterHeaderType) ([]eacl.Header, bool) {
+	if typ != eacl.HeaderFromObject || s.obj == nil {
+		return nil, true
+	}
+
+	res := make([]eacl.Header, 0, len(s.obj.Attributes())+7)
+	for _, attr := range s.obj.Attributes() {
+		res = append(res, localHeader{key: attr.Key(), value: attr.Value()})
+	}
+
+	if idCnr, ok := s.obj.ContainerID(); ok {
+		res = append(res, localHeader{key: v2acl.FilterObjectContainerID, value: idCnr.EncodeToString()})
+	}
+	if idObj, ok := s.obj.ID(); ok {
+		res = append(res, localHeader{key: v2acl.FilterObjectID, value: idObj.EncodeToString()})
+	}
+	if owner := s.obj.OwnerID(); owner != nil {
+		res = append(res, localHeader{key: v2acl.FilterObjectOwnerID, value: owner.EncodeToString()})
+	}
+	if cs, ok := s.obj.PayloadChecksum(); ok {
+		res = append(res, localHeader{key: v2acl.FilterObjectPayloadHash, value: hex.EncodeToString(cs.Value())})
+	}
+
+	res = append(res,
+		localHeader{key: v2acl.FilterObjectCreationEpoch, value: strconv.FormatUint(s.obj.CreationEpoch(), 10)},
+		localHeader{key: v2acl.FilterObjectPayloadLength, value: strconv.FormatUint(s.obj.PayloadSize(), 10)},
+		localHeader{key: v2acl.FilterObjectType, value: s.obj.Type().String()},
+	)
+
+	return res, true
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to path.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,260 @@
+package neofs
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	objectv2 "github.com/nspcc-dev/neofs-api-go/v2/object"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"github.com/nspcc-dev/neofs-sdk-go/client"
+	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
+	"github.com/nspcc-dev/neofs-sdk-go/container/acl"
+	cid "github.com/nspcc-dev/neofs-sdk-go/container/id"
+	"github.com/nspcc-dev/neofs-sdk-go/eacl"
+	"github.com/nspcc-dev/neofs-sdk-go/netmap"
+	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
+	"github.com/nspcc-dev/neofs-sdk-go/user"
+	"github.com/stretchr/testify/require"
+)
+
+func newLocalNeoFS(t *testing.T) (*LocalNeoFS, user.ID) {
+	key, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	neoFS, err := NewLocalNeoFS(t.TempDir(), key, time.Hour)
+	require.NoError(t, err)
+
+	var owner user.ID
+	user.IDFromKey(&owner, key.PrivateKey.PublicKey)
+
+	return neoFS, owner
+}
+
+func createLocalContainer(t *testing.T, neoFS *LocalNeoFS, owner user.ID, name string, basicACL acl.Basic) cid.ID {
+	var policy netmap.PlacementPolicy
+	require.NoError(t, policy.DecodeString("REP 1"))
+
+	idCnr, err := neoFS.CreateContainer(context.Background(), layer.PrmContainerCreate{
+		Creator:  owner,
+		Policy:   policy,
+		Name:     name,
+		BasicACL: basicACL,
+	})
+	require.NoError(t, err)
+	return idCnr
+}
+
+func putLocalObject(t *testing.T, neoFS *LocalNeoFS, prm layer.PrmObjectCreate) oid.ID {
+	id, err := neoFS.CreateObject(context.Background(), prm)
+	require.NoError(t, err)
+	return id
+}
+
+func TestLocalNeoFSContainers(t *testing.T) {
+	ctx := context.Background()
+	neoFS, owner := newLocalNeoFS(t)
+
+	idCnr := createLocalContainer(t, neoFS, owner, "bucket", 0)
+
+	cnr, err := neoFS.Container(ctx, idCnr)
+	require.NoError(t, err)
+	require.Equal(t, acl.PublicRWExtended, cnr.BasicACL())
+
+	_, err = neoFS.CreateContainer(ctx, layer.PrmContainerCreate{Creator: owner, Policy: cnr.PlacementPolicy(), Name: "bucket"})
+	require.Error(t, err)
+
+	resolved, err := neoFS.ResolveContainerName(ctx, "bucket")
+	require.NoError(t, err)
+	require.Equal(t, idCnr, resolved)
+
+	list, err := neoFS.UserContainers(ctx, owner)
+	require.NoError(t, err)
+	require.Equal(t, []cid.ID{idCnr}, list)
+
+	_, err = neoFS.ContainerEACL(ctx, idCnr)
+	require.ErrorAs(t, err, new(apistatus.EACLNotFound))
+
+	otherKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	err = neoFS.WithKey(otherKey).DeleteContainer(ctx, idCnr, nil)
+	require.ErrorIs(t, err, layer.ErrAccessDenied)
+
+	require.NoError(t, neoFS.DeleteContainer(ctx, idCnr, nil))
+	_, err = neoFS.Container(ctx, idCnr)
+	require.True(t, client.IsErrContainerNotFound(err))
+
+	_, err = neoFS.ResolveContainerName(ctx, "bucket")
+	require.Error(t, err)
+}
+
+func TestLocalNeoFSObjects(t *testing.T) {
+	ctx := context.Background()
+	neoFS, owner := newLocalNeoFS(t)
+	idCnr := createLocalContainer(t, neoFS, owner, "bucket", 0)
+
+	payload := []byte("local object payload")
+	idObj := putLocalObject(t, neoFS, layer.PrmObjectCreate{
+		Container:  idCnr,
+		Creator:    owner,
+		Filepath:   "dir/obj",
+		Attributes: [][2]string{{"foo", "bar"}},
+		Payload:    bytes.NewReader(payload),
+	})
+
+	prm := layer.PrmObjectRead{Container: idCnr, Object: idObj, WithHeader: true}
+	res, err := neoFS.ReadObject(ctx, prm)
+	require.NoError(t, err)
+	require.Equal(t, uint64(len(payload)), res.Head.PayloadSize())
+	require.Empty(t, res.Head.Payload())
+
+	prm.WithPayload = true
+	res, err = neoFS.ReadObject(ctx, prm)
+	require.NoError(t, err)
+	require.Equal(t, payload, res.Head.Payload())
+
+	prm.WithHeader = false
+	res, err = neoFS.ReadObject(ctx, prm)
+	require.NoError(t, err)
+	full, err := io.ReadAll(res.Payload)
+	require.NoError(t, err)
+	require.NoError(t, res.Payload.Close())
+	require.Equal(t, payload, full)
+
+	prm.PayloadRange = [2]uint64{6, 6}
+	res, err = neoFS.ReadObject(ctx, prm)
+	require.NoError(t, err)
+	part, err := io.ReadAll(res.Payload)
+	require.NoError(t, err)
+	require.NoError(t, res.Payload.Close())
+	require.Equal(t, payload[6:12], part)
+
+	prm.PayloadRange = [2]uint64{6, uint64(len(payload))}
+	_, err = neoFS.ReadObject(ctx, prm)
+	require.ErrorAs(t, err, new(apistatus.ObjectOutOfRange))
+
+	other := putLocalObject(t, neoFS, layer.PrmObjectCreate{Container: idCnr, Creator: owner})
+
+	ids, err := neoFS.SearchObjects(ctx, layer.PrmObjectSearch{Container: idCnr})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []oid.ID{idObj, other}, ids)
+
+	ids, err = neoFS.SearchObjects(ctx, layer.PrmObjectSearch{Container: idCnr, WithAttribute: "foo"})
+	require.NoError(t, err)
+	require.Equal(t, []oid.ID{idObj}, ids)
+
+	require.NoError(t, neoFS.DeleteObject(ctx, layer.PrmObjectDelete{Container: idCnr, Object: idObj}))
+	_, err = neoFS.ReadObject(ctx, layer.PrmObjectRead{Container: idCnr, Object: idObj, WithHeader: true})
+	require.True(t, client.IsErrObjectNotFound(err))
+
+	// removal of missing object is not an error
+	require.NoError(t, neoFS.DeleteObject(ctx, layer.PrmObjectDelete{Container: idCnr, Object: idObj}))
+}
+
+func TestLocalNeoFSExpirationAndLocks(t *testing.T) {
+	ctx := context.Background()
+	neoFS, owner := newLocalNeoFS(t)
+	idCnr := createLocalContainer(t, neoFS, owner, "bucket", 0)
+
+	expired := putLocalObject(t, neoFS, layer.PrmObjectCreate{
+		Container:  idCnr,
+		Creator:    owner,
+		Attributes: [][2]string{{objectv2.SysAttributeExpEpoch, "0"}},
+	})
+	_, err := neoFS.ReadObject(ctx, layer.PrmObjectRead{Container: idCnr, Object: expired, WithHeader: true})
+	require.True(t, client.IsErrObjectNotFound(err))
+
+	idObj := putLocalObject(t, neoFS, layer.PrmObjectCreate{Container: idCnr, Creator: owner})
+	idLock := putLocalObject(t, neoFS, layer.PrmObjectCreate{
+		Container: idCnr,
+		Creator:   owner,
+		Locks:     []oid.ID{idObj},
+	})
+
+	err = neoFS.DeleteObject(ctx, layer.PrmObjectDelete{Container: idCnr, Object: idObj})
+	require.ErrorAs(t, err, new(apistatus.ObjectLocked))
+
+	require.NoError(t, neoFS.DeleteObject(ctx, layer.PrmObjectDelete{Container: idCnr, Object: idLock}))
+	require.NoError(t, neoFS.DeleteObject(ctx, layer.PrmObjectDelete{Container: idCnr, Object: idObj}))
+}
+
+func TestLocalNeoFSAccess(t *testing.T) {
+	ctx := context.Background()
+	neoFS, owner := newLocalNeoFS(t)
+	idCnr := createLocalContainer(t, neoFS, owner, "bucket", acl.PublicRWExtended)
+
+	idObj := putLocalObject(t, neoFS, layer.PrmObjectCreate{Container: idCnr, Creator: owner})
+
+	otherKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	var other user.ID
+	user.IDFromKey(&other, otherKey.PrivateKey.PublicKey)
+
+	prm := layer.PrmObjectRead{
+		PrmAuth:    layer.PrmAuth{PrivateKey: &otherKey.PrivateKey},
+		Container:  idCnr,
+		Object:     idObj,
+		WithHeader: true,
+	}
+	_, err = neoFS.ReadObject(ctx, prm)
+	require.NoError(t, err)
+
+	// deny everything to others by container eACL
+	table := eacl.NewTable()
+	table.SetCID(idCnr)
+	for op := eacl.OperationGet; op <= eacl.OperationRangeHash; op++ {
+		record := eacl.CreateRecord(eacl.ActionDeny, op)
+		eacl.AddFormedTarget(record, eacl.RoleOthers)
+		table.AddRecord(record)
+	}
+	require.NoError(t, neoFS.SetContainerEACL(ctx, *table, nil))
+
+	_, err = neoFS.ReadObject(ctx, prm)
+	require.ErrorIs(t, err, layer.ErrAccessDenied)
+
+	// owner is still allowed
+	_, err = neoFS.ReadObject(ctx, layer.PrmObjectRead{Container: idCnr, Object: idObj, WithHeader: true})
+	require.NoError(t, err)
+
+	// bearer token issued by the owner overrides container eACL
+	allow := eacl.NewTable()
+	allow.SetCID(idCnr)
+	record := eacl.CreateRecord(eacl.ActionAllow, eacl.OperationHead)
+	eacl.AddFormedTarget(record, eacl.RoleOthers)
+	allow.AddRecord(record)
+
+	var gateway user.ID
+	user.IDFromKey(&gateway, neoFS.key.PrivateKey.PublicKey)
+
+	ownerKey := neoFS.key
+	gatewayKey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	user.IDFromKey(&gateway, gatewayKey.PrivateKey.PublicKey)
+	gatewayNeoFS := neoFS.WithKey(gatewayKey)
+
+	var tok bearer.Token
+	tok.SetEACLTable(*allow)
+	tok.ForUser(gateway)
+	tok.SetExp(neoFS.CurrentEpoch() + 1)
+	require.NoError(t, tok.Sign(ownerKey.PrivateKey))
+
+	prm.PrmAuth = layer.PrmAuth{BearerToken: &tok}
+	_, err = gatewayNeoFS.ReadObject(ctx, prm)
+	require.NoError(t, err)
+
+	// bearer token for another user is rejected
+	tok.ForUser(other)
+	require.NoError(t, tok.Sign(ownerKey.PrivateKey))
+	_, err = gatewayNeoFS.ReadObject(ctx, prm)
+	require.ErrorIs(t, err, layer.ErrAccessDenied)
+
+	// bearer token not issued by the owner is rejected
+	tok.ForUser(gateway)
+	require.NoError(t, tok.Sign(otherKey.PrivateKey))
+	_, err = gatewayNeoFS.ReadObject(ctx, prm)
+	require.ErrorIs(t, err, layer.ErrAccessDenied)
+}
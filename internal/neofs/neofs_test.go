@@ -2,7 +2,10 @@ package neofs
 
 import (
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
 	apistatus "github.com/nspcc-dev/neofs-sdk-go/client/status"
@@ -23,3 +26,32 @@ func TestErrorChecking(t *testing.T) {
 	require.ErrorIs(t, wrappedError, layer.ErrAccessDenied)
 	require.Contains(t, wrappedError.Error(), reason)
 }
+
+func TestFirstByteReader(t *testing.T) {
+	labels := map[string]string{"method": methodGetObject}
+	before := sampleCount(t, "neofs_s3_neofs_first_byte_seconds", labels)
+
+	r := newFirstByteReader(io.NopCloser(strings.NewReader("payload")), methodGetObject, time.Now())
+	buf := make([]byte, 3)
+	for {
+		if _, err := r.Read(buf); err != nil {
+			require.ErrorIs(t, err, io.EOF)
+			break
+		}
+	}
+
+	require.Equal(t, before+1, sampleCount(t, "neofs_s3_neofs_first_byte_seconds", labels))
+}
+
+func TestObserveRequest(t *testing.T) {
+	labels := map[string]string{"method": methodHeadObject}
+	before := sampleCount(t, "neofs_s3_neofs_errors_total", labels)
+
+	observeRequest(methodHeadObject, time.Now(), nil)
+	observeRequest(methodHeadObject, time.Now(), apistatus.ObjectNotFound{})
+	observeRequest(methodHeadObject, time.Now(), new(apistatus.ObjectAccessDenied))
+	require.Equal(t, before, sampleCount(t, "neofs_s3_neofs_errors_total", labels))
+
+	observeRequest(methodHeadObject, time.Now(), apistatus.ServerInternal{})
+	require.Equal(t, before+1, sampleCount(t, "neofs_s3_neofs_errors_total", labels))
+}
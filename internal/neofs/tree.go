@@ -10,23 +10,27 @@ import (
 	"time"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
-	"github.com/nspcc-dev/neofs-s3-gw/api"
 	"github.com/nspcc-dev/neofs-s3-gw/api/data"
 	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
-	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
 	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs/services/tree"
-	"github.com/nspcc-dev/neofs-sdk-go/bearer"
 	oid "github.com/nspcc-dev/neofs-sdk-go/object/id"
 	"github.com/nspcc-dev/neofs-sdk-go/user"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type (
 	TreeClient struct {
-		key     *keys.PrivateKey
-		conn    *grpc.ClientConn
-		service tree.TreeServiceClient
+		service ServiceClient
+	}
+
+	// ServiceClient is a low-level client of the tree service.
+	// TreeClient builds the S3 object model on top of it.
+	ServiceClient interface {
+		GetNodes(ctx context.Context, p *GetNodesParams) ([]NodeResponse, error)
+		GetSubTree(ctx context.Context, bktInfo *data.BucketInfo, treeID string, rootID uint64, depth uint32) ([]NodeResponse, error)
+		AddNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, parent uint64, meta map[string]string) (uint64, error)
+		AddNodeByPath(ctx context.Context, bktInfo *data.BucketInfo, treeID string, path []string, meta map[string]string) (uint64, error)
+		MoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID, parentID uint64, meta map[string]string) error
+		RemoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error
 	}
 
 	TreeNode struct {
@@ -38,7 +42,7 @@ type (
 		Meta      map[string]string
 	}
 
-	getNodesParams struct {
+	GetNodesParams struct {
 		BktInfo    *data.BucketInfo
 		TreeID     string
 		Path       []string
@@ -51,6 +55,8 @@ type (
 const (
 	versioningKV        = "Versioning"
 	lockConfigurationKV = "LockConfiguration"
+	quotaKV             = "Quota"
+	trackUsageKV        = "TrackUsage"
 	oidKV               = "OID"
 	fileNameKV          = "FileName"
 	isUnversionedKV     = "IsUnversioned"
@@ -59,6 +65,7 @@ const (
 	partNumberKV        = "Number"
 	sizeKV              = "Size"
 	etagKV              = "ETag"
+	storageClassKV      = "StorageClass"
 
 	// keys for lock.
 	isLockKV       = "IsLock"
@@ -72,7 +79,19 @@ const (
 	ownerKV          = "Owner"
 	createdKV        = "Created"
 
+	// keys for bucket usage node.
+	usageSizeKV           = "UsageSize"
+	usageObjectsKV        = "UsageObjects"
+	usageCurrentObjectsKV = "UsageCurrentObjects"
+	usageDeleteMarkersKV  = "UsageDeleteMarkers"
+	usageMultipartSizeKV  = "UsageMultipartSize"
+
+	// keys for bucket import node.
+	importEpochKV = "ImportEpoch"
+
 	settingsFileName      = "bucket-settings"
+	usageFileName         = "bucket-usage"
+	importFileName        = "bucket-import"
 	notifConfFileName     = "bucket-notifications"
 	corsFilename          = "bucket-cors"
 	bucketTaggingFilename = "bucket-tagging"
@@ -92,21 +111,18 @@ const (
 
 // NewTreeClient creates instance of TreeClient using provided address and create grpc connection.
 func NewTreeClient(ctx context.Context, addr string, key *keys.PrivateKey) (*TreeClient, error) {
-	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	service, err := NewServiceClientGRPC(ctx, addr, key)
 	if err != nil {
-		return nil, fmt.Errorf("did not connect: %v", err)
+		return nil, err
 	}
 
-	c := tree.NewTreeServiceClient(conn)
-	if _, err = c.Healthcheck(ctx, &tree.HealthcheckRequest{}); err != nil {
-		return nil, fmt.Errorf("healthcheck: %w", err)
-	}
+	return NewTreeClientWithService(service), nil
+}
 
-	return &TreeClient{
-		key:     key,
-		conn:    conn,
-		service: c,
-	}, nil
+// NewTreeClientWithService creates instance of TreeClient using provided tree service client.
+// Every call of the service client is traced.
+func NewTreeClientWithService(service ServiceClient) *TreeClient {
+	return &TreeClient{service: tracingServiceClient{service: service}}
 }
 
 type NodeResponse interface {
@@ -168,6 +184,7 @@ func newNodeVersionFromTreeNode(filePath string, treeNode *TreeNode) *data.NodeV
 	_, isUnversioned := treeNode.Get(isUnversionedKV)
 	_, isDeleteMarker := treeNode.Get(isDeleteMarkerKV)
 	eTag, _ := treeNode.Get(etagKV)
+	storageClass, _ := treeNode.Get(storageClassKV)
 
 	version := &data.NodeVersion{
 		BaseNodeVersion: data.BaseNodeVersion{
@@ -180,6 +197,7 @@ func newNodeVersionFromTreeNode(filePath string, treeNode *TreeNode) *data.NodeV
 			FilePath:  filePath,
 		},
 		IsUnversioned: isUnversioned,
+		StorageClass:  storageClass,
 	}
 
 	if isDeleteMarker {
@@ -271,7 +289,7 @@ func newPartInfo(node NodeResponse) (*data.PartInfo, error) {
 }
 
 func (c *TreeClient) GetSettingsNode(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketSettings, error) {
-	keysToReturn := []string{versioningKV, lockConfigurationKV}
+	keysToReturn := []string{versioningKV, lockConfigurationKV, quotaKV, trackUsageKV}
 	node, err := c.getSystemNode(ctx, bktInfo, []string{settingsFileName}, keysToReturn)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't get node: %w", err)
@@ -288,6 +306,18 @@ func (c *TreeClient) GetSettingsNode(ctx context.Context, bktInfo *data.BucketIn
 		}
 	}
 
+	if quotaValue, ok := node.Get(quotaKV); ok {
+		if settings.Quota, err = parseQuota(quotaValue); err != nil {
+			return nil, fmt.Errorf("settings node: invalid quota: %w", err)
+		}
+	}
+
+	if trackUsageValue, ok := node.Get(trackUsageKV); ok {
+		if settings.TrackUsage, err = strconv.ParseBool(trackUsageValue); err != nil {
+			return nil, fmt.Errorf("settings node: invalid track usage flag: %w", err)
+		}
+	}
+
 	return settings, nil
 }
 
@@ -301,11 +331,93 @@ func (c *TreeClient) PutSettingsNode(ctx context.Context, bktInfo *data.BucketIn
 	meta := metaFromSettings(settings)
 
 	if isErrNotFound {
-		_, err = c.addNode(ctx, bktInfo, systemTree, 0, meta)
+		_, err = c.service.AddNode(ctx, bktInfo, systemTree, 0, meta)
+		return err
+	}
+
+	return c.service.MoveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) GetBucketUsage(ctx context.Context, bktInfo *data.BucketInfo) (*data.BucketUsage, error) {
+	usage := &data.BucketUsage{}
+	fields := usageFields(usage)
+
+	keysToReturn := make([]string, 0, len(fields))
+	for key := range fields {
+		keysToReturn = append(keysToReturn, key)
+	}
+
+	node, err := c.getSystemNode(ctx, bktInfo, []string{usageFileName}, keysToReturn)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	for key, field := range fields {
+		if value, ok := node.Get(key); ok {
+			if *field, err = strconv.ParseUint(value, 10, 64); err != nil {
+				return nil, fmt.Errorf("usage node: invalid '%s': %w", key, err)
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+func (c *TreeClient) PutBucketUsage(ctx context.Context, bktInfo *data.BucketInfo, usage *data.BucketUsage) error {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{usageFileName}, []string{})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	fields := usageFields(usage)
+	meta := make(map[string]string, len(fields)+1)
+	meta[fileNameKV] = usageFileName
+	for key, field := range fields {
+		meta[key] = strconv.FormatUint(*field, 10)
+	}
+
+	if isErrNotFound {
+		_, err = c.service.AddNode(ctx, bktInfo, systemTree, 0, meta)
+		return err
+	}
+
+	return c.service.MoveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+}
+
+func (c *TreeClient) GetImportCursor(ctx context.Context, bktInfo *data.BucketInfo) (uint64, error) {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{importFileName}, []string{importEpochKV})
+	if err != nil {
+		return 0, fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	value, _ := node.Get(importEpochKV)
+	epoch, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("import node: invalid epoch: %w", err)
+	}
+
+	return epoch, nil
+}
+
+func (c *TreeClient) PutImportCursor(ctx context.Context, bktInfo *data.BucketInfo, epoch uint64) error {
+	node, err := c.getSystemNode(ctx, bktInfo, []string{importFileName}, []string{})
+	isErrNotFound := errors.Is(err, layer.ErrNodeNotFound)
+	if err != nil && !isErrNotFound {
+		return fmt.Errorf("couldn't get node: %w", err)
+	}
+
+	meta := map[string]string{
+		fileNameKV:    importFileName,
+		importEpochKV: strconv.FormatUint(epoch, 10),
+	}
+
+	if isErrNotFound {
+		_, err = c.service.AddNode(ctx, bktInfo, systemTree, 0, meta)
 		return err
 	}
 
-	return c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+	return c.service.MoveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
 }
 
 func (c *TreeClient) GetNotificationConfigurationNode(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
@@ -329,13 +441,13 @@ func (c *TreeClient) PutNotificationConfigurationNode(ctx context.Context, bktIn
 	meta[oidKV] = objID.EncodeToString()
 
 	if isErrNotFound {
-		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+		if _, err = c.service.AddNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
 			return oid.ID{}, err
 		}
 		return oid.ID{}, layer.ErrNoNodeToRemove
 	}
 
-	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+	return node.ObjID, c.service.MoveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
 }
 
 func (c *TreeClient) GetBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
@@ -359,13 +471,13 @@ func (c *TreeClient) PutBucketCORS(ctx context.Context, bktInfo *data.BucketInfo
 	meta[oidKV] = objID.EncodeToString()
 
 	if isErrNotFound {
-		if _, err = c.addNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
+		if _, err = c.service.AddNode(ctx, bktInfo, systemTree, 0, meta); err != nil {
 			return oid.ID{}, err
 		}
 		return oid.ID{}, layer.ErrNoNodeToRemove
 	}
 
-	return node.ObjID, c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
+	return node.ObjID, c.service.MoveNode(ctx, bktInfo, systemTree, node.ID, 0, meta)
 }
 
 func (c *TreeClient) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketInfo) (oid.ID, error) {
@@ -375,7 +487,7 @@ func (c *TreeClient) DeleteBucketCORS(ctx context.Context, bktInfo *data.BucketI
 	}
 
 	if node != nil {
-		return node.ObjID, c.removeNode(ctx, bktInfo, systemTree, node.ID)
+		return node.ObjID, c.service.RemoveNode(ctx, bktInfo, systemTree, node.ID)
 	}
 
 	return oid.ID{}, layer.ErrNoNodeToRemove
@@ -412,22 +524,28 @@ func (c *TreeClient) PutObjectTagging(ctx context.Context, bktInfo *data.BucketI
 		return err
 	}
 
-	treeTagSet := make(map[string]string)
-	treeTagSet[isTagKV] = "true"
-
-	for key, val := range tagSet {
-		treeTagSet[userDefinedTagPrefix+key] = val
-	}
+	treeTagSet := objectTagMeta(tagSet)
 
 	if tagNode == nil {
-		_, err = c.addNode(ctx, bktInfo, versionTree, objVersion.ID, treeTagSet)
+		_, err = c.service.AddNode(ctx, bktInfo, versionTree, objVersion.ID, treeTagSet)
 	} else {
-		err = c.moveNode(ctx, bktInfo, versionTree, tagNode.ID, objVersion.ID, treeTagSet)
+		err = c.service.MoveNode(ctx, bktInfo, versionTree, tagNode.ID, objVersion.ID, treeTagSet)
 	}
 
 	return err
 }
 
+func objectTagMeta(tagSet map[string]string) map[string]string {
+	treeTagSet := make(map[string]string, len(tagSet)+1)
+	treeTagSet[isTagKV] = "true"
+
+	for key, val := range tagSet {
+		treeTagSet[userDefinedTagPrefix+key] = val
+	}
+
+	return treeTagSet
+}
+
 func (c *TreeClient) DeleteObjectTagging(ctx context.Context, bktInfo *data.BucketInfo, objVersion *data.NodeVersion) error {
 	tagNode, err := c.getTreeNode(ctx, bktInfo, objVersion.ID, isTagKV)
 	if err != nil {
@@ -438,7 +556,7 @@ func (c *TreeClient) DeleteObjectTagging(ctx context.Context, bktInfo *data.Buck
 		return nil
 	}
 
-	return c.removeNode(ctx, bktInfo, versionTree, tagNode.ID)
+	return c.service.RemoveNode(ctx, bktInfo, versionTree, tagNode.ID)
 }
 
 func (c *TreeClient) GetBucketTagging(ctx context.Context, bktInfo *data.BucketInfo) (map[string]string, error) {
@@ -473,9 +591,9 @@ func (c *TreeClient) PutBucketTagging(ctx context.Context, bktInfo *data.BucketI
 	}
 
 	if isErrNotFound {
-		_, err = c.addNode(ctx, bktInfo, systemTree, 0, treeTagSet)
+		_, err = c.service.AddNode(ctx, bktInfo, systemTree, 0, treeTagSet)
 	} else {
-		err = c.moveNode(ctx, bktInfo, systemTree, node.ID, 0, treeTagSet)
+		err = c.service.MoveNode(ctx, bktInfo, systemTree, node.ID, 0, treeTagSet)
 	}
 
 	return err
@@ -488,7 +606,7 @@ func (c *TreeClient) DeleteBucketTagging(ctx context.Context, bktInfo *data.Buck
 	}
 
 	if node != nil {
-		return c.removeNode(ctx, bktInfo, systemTree, node.ID)
+		return c.service.RemoveNode(ctx, bktInfo, systemTree, node.ID)
 	}
 
 	return nil
@@ -505,7 +623,7 @@ func (c *TreeClient) getTreeNode(ctx context.Context, bktInfo *data.BucketInfo,
 }
 
 func (c *TreeClient) getTreeNodes(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64, keys ...string) (map[string]*TreeNode, error) {
-	subtree, err := c.getSubTree(ctx, bktInfo, versionTree, nodeID, 2)
+	subtree, err := c.service.GetSubTree(ctx, bktInfo, versionTree, nodeID, 2)
 	if err != nil {
 		return nil, err
 	}
@@ -536,10 +654,10 @@ func (c *TreeClient) GetVersions(ctx context.Context, bktInfo *data.BucketInfo,
 }
 
 func (c *TreeClient) GetLatestVersion(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, error) {
-	meta := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV}
+	meta := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV, storageClassKV}
 	path := pathFromName(objectName)
 
-	p := &getNodesParams{
+	p := &GetNodesParams{
 		BktInfo:    bktInfo,
 		TreeID:     versionTree,
 		Path:       path,
@@ -547,7 +665,7 @@ func (c *TreeClient) GetLatestVersion(ctx context.Context, bktInfo *data.BucketI
 		LatestOnly: true,
 		AllAttrs:   false,
 	}
-	nodes, err := c.getNodes(ctx, p)
+	nodes, err := c.service.GetNodes(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -585,14 +703,14 @@ func (c *TreeClient) determinePrefixNode(ctx context.Context, bktInfo *data.Buck
 }
 
 func (c *TreeClient) getPrefixNodeID(ctx context.Context, bktInfo *data.BucketInfo, treeID string, prefixPath []string) (uint64, error) {
-	p := &getNodesParams{
+	p := &GetNodesParams{
 		BktInfo:    bktInfo,
 		TreeID:     treeID,
 		Path:       prefixPath,
 		LatestOnly: false,
 		AllAttrs:   true,
 	}
-	nodes, err := c.getNodes(ctx, p)
+	nodes, err := c.service.GetNodes(ctx, p)
 	if err != nil {
 		return 0, err
 	}
@@ -614,7 +732,7 @@ func (c *TreeClient) getPrefixNodeID(ctx context.Context, bktInfo *data.BucketIn
 	return intermediateNodes[0], nil
 }
 
-func (c *TreeClient) getSubTreeByPrefix(ctx context.Context, bktInfo *data.BucketInfo, treeID, prefix string, latestOnly bool) ([]*tree.GetSubTreeResponse_Body, string, error) {
+func (c *TreeClient) getSubTreeByPrefix(ctx context.Context, bktInfo *data.BucketInfo, treeID, prefix string, latestOnly bool) ([]NodeResponse, string, error) {
 	rootID, tailPrefix, err := c.determinePrefixNode(ctx, bktInfo, treeID, prefix)
 	if err != nil {
 		if errors.Is(err, layer.ErrNodeNotFound) {
@@ -623,7 +741,7 @@ func (c *TreeClient) getSubTreeByPrefix(ctx context.Context, bktInfo *data.Bucke
 		return nil, "", err
 	}
 
-	subTree, err := c.getSubTree(ctx, bktInfo, treeID, rootID, 2)
+	subTree, err := c.service.GetSubTree(ctx, bktInfo, treeID, rootID, 2)
 	if err != nil {
 		if errors.Is(err, layer.ErrNodeNotFound) {
 			return nil, "", nil
@@ -631,7 +749,7 @@ func (c *TreeClient) getSubTreeByPrefix(ctx context.Context, bktInfo *data.Bucke
 		return nil, "", err
 	}
 
-	nodesMap := make(map[string][]*tree.GetSubTreeResponse_Body, len(subTree))
+	nodesMap := make(map[string][]NodeResponse, len(subTree))
 	for _, node := range subTree {
 		if node.GetNodeId() == rootID {
 			continue
@@ -648,11 +766,11 @@ func (c *TreeClient) getSubTreeByPrefix(ctx context.Context, bktInfo *data.Bucke
 		// Add all intermediate nodes (actually should be exactly one intermediate node with the same name)
 		// and only latest leaf (object) nodes. To do this store and replace last leaf (object) node in nodes[0]
 		if len(nodes) == 0 {
-			nodes = []*tree.GetSubTreeResponse_Body{node}
+			nodes = []NodeResponse{node}
 		} else if !latestOnly || isIntermediate(node) {
 			nodes = append(nodes, node)
 		} else if isIntermediate(nodes[0]) {
-			nodes = append([]*tree.GetSubTreeResponse_Body{node}, nodes...)
+			nodes = append([]NodeResponse{node}, nodes...)
 		} else if node.GetTimestamp() > nodes[0].GetTimestamp() {
 			nodes[0] = node
 		}
@@ -660,7 +778,7 @@ func (c *TreeClient) getSubTreeByPrefix(ctx context.Context, bktInfo *data.Bucke
 		nodesMap[fileName] = nodes
 	}
 
-	result := make([]*tree.GetSubTreeResponse_Body, 0, len(subTree))
+	result := make([]NodeResponse, 0, len(subTree))
 	for _, nodes := range nodesMap {
 		result = append(result, nodes...)
 	}
@@ -668,7 +786,7 @@ func (c *TreeClient) getSubTreeByPrefix(ctx context.Context, bktInfo *data.Bucke
 	return result, strings.TrimSuffix(prefix, tailPrefix), nil
 }
 
-func getFilename(node *tree.GetSubTreeResponse_Body) string {
+func getFilename(node NodeResponse) string {
 	for _, kv := range node.GetMeta() {
 		if kv.GetKey() == fileNameKV {
 			return string(kv.GetValue())
@@ -687,7 +805,7 @@ func isIntermediate(node NodeResponse) bool {
 }
 
 func (c *TreeClient) getSubTreeVersions(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64, parentFilePath string, latestOnly bool) ([]*data.NodeVersion, error) {
-	subTree, err := c.getSubTree(ctx, bktInfo, versionTree, nodeID, maxGetSubTreeDepth)
+	subTree, err := c.service.GetSubTree(ctx, bktInfo, versionTree, nodeID, maxGetSubTreeDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -745,7 +863,7 @@ func (c *TreeClient) getSubTreeVersions(ctx context.Context, bktInfo *data.Bucke
 	return result, nil
 }
 
-func formFilePath(node *tree.GetSubTreeResponse_Body, fileName string, namesMap map[uint64]string) (string, error) {
+func formFilePath(node NodeResponse, fileName string, namesMap map[uint64]string) (string, error) {
 	parentPath, ok := namesMap[node.GetParentId()]
 	if !ok {
 		return "", fmt.Errorf("couldn't get parent path")
@@ -757,7 +875,7 @@ func formFilePath(node *tree.GetSubTreeResponse_Body, fileName string, namesMap
 	return filepath, nil
 }
 
-func parseTreeNode(node *tree.GetSubTreeResponse_Body) (*TreeNode, string, error) {
+func parseTreeNode(node NodeResponse) (*TreeNode, string, error) {
 	treeNode, err := newTreeNode(node)
 	if err != nil { // invalid OID attribute
 		return nil, "", err
@@ -819,17 +937,25 @@ func (c *TreeClient) getUnversioned(ctx context.Context, bktInfo *data.BucketInf
 }
 
 func (c *TreeClient) AddVersion(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion) (uint64, error) {
-	return c.addVersion(ctx, bktInfo, versionTree, version)
+	return c.addVersion(ctx, bktInfo, versionTree, version, nil, nil)
+}
+
+func (c *TreeClient) AddVersionWithTagging(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, tagSet map[string]string) (uint64, error) {
+	return c.addVersion(ctx, bktInfo, versionTree, version, tagSet, nil)
+}
+
+func (c *TreeClient) AddVersionIfUnchanged(ctx context.Context, bktInfo *data.BucketInfo, version *data.NodeVersion, tagSet map[string]string, expected *data.NodeVersion) (uint64, error) {
+	return c.addVersion(ctx, bktInfo, versionTree, version, tagSet, &expectedVersion{version: expected})
 }
 
 func (c *TreeClient) RemoveVersion(ctx context.Context, bktInfo *data.BucketInfo, id uint64) error {
-	return c.removeNode(ctx, bktInfo, versionTree, id)
+	return c.service.RemoveNode(ctx, bktInfo, versionTree, id)
 }
 
 func (c *TreeClient) CreateMultipartUpload(ctx context.Context, bktInfo *data.BucketInfo, info *data.MultipartInfo) error {
 	path := pathFromName(info.Key)
 	meta := metaFromMultipart(info, path[len(path)-1])
-	_, err := c.addNodeByPath(ctx, bktInfo, systemTree, path[:len(path)-1], meta)
+	_, err := c.service.AddNodeByPath(ctx, bktInfo, systemTree, path[:len(path)-1], meta)
 
 	return err
 }
@@ -853,7 +979,7 @@ func (c *TreeClient) GetMultipartUploadsByPrefix(ctx context.Context, bktInfo *d
 }
 
 func (c *TreeClient) getSubTreeMultipartUploads(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) ([]*data.MultipartInfo, error) {
-	subTree, err := c.getSubTree(ctx, bktInfo, systemTree, nodeID, maxGetSubTreeDepth)
+	subTree, err := c.service.GetSubTree(ctx, bktInfo, systemTree, nodeID, maxGetSubTreeDepth)
 	if err != nil {
 		return nil, err
 	}
@@ -872,14 +998,14 @@ func (c *TreeClient) getSubTreeMultipartUploads(ctx context.Context, bktInfo *da
 
 func (c *TreeClient) GetMultipartUpload(ctx context.Context, bktInfo *data.BucketInfo, objectName, uploadID string) (*data.MultipartInfo, error) {
 	path := pathFromName(objectName)
-	p := &getNodesParams{
+	p := &GetNodesParams{
 		BktInfo:  bktInfo,
 		TreeID:   systemTree,
 		Path:     path,
 		AllAttrs: true,
 	}
 
-	nodes, err := c.getNodes(ctx, p)
+	nodes, err := c.service.GetNodes(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -898,7 +1024,7 @@ func (c *TreeClient) GetMultipartUpload(ctx context.Context, bktInfo *data.Bucke
 }
 
 func (c *TreeClient) AddPart(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64, info *data.PartInfo) (oldObjIDToDelete oid.ID, err error) {
-	parts, err := c.getSubTree(ctx, bktInfo, systemTree, multipartNodeID, 2)
+	parts, err := c.service.GetSubTree(ctx, bktInfo, systemTree, multipartNodeID, 2)
 	if err != nil {
 		return oid.ID{}, err
 	}
@@ -928,17 +1054,17 @@ func (c *TreeClient) AddPart(ctx context.Context, bktInfo *data.BucketInfo, mult
 	}
 
 	if foundPartID != multipartNodeID {
-		if _, err = c.addNode(ctx, bktInfo, systemTree, multipartNodeID, meta); err != nil {
+		if _, err = c.service.AddNode(ctx, bktInfo, systemTree, multipartNodeID, meta); err != nil {
 			return oid.ID{}, err
 		}
 		return oid.ID{}, layer.ErrNoNodeToRemove
 	}
 
-	return oldObjIDToDelete, c.moveNode(ctx, bktInfo, systemTree, foundPartID, multipartNodeID, meta)
+	return oldObjIDToDelete, c.service.MoveNode(ctx, bktInfo, systemTree, foundPartID, multipartNodeID, meta)
 }
 
 func (c *TreeClient) GetParts(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64) ([]*data.PartInfo, error) {
-	parts, err := c.getSubTree(ctx, bktInfo, systemTree, multipartNodeID, 2)
+	parts, err := c.service.GetSubTree(ctx, bktInfo, systemTree, multipartNodeID, 2)
 	if err != nil {
 		return nil, err
 	}
@@ -959,7 +1085,7 @@ func (c *TreeClient) GetParts(ctx context.Context, bktInfo *data.BucketInfo, mul
 }
 
 func (c *TreeClient) DeleteMultipartUpload(ctx context.Context, bktInfo *data.BucketInfo, multipartNodeID uint64) error {
-	return c.removeNode(ctx, bktInfo, systemTree, multipartNodeID)
+	return c.service.RemoveNode(ctx, bktInfo, systemTree, multipartNodeID)
 }
 
 func (c *TreeClient) PutLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64, lock *data.LockInfo) error {
@@ -977,11 +1103,11 @@ func (c *TreeClient) PutLock(ctx context.Context, bktInfo *data.BucketInfo, node
 	}
 
 	if lock.ID() == 0 {
-		_, err := c.addNode(ctx, bktInfo, versionTree, nodeID, meta)
+		_, err := c.service.AddNode(ctx, bktInfo, versionTree, nodeID, meta)
 		return err
 	}
 
-	return c.moveNode(ctx, bktInfo, versionTree, lock.ID(), nodeID, meta)
+	return c.service.MoveNode(ctx, bktInfo, versionTree, lock.ID(), nodeID, meta)
 }
 
 func (c *TreeClient) GetLock(ctx context.Context, bktInfo *data.BucketInfo, nodeID uint64) (*data.LockInfo, error) {
@@ -1034,15 +1160,55 @@ func (c *TreeClient) GetObjectTaggingAndLock(ctx context.Context, bktInfo *data.
 	return getObjectTagging(nodes[isTagKV]), lockInfo, nil
 }
 
+func (c *TreeClient) GetLatestAndUnversioned(ctx context.Context, bktInfo *data.BucketInfo, objectName string) (*data.NodeVersion, *data.NodeVersion, error) {
+	versions, err := c.getVersions(ctx, bktInfo, versionTree, objectName, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var latest, unversioned *data.NodeVersion
+	for _, version := range versions {
+		if latest == nil || latest.Timestamp <= version.Timestamp {
+			latest = version
+		}
+		if version.IsUnversioned {
+			if unversioned != nil {
+				return nil, nil, fmt.Errorf("found more than one unversioned node")
+			}
+			unversioned = version
+		}
+	}
+
+	if latest == nil {
+		return nil, nil, layer.ErrNodeNotFound
+	}
+
+	return latest, unversioned, nil
+}
+
 func (c *TreeClient) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
+	if closer, ok := c.service.(io.Closer); ok {
+		return closer.Close()
 	}
 
 	return nil
 }
 
-func (c *TreeClient) addVersion(ctx context.Context, bktInfo *data.BucketInfo, treeID string, version *data.NodeVersion) (uint64, error) {
+// expectedVersion is the unversioned node that the new unversioned version must replace.
+// Nil version means that there must be no unversioned node.
+type expectedVersion struct {
+	version *data.NodeVersion
+}
+
+func (e *expectedVersion) matches(node *data.NodeVersion) bool {
+	if e.version == nil || node == nil {
+		return e.version == nil && node == nil
+	}
+
+	return e.version.ID == node.ID && e.version.OID.Equals(node.OID) && e.version.Timestamp == node.Timestamp
+}
+
+func (c *TreeClient) addVersion(ctx context.Context, bktInfo *data.BucketInfo, treeID string, version *data.NodeVersion, tagSet map[string]string, expected *expectedVersion) (uint64, error) {
 	path := pathFromName(version.FilePath)
 	meta := map[string]string{
 		oidKV:      version.OID.EncodeToString(),
@@ -1055,6 +1221,9 @@ func (c *TreeClient) addVersion(ctx context.Context, bktInfo *data.BucketInfo, t
 	if len(version.ETag) > 0 {
 		meta[etagKV] = version.ETag
 	}
+	if len(version.StorageClass) > 0 {
+		meta[storageClassKV] = version.StorageClass
+	}
 
 	if version.IsDeleteMarker() {
 		meta[isDeleteMarkerKV] = "true"
@@ -1066,38 +1235,83 @@ func (c *TreeClient) addVersion(ctx context.Context, bktInfo *data.BucketInfo, t
 		meta[isUnversionedKV] = "true"
 
 		node, err := c.getUnversioned(ctx, bktInfo, treeID, version.FilePath)
-		if err == nil {
-			if err = c.moveNode(ctx, bktInfo, treeID, node.ID, node.ParenID, meta); err != nil {
+		if err != nil && !errors.Is(err, layer.ErrNodeNotFound) {
+			return 0, err
+		}
+
+		// The tree service has no compare-and-swap, so the node is checked right before it's replaced.
+		if expected != nil && !expected.matches(node) {
+			return 0, layer.ErrNodeChanged
+		}
+
+		if node != nil {
+			if err = c.replaceVersion(ctx, bktInfo, treeID, node, meta, tagSet); err != nil && !errors.Is(err, layer.ErrVersionTagging) {
 				return 0, err
 			}
-
-			return node.ID, c.clearOutdatedVersionInfo(ctx, bktInfo, treeID, node.ID)
+			return node.ID, err
 		}
+	}
 
-		if !errors.Is(err, layer.ErrNodeNotFound) {
-			return 0, err
+	nodeID, err := c.service.AddNodeByPath(ctx, bktInfo, treeID, path[:len(path)-1], meta)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(tagSet) > 0 {
+		// The tree service has no batch requests, so the version and its tags are added
+		// by two calls. The version without tags mustn't stay in the tree.
+		if _, err = c.service.AddNode(ctx, bktInfo, treeID, nodeID, objectTagMeta(tagSet)); err != nil {
+			if rmErr := c.service.RemoveNode(ctx, bktInfo, treeID, nodeID); rmErr != nil {
+				return nodeID, fmt.Errorf("%w: couldn't add tagging node: %s, couldn't remove version node %d: %s",
+					layer.ErrVersionTagging, err, nodeID, rmErr)
+			}
+			return 0, fmt.Errorf("couldn't add tagging node: %w", err)
 		}
 	}
 
-	return c.addNodeByPath(ctx, bktInfo, treeID, path[:len(path)-1], meta)
+	return nodeID, nil
 }
 
-func (c *TreeClient) clearOutdatedVersionInfo(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error {
-	taggingNode, err := c.getTreeNode(ctx, bktInfo, nodeID, isTagKV)
+// replaceVersion updates the unversioned node in place and replaces its tagging with the new tag set.
+// The tagging node is looked up before the move, so nothing is changed if the lookup fails. The object
+// is replaced once the node is moved, so failures of the tagging update are returned as ErrVersionTagging.
+// The tags of the replaced object are removed at least, so they don't stay on the new one.
+func (c *TreeClient) replaceVersion(ctx context.Context, bktInfo *data.BucketInfo, treeID string, node *data.NodeVersion, meta, tagSet map[string]string) error {
+	taggingNode, err := c.getTreeNode(ctx, bktInfo, node.ID, isTagKV)
 	if err != nil {
 		return err
 	}
-	if taggingNode != nil {
-		return c.removeNode(ctx, bktInfo, treeID, taggingNode.ID)
+
+	if err = c.service.MoveNode(ctx, bktInfo, treeID, node.ID, node.ParenID, meta); err != nil {
+		return err
+	}
+
+	switch {
+	case taggingNode != nil && len(tagSet) > 0:
+		if err = c.service.MoveNode(ctx, bktInfo, treeID, taggingNode.ID, node.ID, objectTagMeta(tagSet)); err != nil {
+			if rmErr := c.service.RemoveNode(ctx, bktInfo, treeID, taggingNode.ID); rmErr != nil {
+				return fmt.Errorf("%w: couldn't replace tagging node: %s, couldn't remove outdated tagging node %d: %s",
+					layer.ErrVersionTagging, err, taggingNode.ID, rmErr)
+			}
+			return fmt.Errorf("%w: couldn't replace tagging node: %s", layer.ErrVersionTagging, err)
+		}
+	case taggingNode != nil:
+		if err = c.service.RemoveNode(ctx, bktInfo, treeID, taggingNode.ID); err != nil {
+			return fmt.Errorf("%w: couldn't remove outdated tagging node: %s", layer.ErrVersionTagging, err)
+		}
+	case len(tagSet) > 0:
+		if _, err = c.service.AddNode(ctx, bktInfo, treeID, node.ID, objectTagMeta(tagSet)); err != nil {
+			return fmt.Errorf("%w: couldn't add tagging node: %s", layer.ErrVersionTagging, err)
+		}
 	}
 
 	return nil
 }
 
 func (c *TreeClient) getVersions(ctx context.Context, bktInfo *data.BucketInfo, treeID, filepath string, onlyUnversioned bool) ([]*data.NodeVersion, error) {
-	keysToReturn := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV}
+	keysToReturn := []string{oidKV, isUnversionedKV, isDeleteMarkerKV, etagKV, sizeKV, storageClassKV}
 	path := pathFromName(filepath)
-	p := &getNodesParams{
+	p := &GetNodesParams{
 		BktInfo:    bktInfo,
 		TreeID:     treeID,
 		Path:       path,
@@ -1105,7 +1319,7 @@ func (c *TreeClient) getVersions(ctx context.Context, bktInfo *data.BucketInfo,
 		LatestOnly: false,
 		AllAttrs:   false,
 	}
-	nodes, err := c.getNodes(ctx, p)
+	nodes, err := c.service.GetNodes(ctx, p)
 	if err != nil {
 		if errors.Is(err, layer.ErrNodeNotFound) {
 			return nil, nil
@@ -1130,55 +1344,33 @@ func (c *TreeClient) getVersions(ctx context.Context, bktInfo *data.BucketInfo,
 	return result, nil
 }
 
-func (c *TreeClient) getSubTree(ctx context.Context, bktInfo *data.BucketInfo, treeID string, rootID uint64, depth uint32) ([]*tree.GetSubTreeResponse_Body, error) {
-	request := &tree.GetSubTreeRequest{
-		Body: &tree.GetSubTreeRequest_Body{
-			ContainerId: bktInfo.CID[:],
-			TreeId:      treeID,
-			RootId:      rootID,
-			Depth:       depth,
-			BearerToken: getBearer(ctx, bktInfo),
-		},
-	}
-
-	if err := c.signRequest(request.Body, func(key, sign []byte) {
-		request.Signature = &tree.Signature{
-			Key:  key,
-			Sign: sign,
-		}
-	}); err != nil {
-		return nil, err
-	}
-
-	cli, err := c.service.GetSubTree(ctx, request)
-	if err != nil {
-		return nil, handleError("failed to get sub tree client", err)
-	}
-
-	var subtree []*tree.GetSubTreeResponse_Body
-	for {
-		resp, err := cli.Recv()
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			return nil, handleError("failed to get sub tree", err)
-		}
-		subtree = append(subtree, resp.Body)
-	}
-
-	return subtree, nil
-}
-
 func metaFromSettings(settings *data.BucketSettings) map[string]string {
-	results := make(map[string]string, 3)
+	results := make(map[string]string, 5)
 
 	results[fileNameKV] = settingsFileName
 	results[versioningKV] = settings.Versioning
 	results[lockConfigurationKV] = encodeLockConfiguration(settings.LockConfiguration)
+	if settings.Quota != nil {
+		results[quotaKV] = encodeQuota(settings.Quota)
+	}
+	if settings.TrackUsage {
+		results[trackUsageKV] = strconv.FormatBool(settings.TrackUsage)
+	}
 
 	return results
 }
 
+// usageFields maps keys of the bucket usage node to the corresponding fields of usage.
+func usageFields(usage *data.BucketUsage) map[string]*uint64 {
+	return map[string]*uint64{
+		usageSizeKV:           &usage.Size,
+		usageObjectsKV:        &usage.Objects,
+		usageCurrentObjectsKV: &usage.CurrentObjects,
+		usageDeleteMarkersKV:  &usage.DeleteMarkers,
+		usageMultipartSizeKV:  &usage.MultipartSize,
+	}
+}
+
 func metaFromMultipart(info *data.MultipartInfo, fileName string) map[string]string {
 	info.Meta[fileNameKV] = fileName
 	info.Meta[uploadIDKV] = info.UploadID
@@ -1197,7 +1389,7 @@ func (c *TreeClient) getSystemNodeWithAllAttributes(ctx context.Context, bktInfo
 }
 
 func (c *TreeClient) getNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, path, meta []string, allAttrs bool) (*TreeNode, error) {
-	p := &getNodesParams{
+	p := &GetNodesParams{
 		BktInfo:    bktInfo,
 		TreeID:     treeID,
 		Path:       path,
@@ -1205,7 +1397,7 @@ func (c *TreeClient) getNode(ctx context.Context, bktInfo *data.BucketInfo, tree
 		LatestOnly: false,
 		AllAttrs:   allAttrs,
 	}
-	nodes, err := c.getNodes(ctx, p)
+	nodes, err := c.service.GetNodes(ctx, p)
 	if err != nil {
 		return nil, err
 	}
@@ -1219,184 +1411,6 @@ func (c *TreeClient) getNode(ctx context.Context, bktInfo *data.BucketInfo, tree
 	return newTreeNode(nodes[0])
 }
 
-func (c *TreeClient) getNodes(ctx context.Context, p *getNodesParams) ([]*tree.GetNodeByPathResponse_Info, error) {
-	request := &tree.GetNodeByPathRequest{
-		Body: &tree.GetNodeByPathRequest_Body{
-			ContainerId:   p.BktInfo.CID[:],
-			TreeId:        p.TreeID,
-			Path:          p.Path,
-			Attributes:    p.Meta,
-			PathAttribute: fileNameKV,
-			LatestOnly:    p.LatestOnly,
-			AllAttributes: p.AllAttrs,
-			BearerToken:   getBearer(ctx, p.BktInfo),
-		},
-	}
-
-	if err := c.signRequest(request.Body, func(key, sign []byte) {
-		request.Signature = &tree.Signature{
-			Key:  key,
-			Sign: sign,
-		}
-	}); err != nil {
-		return nil, err
-	}
-
-	resp, err := c.service.GetNodeByPath(ctx, request)
-	if err != nil {
-		return nil, handleError("failed to get node by path", err)
-	}
-
-	return resp.GetBody().GetNodes(), nil
-}
-
-func handleError(msg string, err error) error {
-	if strings.Contains(err.Error(), "not found") {
-		return fmt.Errorf("%w: %s", layer.ErrNodeNotFound, err.Error())
-	} else if strings.Contains(err.Error(), "is denied by") {
-		return fmt.Errorf("%w: %s", layer.ErrNodeAccessDenied, err.Error())
-	}
-	return fmt.Errorf("%s: %w", msg, err)
-}
-
-func getBearer(ctx context.Context, bktInfo *data.BucketInfo) []byte {
-	if bd, ok := ctx.Value(api.BoxData).(*accessbox.Box); ok && bd != nil && bd.Gate != nil {
-		if bd.Gate.BearerToken != nil {
-			if bktInfo.Owner.Equals(bearer.ResolveIssuer(*bd.Gate.BearerToken)) {
-				return bd.Gate.BearerToken.Marshal()
-			}
-		}
-	}
-	return nil
-}
-
-func (c *TreeClient) addNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, parent uint64, meta map[string]string) (uint64, error) {
-	request := &tree.AddRequest{
-		Body: &tree.AddRequest_Body{
-			ContainerId: bktInfo.CID[:],
-			TreeId:      treeID,
-			ParentId:    parent,
-			Meta:        metaToKV(meta),
-			BearerToken: getBearer(ctx, bktInfo),
-		},
-	}
-	if err := c.signRequest(request.Body, func(key, sign []byte) {
-		request.Signature = &tree.Signature{
-			Key:  key,
-			Sign: sign,
-		}
-	}); err != nil {
-		return 0, err
-	}
-
-	resp, err := c.service.Add(ctx, request)
-	if err != nil {
-		return 0, handleError("failed to add node", err)
-	}
-
-	return resp.GetBody().GetNodeId(), nil
-}
-
-func (c *TreeClient) addNodeByPath(ctx context.Context, bktInfo *data.BucketInfo, treeID string, path []string, meta map[string]string) (uint64, error) {
-	request := &tree.AddByPathRequest{
-		Body: &tree.AddByPathRequest_Body{
-			ContainerId:   bktInfo.CID[:],
-			TreeId:        treeID,
-			Path:          path,
-			Meta:          metaToKV(meta),
-			PathAttribute: fileNameKV,
-			BearerToken:   getBearer(ctx, bktInfo),
-		},
-	}
-
-	if err := c.signRequest(request.Body, func(key, sign []byte) {
-		request.Signature = &tree.Signature{
-			Key:  key,
-			Sign: sign,
-		}
-	}); err != nil {
-		return 0, err
-	}
-
-	resp, err := c.service.AddByPath(ctx, request)
-	if err != nil {
-		return 0, handleError("failed to add node by path", err)
-	}
-
-	body := resp.GetBody()
-	if body == nil {
-		return 0, errors.New("nil body in tree service response")
-	} else if len(body.Nodes) == 0 {
-		return 0, errors.New("empty list of added nodes in tree service response")
-	}
-
-	// The first node is the leaf that we add, according to tree service docs.
-	return body.Nodes[0], nil
-}
-
-func (c *TreeClient) moveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID, parentID uint64, meta map[string]string) error {
-	request := &tree.MoveRequest{
-		Body: &tree.MoveRequest_Body{
-			ContainerId: bktInfo.CID[:],
-			TreeId:      treeID,
-			NodeId:      nodeID,
-			ParentId:    parentID,
-			Meta:        metaToKV(meta),
-			BearerToken: getBearer(ctx, bktInfo),
-		},
-	}
-
-	if err := c.signRequest(request.Body, func(key, sign []byte) {
-		request.Signature = &tree.Signature{
-			Key:  key,
-			Sign: sign,
-		}
-	}); err != nil {
-		return err
-	}
-
-	if _, err := c.service.Move(ctx, request); err != nil {
-		return handleError("failed to move node", err)
-	}
-
-	return nil
-}
-
-func (c *TreeClient) removeNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error {
-	request := &tree.RemoveRequest{
-		Body: &tree.RemoveRequest_Body{
-			ContainerId: bktInfo.CID[:],
-			TreeId:      treeID,
-			NodeId:      nodeID,
-			BearerToken: getBearer(ctx, bktInfo),
-		},
-	}
-	if err := c.signRequest(request.Body, func(key, sign []byte) {
-		request.Signature = &tree.Signature{
-			Key:  key,
-			Sign: sign,
-		}
-	}); err != nil {
-		return err
-	}
-
-	if _, err := c.service.Remove(ctx, request); err != nil {
-		return handleError("failed to remove node", err)
-	}
-
-	return nil
-}
-
-func metaToKV(meta map[string]string) []*tree.KeyValue {
-	result := make([]*tree.KeyValue, 0, len(meta))
-
-	for key, value := range meta {
-		result = append(result, &tree.KeyValue{Key: key, Value: []byte(value)})
-	}
-
-	return result
-}
-
 func parseLockConfiguration(value string) (*data.ObjectLockConfiguration, error) {
 	result := &data.ObjectLockConfiguration{}
 	if len(value) == 0 {
@@ -1452,3 +1466,29 @@ func encodeLockConfiguration(conf *data.ObjectLockConfiguration) string {
 	defaults := conf.Rule.DefaultRetention
 	return fmt.Sprintf("%s,%d,%s,%d", conf.ObjectLockEnabled, defaults.Days, defaults.Mode, defaults.Years)
 }
+
+func parseQuota(value string) (*data.BucketQuota, error) {
+	quotaValues := strings.Split(value, ",")
+	if len(quotaValues) != 4 {
+		return nil, fmt.Errorf("invalid quota: %s", value)
+	}
+
+	limits := make([]uint64, len(quotaValues))
+	for i, val := range quotaValues {
+		var err error
+		if limits[i], err = strconv.ParseUint(val, 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid quota: %s", value)
+		}
+	}
+
+	return &data.BucketQuota{
+		MaxSize:        limits[0],
+		MaxObjects:     limits[1],
+		SoftMaxSize:    limits[2],
+		SoftMaxObjects: limits[3],
+	}, nil
+}
+
+func encodeQuota(quota *data.BucketQuota) string {
+	return fmt.Sprintf("%d,%d,%d,%d", quota.MaxSize, quota.MaxObjects, quota.SoftMaxSize, quota.SoftMaxObjects)
+}
@@ -0,0 +1,293 @@
+package neofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/creds/accessbox"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs/services/tree"
+	"github.com/nspcc-dev/neofs-sdk-go/bearer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// ServiceClientGRPC is a ServiceClient working with the tree service of NeoFS storage nodes over gRPC.
+type ServiceClientGRPC struct {
+	key     *keys.PrivateKey
+	conn    *grpc.ClientConn
+	service tree.TreeServiceClient
+}
+
+// NewServiceClientGRPC creates instance of ServiceClientGRPC using provided address and create grpc connection.
+func NewServiceClientGRPC(ctx context.Context, addr string, key *keys.PrivateKey) (*ServiceClientGRPC, error) {
+	c, err := dialServiceClientGRPC(addr, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.Healthcheck(ctx); err != nil {
+		_ = c.Close()
+		return nil, fmt.Errorf("healthcheck: %w", err)
+	}
+
+	return c, nil
+}
+
+// dialServiceClientGRPC creates instance of ServiceClientGRPC without checking the endpoint health.
+func dialServiceClientGRPC(addr string, key *keys.PrivateKey) (*ServiceClientGRPC, error) {
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("did not connect: %v", err)
+	}
+
+	return &ServiceClientGRPC{
+		key:     key,
+		conn:    conn,
+		service: tree.NewTreeServiceClient(conn),
+	}, nil
+}
+
+// Healthcheck checks that the tree service of the node is available.
+func (c *ServiceClientGRPC) Healthcheck(ctx context.Context) error {
+	_, err := c.service.Healthcheck(ctx, &tree.HealthcheckRequest{})
+	return err
+}
+
+// Close closes the underlying grpc connection.
+func (c *ServiceClientGRPC) Close() error {
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+
+	return nil
+}
+
+func (c *ServiceClientGRPC) GetSubTree(ctx context.Context, bktInfo *data.BucketInfo, treeID string, rootID uint64, depth uint32) ([]NodeResponse, error) {
+	request := &tree.GetSubTreeRequest{
+		Body: &tree.GetSubTreeRequest_Body{
+			ContainerId: bktInfo.CID[:],
+			TreeId:      treeID,
+			RootId:      rootID,
+			Depth:       depth,
+			BearerToken: getBearer(ctx, bktInfo),
+		},
+	}
+
+	if err := c.signRequest(request.Body, func(key, sign []byte) {
+		request.Signature = &tree.Signature{
+			Key:  key,
+			Sign: sign,
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	cli, err := c.service.GetSubTree(ctx, request)
+	if err != nil {
+		return nil, handleError("failed to get sub tree client", err)
+	}
+
+	var subtree []NodeResponse
+	for {
+		resp, err := cli.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, handleError("failed to get sub tree", err)
+		}
+		subtree = append(subtree, resp.Body)
+	}
+
+	return subtree, nil
+}
+
+func (c *ServiceClientGRPC) GetNodes(ctx context.Context, p *GetNodesParams) ([]NodeResponse, error) {
+	request := &tree.GetNodeByPathRequest{
+		Body: &tree.GetNodeByPathRequest_Body{
+			ContainerId:   p.BktInfo.CID[:],
+			TreeId:        p.TreeID,
+			Path:          p.Path,
+			Attributes:    p.Meta,
+			PathAttribute: fileNameKV,
+			LatestOnly:    p.LatestOnly,
+			AllAttributes: p.AllAttrs,
+			BearerToken:   getBearer(ctx, p.BktInfo),
+		},
+	}
+
+	if err := c.signRequest(request.Body, func(key, sign []byte) {
+		request.Signature = &tree.Signature{
+			Key:  key,
+			Sign: sign,
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.service.GetNodeByPath(ctx, request)
+	if err != nil {
+		return nil, handleError("failed to get node by path", err)
+	}
+
+	nodes := resp.GetBody().GetNodes()
+	result := make([]NodeResponse, len(nodes))
+	for i, node := range nodes {
+		result[i] = node
+	}
+
+	return result, nil
+}
+
+func handleError(msg string, err error) error {
+	if strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("%w: %s", layer.ErrNodeNotFound, err.Error())
+	} else if strings.Contains(err.Error(), "is denied by") {
+		return fmt.Errorf("%w: %s", layer.ErrNodeAccessDenied, err.Error())
+	}
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+func getBearer(ctx context.Context, bktInfo *data.BucketInfo) []byte {
+	if bd, ok := ctx.Value(api.BoxData).(*accessbox.Box); ok && bd != nil && bd.Gate != nil {
+		if bd.Gate.BearerToken != nil {
+			if bktInfo.Owner.Equals(bearer.ResolveIssuer(*bd.Gate.BearerToken)) {
+				return bd.Gate.BearerToken.Marshal()
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ServiceClientGRPC) AddNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, parent uint64, meta map[string]string) (uint64, error) {
+	request := &tree.AddRequest{
+		Body: &tree.AddRequest_Body{
+			ContainerId: bktInfo.CID[:],
+			TreeId:      treeID,
+			ParentId:    parent,
+			Meta:        metaToKV(meta),
+			BearerToken: getBearer(ctx, bktInfo),
+		},
+	}
+	if err := c.signRequest(request.Body, func(key, sign []byte) {
+		request.Signature = &tree.Signature{
+			Key:  key,
+			Sign: sign,
+		}
+	}); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.service.Add(ctx, request)
+	if err != nil {
+		return 0, handleError("failed to add node", err)
+	}
+
+	return resp.GetBody().GetNodeId(), nil
+}
+
+func (c *ServiceClientGRPC) AddNodeByPath(ctx context.Context, bktInfo *data.BucketInfo, treeID string, path []string, meta map[string]string) (uint64, error) {
+	request := &tree.AddByPathRequest{
+		Body: &tree.AddByPathRequest_Body{
+			ContainerId:   bktInfo.CID[:],
+			TreeId:        treeID,
+			Path:          path,
+			Meta:          metaToKV(meta),
+			PathAttribute: fileNameKV,
+			BearerToken:   getBearer(ctx, bktInfo),
+		},
+	}
+
+	if err := c.signRequest(request.Body, func(key, sign []byte) {
+		request.Signature = &tree.Signature{
+			Key:  key,
+			Sign: sign,
+		}
+	}); err != nil {
+		return 0, err
+	}
+
+	resp, err := c.service.AddByPath(ctx, request)
+	if err != nil {
+		return 0, handleError("failed to add node by path", err)
+	}
+
+	body := resp.GetBody()
+	if body == nil {
+		return 0, errors.New("nil body in tree service response")
+	} else if len(body.Nodes) == 0 {
+		return 0, errors.New("empty list of added nodes in tree service response")
+	}
+
+	// The first node is the leaf that we add, according to tree service docs.
+	return body.Nodes[0], nil
+}
+
+func (c *ServiceClientGRPC) MoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID, parentID uint64, meta map[string]string) error {
+	request := &tree.MoveRequest{
+		Body: &tree.MoveRequest_Body{
+			ContainerId: bktInfo.CID[:],
+			TreeId:      treeID,
+			NodeId:      nodeID,
+			ParentId:    parentID,
+			Meta:        metaToKV(meta),
+			BearerToken: getBearer(ctx, bktInfo),
+		},
+	}
+
+	if err := c.signRequest(request.Body, func(key, sign []byte) {
+		request.Signature = &tree.Signature{
+			Key:  key,
+			Sign: sign,
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := c.service.Move(ctx, request); err != nil {
+		return handleError("failed to move node", err)
+	}
+
+	return nil
+}
+
+func (c *ServiceClientGRPC) RemoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error {
+	request := &tree.RemoveRequest{
+		Body: &tree.RemoveRequest_Body{
+			ContainerId: bktInfo.CID[:],
+			TreeId:      treeID,
+			NodeId:      nodeID,
+			BearerToken: getBearer(ctx, bktInfo),
+		},
+	}
+	if err := c.signRequest(request.Body, func(key, sign []byte) {
+		request.Signature = &tree.Signature{
+			Key:  key,
+			Sign: sign,
+		}
+	}); err != nil {
+		return err
+	}
+
+	if _, err := c.service.Remove(ctx, request); err != nil {
+		return handleError("failed to remove node", err)
+	}
+
+	return nil
+}
+
+func metaToKV(meta map[string]string) []*tree.KeyValue {
+	result := make([]*tree.KeyValue, 0, len(meta))
+
+	for key, value := range meta {
+		result = append(result, &tree.KeyValue{Key: key, Value: []byte(value)})
+	}
+
+	return result
+}
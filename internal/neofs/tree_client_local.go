@@ -0,0 +1,487 @@
+package neofs
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/neofs/services/tree"
+	"go.etcd.io/bbolt"
+)
+
+// ServiceClientLocal is a ServiceClient storing trees in a local bbolt database.
+// It follows the semantics of the tree service of NeoFS storage nodes but
+// doesn't check access rights, so it's intended for development and single-node deployments.
+type ServiceClientLocal struct {
+	db *bbolt.DB
+}
+
+type (
+	localNode struct {
+		Parent    uint64    `json:"p"`
+		Timestamp uint64    `json:"t"`
+		Meta      []localKV `json:"m"`
+	}
+
+	localKV struct {
+		Key   string `json:"k"`
+		Value []byte `json:"v"`
+	}
+)
+
+// Key prefixes inside a tree bucket.
+const (
+	// localNodePrefix + nodeID -> encoded localNode.
+	localNodePrefix byte = 'n'
+	// localChildPrefix + parentID + childID -> nil.
+	localChildPrefix byte = 'c'
+	// localNamePrefix + parentID + len(FileName) + FileName + childID -> nil.
+	localNamePrefix byte = 'a'
+)
+
+var errLocalTreeNotFound = fmt.Errorf("%w: tree not found", layer.ErrNodeNotFound)
+
+// NewServiceClientLocal opens (or creates) bbolt database by the provided path.
+func NewServiceClientLocal(path string) (*ServiceClientLocal, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open tree database: %w", err)
+	}
+
+	return &ServiceClientLocal{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (c *ServiceClientLocal) Close() error {
+	return c.db.Close()
+}
+
+func (c *ServiceClientLocal) GetNodes(_ context.Context, p *GetNodesParams) ([]NodeResponse, error) {
+	var result []NodeResponse
+
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		b := getTreeBucket(tx, p.BktInfo, p.TreeID)
+		if b == nil {
+			return errLocalTreeNotFound
+		}
+		if len(p.Path) == 0 {
+			return nil
+		}
+
+		parentID, ok, err := findPath(b, p.Path[:len(p.Path)-1])
+		if err != nil || !ok {
+			return err
+		}
+
+		ids := childrenByName(b, parentID, p.Path[len(p.Path)-1])
+		nodes := make([]*tree.GetNodeByPathResponse_Info, 0, len(ids))
+		for _, id := range ids {
+			node, err := getLocalNode(b, id)
+			if err != nil {
+				return err
+			}
+
+			info := &tree.GetNodeByPathResponse_Info{
+				NodeId:    id,
+				ParentId:  node.Parent,
+				Timestamp: node.Timestamp,
+				Meta:      filterMeta(node.Meta, p.Meta, p.AllAttrs),
+			}
+
+			if p.LatestOnly {
+				if len(nodes) == 0 {
+					nodes = append(nodes, info)
+				} else if info.Timestamp > nodes[0].Timestamp {
+					nodes[0] = info
+				}
+				continue
+			}
+			nodes = append(nodes, info)
+		}
+
+		for _, node := range nodes {
+			result = append(result, node)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+func (c *ServiceClientLocal) GetSubTree(_ context.Context, bktInfo *data.BucketInfo, treeID string, rootID uint64, depth uint32) ([]NodeResponse, error) {
+	var result []NodeResponse
+
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		b := getTreeBucket(tx, bktInfo, treeID)
+		if b == nil {
+			return errLocalTreeNotFound
+		}
+
+		var walk func(id uint64, level uint32) error
+		walk = func(id uint64, level uint32) error {
+			node, err := getLocalNode(b, id)
+			if err != nil {
+				return err
+			}
+
+			result = append(result, &tree.GetSubTreeResponse_Body{
+				NodeId:    id,
+				ParentId:  node.Parent,
+				Timestamp: node.Timestamp,
+				Meta:      filterMeta(node.Meta, nil, true),
+			})
+
+			if depth != maxGetSubTreeDepth && level+1 >= depth {
+				return nil
+			}
+
+			for _, child := range children(b, id) {
+				if err = walk(child, level+1); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		return walk(rootID, 0)
+	})
+
+	return result, err
+}
+
+func (c *ServiceClientLocal) AddNode(_ context.Context, bktInfo *data.BucketInfo, treeID string, parent uint64, meta map[string]string) (uint64, error) {
+	var nodeID uint64
+
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		b, err := createTreeBucket(tx, bktInfo, treeID)
+		if err != nil {
+			return err
+		}
+
+		if _, err = getLocalNode(b, parent); err != nil {
+			return err
+		}
+
+		nodeID, err = addLocalNode(b, parent, mapToLocalMeta(meta))
+		return err
+	})
+
+	return nodeID, err
+}
+
+func (c *ServiceClientLocal) AddNodeByPath(_ context.Context, bktInfo *data.BucketInfo, treeID string, path []string, meta map[string]string) (uint64, error) {
+	var nodeID uint64
+
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		b, err := createTreeBucket(tx, bktInfo, treeID)
+		if err != nil {
+			return err
+		}
+
+		var parentID uint64
+		for _, name := range path {
+			id, ok, err := findIntermediate(b, parentID, name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				if id, err = addLocalNode(b, parentID, []localKV{{Key: fileNameKV, Value: []byte(name)}}); err != nil {
+					return err
+				}
+			}
+			parentID = id
+		}
+
+		nodeID, err = addLocalNode(b, parentID, mapToLocalMeta(meta))
+		return err
+	})
+
+	return nodeID, err
+}
+
+func (c *ServiceClientLocal) MoveNode(_ context.Context, bktInfo *data.BucketInfo, treeID string, nodeID, parentID uint64, meta map[string]string) error {
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		b := getTreeBucket(tx, bktInfo, treeID)
+		if b == nil {
+			return errLocalTreeNotFound
+		}
+		if nodeID == 0 {
+			return errors.New("root node can't be moved")
+		}
+
+		node, err := getLocalNode(b, nodeID)
+		if err != nil {
+			return err
+		}
+		if _, err = getLocalNode(b, parentID); err != nil {
+			return err
+		}
+		for id := parentID; id != 0; {
+			if id == nodeID {
+				return errors.New("node can't be moved to its own subtree")
+			}
+			parent, err := getLocalNode(b, id)
+			if err != nil {
+				return err
+			}
+			id = parent.Parent
+		}
+
+		if err = unlinkLocalNode(b, nodeID, node); err != nil {
+			return err
+		}
+
+		ts, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		return putLocalNode(b, nodeID, &localNode{
+			Parent:    parentID,
+			Timestamp: ts,
+			Meta:      mapToLocalMeta(meta),
+		})
+	})
+}
+
+func (c *ServiceClientLocal) RemoveNode(_ context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error {
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		b := getTreeBucket(tx, bktInfo, treeID)
+		if b == nil {
+			return errLocalTreeNotFound
+		}
+		if nodeID == 0 {
+			return errors.New("root node can't be removed")
+		}
+
+		node, err := getLocalNode(b, nodeID)
+		if err != nil {
+			return err
+		}
+		if err = unlinkLocalNode(b, nodeID, node); err != nil {
+			return err
+		}
+
+		return removeLocalSubTree(b, nodeID)
+	})
+}
+
+func getTreeBucket(tx *bbolt.Tx, bktInfo *data.BucketInfo, treeID string) *bbolt.Bucket {
+	cnr := tx.Bucket(bktInfo.CID[:])
+	if cnr == nil {
+		return nil
+	}
+	return cnr.Bucket([]byte(treeID))
+}
+
+func createTreeBucket(tx *bbolt.Tx, bktInfo *data.BucketInfo, treeID string) (*bbolt.Bucket, error) {
+	cnr, err := tx.CreateBucketIfNotExists(bktInfo.CID[:])
+	if err != nil {
+		return nil, fmt.Errorf("create container bucket: %w", err)
+	}
+
+	b, err := cnr.CreateBucketIfNotExists([]byte(treeID))
+	if err != nil {
+		return nil, fmt.Errorf("create tree bucket: %w", err)
+	}
+	return b, nil
+}
+
+// getLocalNode returns node by its id. Root node always exists and has no meta.
+func getLocalNode(b *bbolt.Bucket, id uint64) (*localNode, error) {
+	if id == 0 {
+		return &localNode{}, nil
+	}
+
+	raw := b.Get(nodeKey(id))
+	if raw == nil {
+		return nil, fmt.Errorf("%w: node %d", layer.ErrNodeNotFound, id)
+	}
+
+	node := new(localNode)
+	if err := json.Unmarshal(raw, node); err != nil {
+		return nil, fmt.Errorf("decode node %d: %w", id, err)
+	}
+	return node, nil
+}
+
+func addLocalNode(b *bbolt.Bucket, parent uint64, meta []localKV) (uint64, error) {
+	id, err := b.NextSequence()
+	if err != nil {
+		return 0, err
+	}
+
+	return id, putLocalNode(b, id, &localNode{
+		Parent:    parent,
+		Timestamp: id,
+		Meta:      meta,
+	})
+}
+
+func putLocalNode(b *bbolt.Bucket, id uint64, node *localNode) error {
+	raw, err := json.Marshal(node)
+	if err != nil {
+		return fmt.Errorf("encode node %d: %w", id, err)
+	}
+
+	if err = b.Put(nodeKey(id), raw); err != nil {
+		return err
+	}
+	if err = b.Put(childKey(node.Parent, id), nil); err != nil {
+		return err
+	}
+	if name, ok := localFileName(node.Meta); ok {
+		return b.Put(nameKey(node.Parent, name, id), nil)
+	}
+	return nil
+}
+
+// unlinkLocalNode removes node from the indexes of its parent.
+func unlinkLocalNode(b *bbolt.Bucket, id uint64, node *localNode) error {
+	if err := b.Delete(childKey(node.Parent, id)); err != nil {
+		return err
+	}
+	if name, ok := localFileName(node.Meta); ok {
+		return b.Delete(nameKey(node.Parent, name, id))
+	}
+	return nil
+}
+
+func removeLocalSubTree(b *bbolt.Bucket, id uint64) error {
+	for _, child := range children(b, id) {
+		node, err := getLocalNode(b, child)
+		if err != nil {
+			return err
+		}
+		if err = unlinkLocalNode(b, child, node); err != nil {
+			return err
+		}
+		if err = removeLocalSubTree(b, child); err != nil {
+			return err
+		}
+	}
+
+	return b.Delete(nodeKey(id))
+}
+
+// findPath walks through intermediate nodes and returns id of the last one.
+func findPath(b *bbolt.Bucket, path []string) (uint64, bool, error) {
+	var id uint64
+	for _, name := range path {
+		next, ok, err := findIntermediate(b, id, name)
+		if err != nil || !ok {
+			return 0, false, err
+		}
+		id = next
+	}
+	return id, true, nil
+}
+
+func findIntermediate(b *bbolt.Bucket, parent uint64, name string) (uint64, bool, error) {
+	for _, id := range childrenByName(b, parent, name) {
+		node, err := getLocalNode(b, id)
+		if err != nil {
+			return 0, false, err
+		}
+		if len(node.Meta) == 1 && node.Meta[0].Key == fileNameKV {
+			return id, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func children(b *bbolt.Bucket, parent uint64) []uint64 {
+	return scanIDs(b, childKey(parent, 0)[:9])
+}
+
+func childrenByName(b *bbolt.Bucket, parent uint64, name string) []uint64 {
+	key := nameKey(parent, name, 0)
+	return scanIDs(b, key[:len(key)-8])
+}
+
+// scanIDs returns ids stored in the last 8 bytes of keys with the provided prefix.
+func scanIDs(b *bbolt.Bucket, prefix []byte) []uint64 {
+	var ids []uint64
+
+	cur := b.Cursor()
+	for k, _ := cur.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cur.Next() {
+		if len(k) == len(prefix)+8 {
+			ids = append(ids, binary.BigEndian.Uint64(k[len(prefix):]))
+		}
+	}
+	return ids
+}
+
+func nodeKey(id uint64) []byte {
+	key := make([]byte, 9)
+	key[0] = localNodePrefix
+	binary.BigEndian.PutUint64(key[1:], id)
+	return key
+}
+
+func childKey(parent, child uint64) []byte {
+	key := make([]byte, 17)
+	key[0] = localChildPrefix
+	binary.BigEndian.PutUint64(key[1:], parent)
+	binary.BigEndian.PutUint64(key[9:], child)
+	return key
+}
+
+func nameKey(parent uint64, name string, child uint64) []byte {
+	key := make([]byte, 13+len(name)+8)
+	key[0] = localNamePrefix
+	binary.BigEndian.PutUint64(key[1:], parent)
+	binary.BigEndian.PutUint32(key[9:], uint32(len(name)))
+	copy(key[13:], name)
+	binary.BigEndian.PutUint64(key[13+len(name):], child)
+	return key
+}
+
+func localFileName(meta []localKV) (string, bool) {
+	for _, kv := range meta {
+		if kv.Key == fileNameKV {
+			return string(kv.Value), true
+		}
+	}
+	return "", false
+}
+
+func mapToLocalMeta(meta map[string]string) []localKV {
+	result := make([]localKV, 0, len(meta))
+	for key, value := range meta {
+		result = append(result, localKV{Key: key, Value: []byte(value)})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result
+}
+
+func filterMeta(meta []localKV, attrs []string, all bool) []*tree.KeyValue {
+	result := make([]*tree.KeyValue, 0, len(meta))
+	for _, kv := range meta {
+		if !all && !containsString(attrs, kv.Key) {
+			continue
+		}
+		result = append(result, &tree.KeyValue{Key: kv.Key, Value: kv.Value})
+	}
+	return result
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
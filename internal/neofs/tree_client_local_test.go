@@ -0,0 +1,451 @@
+package neofs
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/tracing"
+	cidtest "github.com/nspcc-dev/neofs-sdk-go/container/id/test"
+	oidtest "github.com/nspcc-dev/neofs-sdk-go/object/id/test"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newLocalTreeClient(t *testing.T) (*TreeClient, string) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+	service, err := NewServiceClientLocal(path)
+	require.NoError(t, err)
+
+	c := NewTreeClientWithService(service)
+	t.Cleanup(func() { _ = c.Close() })
+	return c, path
+}
+
+func TestLocalTreeVersions(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	_, err := c.GetLatestVersion(ctx, bktInfo, "obj")
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+
+	add := func(name string, unversioned bool) *data.NodeVersion {
+		version := &data.NodeVersion{
+			BaseNodeVersion: data.BaseNodeVersion{OID: oidtest.ID(), FilePath: name, Size: 10},
+			IsUnversioned:   unversioned,
+		}
+		version.ID, err = c.AddVersion(ctx, bktInfo, version)
+		require.NoError(t, err)
+		return version
+	}
+
+	v1 := add("dir/obj", true)
+	v2 := add("dir/obj", true)
+	require.Equal(t, v1.ID, v2.ID, "unversioned version must replace the previous one")
+
+	v3 := add("dir/obj", false)
+	add("dir", false)
+	add("dir/sub/other", false)
+
+	versions, err := c.GetVersions(ctx, bktInfo, "dir/obj")
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+
+	latest, err := c.GetLatestVersion(ctx, bktInfo, "dir/obj")
+	require.NoError(t, err)
+	require.Equal(t, v3.OID, latest.OID)
+	require.Equal(t, int64(10), latest.Size)
+
+	unversioned, err := c.GetUnversioned(ctx, bktInfo, "dir/obj")
+	require.NoError(t, err)
+	require.Equal(t, v2.OID, unversioned.OID)
+
+	list, err := c.GetLatestVersionsByPrefix(ctx, bktInfo, "dir/")
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+
+	list, err = c.GetAllVersionsByPrefix(ctx, bktInfo, "di")
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+
+	require.NoError(t, c.RemoveVersion(ctx, bktInfo, v3.ID))
+	latest, err = c.GetLatestVersion(ctx, bktInfo, "dir/obj")
+	require.NoError(t, err)
+	require.Equal(t, v2.OID, latest.OID)
+
+	_, err = c.GetLatestVersion(ctx, &data.BucketInfo{CID: cidtest.ID()}, "dir/obj")
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+}
+
+func TestLocalTreeSystem(t *testing.T) {
+	ctx := context.Background()
+	c, path := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	_, err := c.GetSettingsNode(ctx, bktInfo)
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+
+	settings := &data.BucketSettings{Versioning: data.VersioningEnabled}
+	require.NoError(t, c.PutSettingsNode(ctx, bktInfo, settings))
+	settings.Versioning = data.VersioningSuspended
+	require.NoError(t, c.PutSettingsNode(ctx, bktInfo, settings))
+
+	_, err = c.DeleteBucketCORS(ctx, bktInfo)
+	require.ErrorIs(t, err, layer.ErrNoNodeToRemove)
+
+	corsID := oidtest.ID()
+	_, err = c.PutBucketCORS(ctx, bktInfo, corsID)
+	require.ErrorIs(t, err, layer.ErrNoNodeToRemove)
+	oldID, err := c.PutBucketCORS(ctx, bktInfo, oidtest.ID())
+	require.NoError(t, err)
+	require.Equal(t, corsID, oldID)
+
+	tags := map[string]string{"key": "value"}
+	require.NoError(t, c.PutBucketTagging(ctx, bktInfo, tags))
+
+	// reopen database to check persistence
+	require.NoError(t, c.Close())
+	service, err := NewServiceClientLocal(path)
+	require.NoError(t, err)
+	c = NewTreeClientWithService(service)
+	defer c.Close()
+
+	res, err := c.GetSettingsNode(ctx, bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, data.VersioningSuspended, res.Versioning)
+
+	resTags, err := c.GetBucketTagging(ctx, bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, tags, resTags)
+
+	require.NoError(t, c.DeleteBucketTagging(ctx, bktInfo))
+	_, err = c.GetBucketTagging(ctx, bktInfo)
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+}
+
+func TestLocalTreeMultipart(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	info := &data.MultipartInfo{
+		Key:      "dir/obj",
+		UploadID: "upload",
+		Created:  time.Now(),
+		Meta:     map[string]string{"foo": "bar"},
+	}
+	require.NoError(t, c.CreateMultipartUpload(ctx, bktInfo, info))
+
+	upload, err := c.GetMultipartUpload(ctx, bktInfo, "dir/obj", "upload")
+	require.NoError(t, err)
+	require.Equal(t, "bar", upload.Meta["foo"])
+
+	part := &data.PartInfo{Key: "dir/obj", UploadID: "upload", Number: 1, OID: oidtest.ID(), Size: 5, Created: time.Now()}
+	_, err = c.AddPart(ctx, bktInfo, upload.ID, part)
+	require.ErrorIs(t, err, layer.ErrNoNodeToRemove)
+
+	parts, err := c.GetParts(ctx, bktInfo, upload.ID)
+	require.NoError(t, err)
+	require.Len(t, parts, 1)
+	require.Equal(t, part.OID, parts[0].OID)
+	require.Equal(t, part.Size, parts[0].Size)
+
+	uploads, err := c.GetMultipartUploadsByPrefix(ctx, bktInfo, "dir/")
+	require.NoError(t, err)
+	require.Len(t, uploads, 1)
+
+	require.NoError(t, c.DeleteMultipartUpload(ctx, bktInfo, upload.ID))
+	_, err = c.GetMultipartUpload(ctx, bktInfo, "dir/obj", "upload")
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+}
+
+func TestLocalTreeLockAndTagging(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	version := &data.NodeVersion{BaseNodeVersion: data.BaseNodeVersion{OID: oidtest.ID(), FilePath: "obj"}}
+	var err error
+	version.ID, err = c.AddVersion(ctx, bktInfo, version)
+	require.NoError(t, err)
+
+	lock := data.NewLockInfo(0)
+	lock.SetLegalHold(oidtest.ID())
+	require.NoError(t, c.PutLock(ctx, bktInfo, version.ID, lock))
+
+	tags := map[string]string{"key": "value"}
+	require.NoError(t, c.PutObjectTagging(ctx, bktInfo, version, tags))
+
+	resTags, resLock, err := c.GetObjectTaggingAndLock(ctx, bktInfo, version)
+	require.NoError(t, err)
+	require.Equal(t, tags, resTags)
+	require.True(t, resLock.IsLegalHoldSet())
+
+	require.NoError(t, c.DeleteObjectTagging(ctx, bktInfo, version))
+	resTags, err = c.GetObjectTagging(ctx, bktInfo, version)
+	require.NoError(t, err)
+	require.Empty(t, resTags)
+
+	// lock and tagging nodes are removed along with the version
+	require.NoError(t, c.RemoveVersion(ctx, bktInfo, version.ID))
+	_, err = c.GetLock(ctx, bktInfo, version.ID)
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+}
+
+func TestLocalTreeAddVersionWithTagging(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	add := func(unversioned bool, tags map[string]string) *data.NodeVersion {
+		version := &data.NodeVersion{
+			BaseNodeVersion: data.BaseNodeVersion{OID: oidtest.ID(), FilePath: "obj"},
+			IsUnversioned:   unversioned,
+		}
+		var err error
+		version.ID, err = c.AddVersionWithTagging(ctx, bktInfo, version, tags)
+		require.NoError(t, err)
+		return version
+	}
+	checkTags := func(version *data.NodeVersion, expected map[string]string) {
+		tags, err := c.GetObjectTagging(ctx, bktInfo, version)
+		require.NoError(t, err)
+		if len(expected) == 0 {
+			require.Empty(t, tags)
+			return
+		}
+		require.Equal(t, expected, tags)
+	}
+
+	tags := map[string]string{"key": "value"}
+	versioned := add(false, tags)
+	checkTags(versioned, tags)
+
+	// unversioned node is moved in place, its tags are replaced or removed
+	unversioned := add(true, tags)
+	checkTags(unversioned, tags)
+
+	newTags := map[string]string{"key": "new value", "other": "value"}
+	replaced := add(true, newTags)
+	require.Equal(t, unversioned.ID, replaced.ID)
+	checkTags(replaced, newTags)
+
+	replaced = add(true, nil)
+	require.Equal(t, unversioned.ID, replaced.ID)
+	checkTags(replaced, nil)
+
+	checkTags(versioned, tags)
+}
+
+func TestLocalTreeImportCursor(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	_, err := c.GetImportCursor(ctx, bktInfo)
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+
+	for _, epoch := range []uint64{10, 12} {
+		require.NoError(t, c.PutImportCursor(ctx, bktInfo, epoch))
+		stored, err := c.GetImportCursor(ctx, bktInfo)
+		require.NoError(t, err)
+		require.Equal(t, epoch, stored)
+	}
+
+	// the cursor doesn't replace other system nodes
+	require.NoError(t, c.PutSettingsNode(ctx, bktInfo, &data.BucketSettings{Versioning: data.VersioningEnabled}))
+	stored, err := c.GetImportCursor(ctx, bktInfo)
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), stored)
+}
+
+func TestLocalTreeGetLatestAndUnversioned(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	_, _, err := c.GetLatestAndUnversioned(ctx, bktInfo, "obj")
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+
+	add := func(unversioned bool) *data.NodeVersion {
+		version := &data.NodeVersion{
+			BaseNodeVersion: data.BaseNodeVersion{OID: oidtest.ID(), FilePath: "obj"},
+			IsUnversioned:   unversioned,
+		}
+		_, err := c.AddVersion(ctx, bktInfo, version)
+		require.NoError(t, err)
+		return version
+	}
+
+	versioned := add(false)
+	latest, unversioned, err := c.GetLatestAndUnversioned(ctx, bktInfo, "obj")
+	require.NoError(t, err)
+	require.Equal(t, versioned.OID, latest.OID)
+	require.Nil(t, unversioned)
+
+	null := add(true)
+	latest, unversioned, err = c.GetLatestAndUnversioned(ctx, bktInfo, "obj")
+	require.NoError(t, err)
+	require.Equal(t, null.OID, latest.OID)
+	require.Equal(t, null.OID, unversioned.OID)
+
+	versioned = add(false)
+	latest, unversioned, err = c.GetLatestAndUnversioned(ctx, bktInfo, "obj")
+	require.NoError(t, err)
+	require.Equal(t, versioned.OID, latest.OID)
+	require.Equal(t, null.OID, unversioned.OID)
+}
+
+func TestLocalTreeAddVersionIfUnchanged(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	newVersion := func() *data.NodeVersion {
+		return &data.NodeVersion{
+			BaseNodeVersion: data.BaseNodeVersion{OID: oidtest.ID(), FilePath: "obj"},
+			IsUnversioned:   true,
+		}
+	}
+
+	first := newVersion()
+	_, err := c.AddVersionIfUnchanged(ctx, bktInfo, first, nil, nil)
+	require.NoError(t, err)
+
+	checked, err := c.GetUnversioned(ctx, bktInfo, "obj")
+	require.NoError(t, err)
+	require.Equal(t, first.OID, checked.OID)
+
+	// node must not exist
+	_, err = c.AddVersionIfUnchanged(ctx, bktInfo, newVersion(), nil, nil)
+	require.ErrorIs(t, err, layer.ErrNodeChanged)
+
+	// node is replaced by another writer after the check
+	concurrent := newVersion()
+	_, err = c.AddVersion(ctx, bktInfo, concurrent)
+	require.NoError(t, err)
+
+	_, err = c.AddVersionIfUnchanged(ctx, bktInfo, newVersion(), nil, checked)
+	require.ErrorIs(t, err, layer.ErrNodeChanged)
+
+	latest, err := c.GetUnversioned(ctx, bktInfo, "obj")
+	require.NoError(t, err)
+	require.Equal(t, concurrent.OID, latest.OID)
+
+	last := newVersion()
+	_, err = c.AddVersionIfUnchanged(ctx, bktInfo, last, nil, latest)
+	require.NoError(t, err)
+
+	latest, err = c.GetUnversioned(ctx, bktInfo, "obj")
+	require.NoError(t, err)
+	require.Equal(t, last.OID, latest.OID)
+}
+
+// failingTaggingService fails to add or update tagging nodes.
+type failingTaggingService struct {
+	ServiceClient
+}
+
+var errTagging = errors.New("tagging failed")
+
+func (f failingTaggingService) AddNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, parent uint64, meta map[string]string) (uint64, error) {
+	if _, ok := meta[isTagKV]; ok {
+		return 0, errTagging
+	}
+	return f.ServiceClient.AddNode(ctx, bktInfo, treeID, parent, meta)
+}
+
+func (f failingTaggingService) MoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID, parentID uint64, meta map[string]string) error {
+	if _, ok := meta[isTagKV]; ok {
+		return errTagging
+	}
+	return f.ServiceClient.MoveNode(ctx, bktInfo, treeID, nodeID, parentID, meta)
+}
+
+// failingLookupService fails to get subtrees, so tagging nodes can't be found.
+type failingLookupService struct {
+	ServiceClient
+}
+
+func (f failingLookupService) GetSubTree(context.Context, *data.BucketInfo, string, uint64, uint32) ([]NodeResponse, error) {
+	return nil, errTagging
+}
+
+func TestLocalTreeAddVersionWithTaggingFailure(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewServiceClientLocal(filepath.Join(t.TempDir(), "tree.db"))
+	require.NoError(t, err)
+	c := NewTreeClientWithService(service)
+	t.Cleanup(func() { _ = c.Close() })
+	failing := NewTreeClientWithService(failingTaggingService{ServiceClient: service})
+	bktInfo := &data.BucketInfo{CID: cidtest.ID()}
+
+	newVersion := func(unversioned bool) *data.NodeVersion {
+		return &data.NodeVersion{
+			BaseNodeVersion: data.BaseNodeVersion{OID: oidtest.ID(), FilePath: "obj"},
+			IsUnversioned:   unversioned,
+		}
+	}
+	tags := map[string]string{"key": "value"}
+
+	// new version without its tags is removed
+	_, err = failing.AddVersionWithTagging(ctx, bktInfo, newVersion(false), tags)
+	require.ErrorIs(t, err, errTagging)
+	require.NotErrorIs(t, err, layer.ErrVersionTagging)
+	_, err = c.GetLatestVersion(ctx, bktInfo, "obj")
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+
+	// replaced unversioned node doesn't keep the tags of the previous object
+	unversioned := newVersion(true)
+	unversioned.ID, err = c.AddVersionWithTagging(ctx, bktInfo, unversioned, tags)
+	require.NoError(t, err)
+
+	// node isn't replaced if its tagging can't be looked up
+	_, err = NewTreeClientWithService(failingLookupService{ServiceClient: service}).AddVersionWithTagging(ctx, bktInfo, newVersion(true), tags)
+	require.ErrorIs(t, err, errTagging)
+
+	latest, err := c.GetLatestVersion(ctx, bktInfo, "obj")
+	require.NoError(t, err)
+	require.Equal(t, unversioned.OID, latest.OID)
+
+	// node is replaced anyway if its tagging can't be updated
+	replaced := newVersion(true)
+	nodeID, err := failing.AddVersionWithTagging(ctx, bktInfo, replaced, map[string]string{"key": "new value"})
+	require.ErrorIs(t, err, layer.ErrVersionTagging)
+	require.Equal(t, unversioned.ID, nodeID)
+
+	latest, err = c.GetLatestVersion(ctx, bktInfo, "obj")
+	require.NoError(t, err)
+	require.Equal(t, replaced.OID, latest.OID)
+	tagSet, err := c.GetObjectTagging(ctx, bktInfo, latest)
+	require.NoError(t, err)
+	require.Empty(t, tagSet)
+}
+
+func TestTreeClientTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracing.SetProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { tracing.SetProvider(trace.NewNoopTracerProvider()) })
+
+	ctx := context.Background()
+	c, _ := newLocalTreeClient(t)
+	bktInfo := &data.BucketInfo{Name: "bucket", CID: cidtest.ID()}
+
+	_, err := c.GetLatestVersion(ctx, bktInfo, "obj")
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	require.Equal(t, "tree.GetNodes", spans[0].Name())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Contains(t, spans[0].Attributes(), tracing.AttrBucket.String("bucket"))
+	require.Contains(t, spans[0].Attributes(), tracing.AttrTree.String(versionTree))
+}
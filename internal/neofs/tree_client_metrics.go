@@ -0,0 +1,75 @@
+package neofs
+
+import (
+	"context"
+	"time"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/metrics"
+)
+
+// Methods of tree service requests reported in metrics.
+const (
+	treeMethodGetNodes      = "get_nodes"
+	treeMethodGetSubTree    = "get_sub_tree"
+	treeMethodAddNode       = "add_node"
+	treeMethodAddNodeByPath = "add_node_by_path"
+	treeMethodMoveNode      = "move_node"
+	treeMethodRemoveNode    = "remove_node"
+)
+
+// metricsEndpointClient is a client of the single tree service endpoint which records
+// duration and failures of every request to the endpoint.
+type metricsEndpointClient struct {
+	treeEndpointClient
+	address string
+}
+
+func (c metricsEndpointClient) observe(method string, start time.Time, err error) {
+	metrics.ObserveTreeRequest(c.address, method, time.Since(start), err != nil && !isTreeLogicalError(err))
+}
+
+func (c metricsEndpointClient) GetNodes(ctx context.Context, p *GetNodesParams) ([]NodeResponse, error) {
+	start := time.Now()
+	res, err := c.treeEndpointClient.GetNodes(ctx, p)
+	c.observe(treeMethodGetNodes, start, err)
+	return res, err
+}
+
+func (c metricsEndpointClient) GetSubTree(ctx context.Context, bktInfo *data.BucketInfo, treeID string, rootID uint64, depth uint32) ([]NodeResponse, error) {
+	start := time.Now()
+	res, err := c.treeEndpointClient.GetSubTree(ctx, bktInfo, treeID, rootID, depth)
+	c.observe(treeMethodGetSubTree, start, err)
+	if err == nil {
+		metrics.ObserveTreeSubTreeSize(c.address, len(res))
+	}
+	return res, err
+}
+
+func (c metricsEndpointClient) AddNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, parent uint64, meta map[string]string) (uint64, error) {
+	start := time.Now()
+	res, err := c.treeEndpointClient.AddNode(ctx, bktInfo, treeID, parent, meta)
+	c.observe(treeMethodAddNode, start, err)
+	return res, err
+}
+
+func (c metricsEndpointClient) AddNodeByPath(ctx context.Context, bktInfo *data.BucketInfo, treeID string, path []string, meta map[string]string) (uint64, error) {
+	start := time.Now()
+	res, err := c.treeEndpointClient.AddNodeByPath(ctx, bktInfo, treeID, path, meta)
+	c.observe(treeMethodAddNodeByPath, start, err)
+	return res, err
+}
+
+func (c metricsEndpointClient) MoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID, parentID uint64, meta map[string]string) error {
+	start := time.Now()
+	err := c.treeEndpointClient.MoveNode(ctx, bktInfo, treeID, nodeID, parentID, meta)
+	c.observe(treeMethodMoveNode, start, err)
+	return err
+}
+
+func (c metricsEndpointClient) RemoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error {
+	start := time.Now()
+	err := c.treeEndpointClient.RemoveNode(ctx, bktInfo, treeID, nodeID)
+	c.observe(treeMethodRemoveNode, start, err)
+	return err
+}
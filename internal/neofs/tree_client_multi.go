@@ -0,0 +1,400 @@
+package neofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TreeEndpoint describes a tree service endpoint of ServiceClientMulti.
+type TreeEndpoint struct {
+	// Address of the tree service.
+	Address string
+
+	// Endpoints with lower priority value are used first.
+	Priority int
+
+	// Share of requests sent to the endpoint among the endpoints with the same priority.
+	Weight float64
+}
+
+// PrmServiceClientMulti groups parameters of NewServiceClientMulti.
+type PrmServiceClientMulti struct {
+	// Key to sign tree service requests.
+	Key *keys.PrivateKey
+
+	// Tree service endpoints.
+	Endpoints []TreeEndpoint
+
+	// Timeout of the endpoint health check.
+	HealthcheckTimeout time.Duration
+
+	// Interval of the endpoints health check.
+	RebalanceInterval time.Duration
+
+	// Logger is used to report endpoint health changes.
+	Logger *zap.Logger
+}
+
+// TreeEndpointStatistic represents statistics of the tree service endpoint.
+type TreeEndpointStatistic struct {
+	Address  string
+	Healthy  bool
+	Requests uint64
+	Errors   uint64
+}
+
+// treeEndpointClient is a ServiceClient of the single endpoint.
+type treeEndpointClient interface {
+	ServiceClient
+	Healthcheck(context.Context) error
+	io.Closer
+}
+
+type treeEndpoint struct {
+	address string
+	weight  float64
+	client  treeEndpointClient
+
+	healthy  uint32
+	requests uint64
+	errors   uint64
+}
+
+func (e *treeEndpoint) isHealthy() bool {
+	return atomic.LoadUint32(&e.healthy) == 1
+}
+
+// setHealthy updates endpoint health and reports if it was changed.
+func (e *treeEndpoint) setHealthy(healthy bool) bool {
+	var val uint32
+	if healthy {
+		val = 1
+	}
+	return atomic.SwapUint32(&e.healthy, val) != val
+}
+
+// ServiceClientMulti is a ServiceClient balancing requests between several tree service endpoints
+// the same way as connection pool does for NeoFS nodes: endpoints with the lowest priority
+// value are used while at least one of them is healthy, requests are distributed between
+// them according to the weights. Idempotent requests failed because of the endpoint
+// unavailability are retried on other endpoints.
+type ServiceClientMulti struct {
+	log                *zap.Logger
+	groups             [][]*treeEndpoint
+	healthcheckTimeout time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// errNoHealthyTreeEndpoints is returned when all tree service endpoints are unhealthy.
+var errNoHealthyTreeEndpoints = errors.New("no healthy tree service endpoints")
+
+// NewServiceClientMulti creates ServiceClientMulti and checks endpoints health.
+// At least one endpoint must be healthy.
+func NewServiceClientMulti(ctx context.Context, prm PrmServiceClientMulti) (*ServiceClientMulti, error) {
+	return newServiceClientMulti(ctx, prm, func(addr string) (treeEndpointClient, error) {
+		return dialServiceClientGRPC(addr, prm.Key)
+	})
+}
+
+func newServiceClientMulti(ctx context.Context, prm PrmServiceClientMulti, dial func(string) (treeEndpointClient, error)) (*ServiceClientMulti, error) {
+	if len(prm.Endpoints) == 0 {
+		return nil, errors.New("no tree service endpoints")
+	}
+	if prm.Logger == nil {
+		prm.Logger = zap.NewNop()
+	}
+
+	endpoints := make([]TreeEndpoint, len(prm.Endpoints))
+	copy(endpoints, prm.Endpoints)
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].Priority < endpoints[j].Priority
+	})
+
+	c := &ServiceClientMulti{
+		log:                prm.Logger,
+		healthcheckTimeout: prm.HealthcheckTimeout,
+		rand:               rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	for i, e := range endpoints {
+		client, err := dial(e.Address)
+		if err != nil {
+			_ = c.Close()
+			return nil, fmt.Errorf("dial tree service '%s': %w", e.Address, err)
+		}
+
+		endpoint := &treeEndpoint{
+			address: e.Address,
+			weight:  e.Weight,
+			client:  metricsEndpointClient{treeEndpointClient: client, address: e.Address},
+		}
+		if i == 0 || e.Priority != endpoints[i-1].Priority {
+			c.groups = append(c.groups, nil)
+		}
+		c.groups[len(c.groups)-1] = append(c.groups[len(c.groups)-1], endpoint)
+	}
+
+	c.healthcheck(ctx)
+	if len(c.healthyEndpoints()) == 0 {
+		_ = c.Close()
+		return nil, errNoHealthyTreeEndpoints
+	}
+
+	if prm.RebalanceInterval > 0 {
+		var runCtx context.Context
+		runCtx, c.cancel = context.WithCancel(context.Background())
+		c.wg.Add(1)
+		go c.rebalance(runCtx, prm.RebalanceInterval)
+	}
+
+	return c, nil
+}
+
+// Close stops endpoints health checks and closes the connections.
+func (c *ServiceClientMulti) Close() error {
+	if c.cancel != nil {
+		c.cancel()
+		c.wg.Wait()
+	}
+
+	var err error
+	for _, group := range c.groups {
+		for _, e := range group {
+			if closeErr := e.client.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	}
+
+	return err
+}
+
+// Statistic returns statistics of all tree service endpoints.
+func (c *ServiceClientMulti) Statistic() []TreeEndpointStatistic {
+	var res []TreeEndpointStatistic
+	for _, group := range c.groups {
+		for _, e := range group {
+			res = append(res, TreeEndpointStatistic{
+				Address:  e.address,
+				Healthy:  e.isHealthy(),
+				Requests: atomic.LoadUint64(&e.requests),
+				Errors:   atomic.LoadUint64(&e.errors),
+			})
+		}
+	}
+
+	return res
+}
+
+func (c *ServiceClientMulti) rebalance(ctx context.Context, interval time.Duration) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.healthcheck(ctx)
+		}
+	}
+}
+
+func (c *ServiceClientMulti) healthcheck(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, group := range c.groups {
+		for _, e := range group {
+			wg.Add(1)
+			go func(e *treeEndpoint) {
+				defer wg.Done()
+
+				checkCtx := ctx
+				if c.healthcheckTimeout > 0 {
+					var cancel context.CancelFunc
+					checkCtx, cancel = context.WithTimeout(ctx, c.healthcheckTimeout)
+					defer cancel()
+				}
+
+				err := e.client.Healthcheck(checkCtx)
+				if !e.setHealthy(err == nil) {
+					return
+				}
+				if err != nil {
+					c.log.Warn("tree service endpoint is unhealthy", zap.String("address", e.address), zap.Error(err))
+				} else {
+					c.log.Info("tree service endpoint is healthy", zap.String("address", e.address))
+				}
+			}(e)
+		}
+	}
+	wg.Wait()
+}
+
+// healthyEndpoints returns healthy endpoints in the order they should be tried:
+// groups are ordered by priority, endpoints in the group are shuffled according to weights.
+func (c *ServiceClientMulti) healthyEndpoints() []*treeEndpoint {
+	var res []*treeEndpoint
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, group := range c.groups {
+		healthy := make([]*treeEndpoint, 0, len(group))
+		var total float64
+		for _, e := range group {
+			if e.isHealthy() {
+				healthy = append(healthy, e)
+				total += e.weight
+			}
+		}
+
+		for len(healthy) > 0 {
+			i := c.pickWeighted(healthy, total)
+			total -= healthy[i].weight
+			res = append(res, healthy[i])
+			healthy = append(healthy[:i], healthy[i+1:]...)
+		}
+	}
+
+	return res
+}
+
+func (c *ServiceClientMulti) pickWeighted(endpoints []*treeEndpoint, total float64) int {
+	if total <= 0 {
+		return c.rand.Intn(len(endpoints))
+	}
+
+	point := c.rand.Float64() * total
+	for i, e := range endpoints {
+		if point < e.weight {
+			return i
+		}
+		point -= e.weight
+	}
+
+	return len(endpoints) - 1
+}
+
+// do executes the request on healthy endpoints. The request is retried on the next endpoint
+// only if it's idempotent and the current endpoint is unavailable.
+func (c *ServiceClientMulti) do(ctx context.Context, idempotent bool, f func(ServiceClient) error) error {
+	endpoints := c.healthyEndpoints()
+	if len(endpoints) == 0 {
+		return errNoHealthyTreeEndpoints
+	}
+
+	var err error
+	for _, e := range endpoints {
+		atomic.AddUint64(&e.requests, 1)
+
+		if err = f(e.client); err == nil || isTreeLogicalError(err) {
+			return err
+		}
+
+		atomic.AddUint64(&e.errors, 1)
+
+		if ctx.Err() != nil || !isTreeUnavailable(err) {
+			return err
+		}
+
+		if e.setHealthy(false) {
+			c.log.Warn("tree service endpoint is unhealthy", zap.String("address", e.address), zap.Error(err))
+		}
+
+		if !idempotent {
+			return err
+		}
+	}
+
+	return err
+}
+
+// isTreeLogicalError checks if the error is a response of the tree service, not an endpoint failure.
+func isTreeLogicalError(err error) bool {
+	return errors.Is(err, layer.ErrNodeNotFound) || errors.Is(err, layer.ErrNodeAccessDenied)
+}
+
+// isTreeUnavailable checks if the error is caused by the endpoint unavailability.
+func isTreeUnavailable(err error) bool {
+	var st interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &st) {
+		return false
+	}
+
+	switch st.GRPCStatus().Code() {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *ServiceClientMulti) GetNodes(ctx context.Context, p *GetNodesParams) ([]NodeResponse, error) {
+	var res []NodeResponse
+	err := c.do(ctx, true, func(client ServiceClient) (err error) {
+		res, err = client.GetNodes(ctx, p)
+		return err
+	})
+	return res, err
+}
+
+func (c *ServiceClientMulti) GetSubTree(ctx context.Context, bktInfo *data.BucketInfo, treeID string, rootID uint64, depth uint32) ([]NodeResponse, error) {
+	var res []NodeResponse
+	err := c.do(ctx, true, func(client ServiceClient) (err error) {
+		res, err = client.GetSubTree(ctx, bktInfo, treeID, rootID, depth)
+		return err
+	})
+	return res, err
+}
+
+func (c *ServiceClientMulti) AddNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, parent uint64, meta map[string]string) (uint64, error) {
+	var res uint64
+	err := c.do(ctx, false, func(client ServiceClient) (err error) {
+		res, err = client.AddNode(ctx, bktInfo, treeID, parent, meta)
+		return err
+	})
+	return res, err
+}
+
+func (c *ServiceClientMulti) AddNodeByPath(ctx context.Context, bktInfo *data.BucketInfo, treeID string, path []string, meta map[string]string) (uint64, error) {
+	var res uint64
+	err := c.do(ctx, false, func(client ServiceClient) (err error) {
+		res, err = client.AddNodeByPath(ctx, bktInfo, treeID, path, meta)
+		return err
+	})
+	return res, err
+}
+
+func (c *ServiceClientMulti) MoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID, parentID uint64, meta map[string]string) error {
+	return c.do(ctx, true, func(client ServiceClient) error {
+		return client.MoveNode(ctx, bktInfo, treeID, nodeID, parentID, meta)
+	})
+}
+
+func (c *ServiceClientMulti) RemoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) error {
+	// retry of the applied removal would fail, so it isn't considered idempotent
+	return c.do(ctx, false, func(client ServiceClient) error {
+		return client.RemoveNode(ctx, bktInfo, treeID, nodeID)
+	})
+}
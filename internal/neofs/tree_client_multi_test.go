@@ -0,0 +1,239 @@
+package neofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/api/layer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type treeEndpointMock struct {
+	ServiceClient
+
+	address   string
+	healthErr error
+	err       error
+	calls     int
+	subTree   []NodeResponse
+}
+
+func (m *treeEndpointMock) Healthcheck(context.Context) error {
+	return m.healthErr
+}
+
+func (m *treeEndpointMock) Close() error {
+	return nil
+}
+
+func (m *treeEndpointMock) GetNodes(context.Context, *GetNodesParams) ([]NodeResponse, error) {
+	m.calls++
+	return nil, m.err
+}
+
+func (m *treeEndpointMock) GetSubTree(context.Context, *data.BucketInfo, string, uint64, uint32) ([]NodeResponse, error) {
+	m.calls++
+	return m.subTree, m.err
+}
+
+func (m *treeEndpointMock) AddNode(context.Context, *data.BucketInfo, string, uint64, map[string]string) (uint64, error) {
+	m.calls++
+	return 1, m.err
+}
+
+func newServiceClientMultiMock(t *testing.T, endpoints []TreeEndpoint, mocks map[string]*treeEndpointMock) *ServiceClientMulti {
+	c, err := newServiceClientMulti(context.Background(), PrmServiceClientMulti{Endpoints: endpoints},
+		func(addr string) (treeEndpointClient, error) {
+			mock, ok := mocks[addr]
+			if !ok {
+				return nil, fmt.Errorf("unknown address %s", addr)
+			}
+			return mock, nil
+		})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestServiceClientMultiPriority(t *testing.T) {
+	ctx := context.Background()
+	mocks := map[string]*treeEndpointMock{
+		"node1": {address: "node1"},
+		"node2": {address: "node2"},
+		"node3": {address: "node3", healthErr: errors.New("unavailable")},
+	}
+
+	c := newServiceClientMultiMock(t, []TreeEndpoint{
+		{Address: "node2", Priority: 2, Weight: 1},
+		{Address: "node1", Priority: 1, Weight: 1},
+		{Address: "node3", Priority: 1, Weight: 1},
+	}, mocks)
+
+	for i := 0; i < 10; i++ {
+		_, err := c.GetNodes(ctx, &GetNodesParams{})
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, 10, mocks["node1"].calls)
+	require.Zero(t, mocks["node2"].calls)
+	require.Zero(t, mocks["node3"].calls)
+
+	stat := c.Statistic()
+	require.Len(t, stat, 3)
+	require.Equal(t, "node1", stat[0].Address)
+	require.True(t, stat[0].Healthy)
+	require.Equal(t, uint64(10), stat[0].Requests)
+	require.False(t, stat[1].Healthy)
+}
+
+func TestServiceClientMultiRetry(t *testing.T) {
+	ctx := context.Background()
+	unavailable := fmt.Errorf("failed to get node by path: %w", status.Error(codes.Unavailable, "connection refused"))
+
+	t.Run("idempotent request is retried", func(t *testing.T) {
+		mocks := map[string]*treeEndpointMock{
+			"node1": {address: "node1", err: unavailable},
+			"node2": {address: "node2"},
+		}
+		c := newServiceClientMultiMock(t, []TreeEndpoint{
+			{Address: "node1", Priority: 1, Weight: 1},
+			{Address: "node2", Priority: 2, Weight: 1},
+		}, mocks)
+
+		_, err := c.GetNodes(ctx, &GetNodesParams{})
+		require.NoError(t, err)
+		require.Equal(t, 1, mocks["node1"].calls)
+		require.Equal(t, 1, mocks["node2"].calls)
+
+		// failed endpoint isn't used until the next successful health check
+		_, err = c.GetNodes(ctx, &GetNodesParams{})
+		require.NoError(t, err)
+		require.Equal(t, 1, mocks["node1"].calls)
+		require.Equal(t, 2, mocks["node2"].calls)
+
+		stat := c.Statistic()
+		require.False(t, stat[0].Healthy)
+		require.Equal(t, uint64(1), stat[0].Errors)
+
+		mocks["node1"].err = nil
+		c.healthcheck(ctx)
+		_, err = c.GetNodes(ctx, &GetNodesParams{})
+		require.NoError(t, err)
+		require.Equal(t, 2, mocks["node1"].calls)
+	})
+
+	t.Run("non-idempotent request isn't retried", func(t *testing.T) {
+		mocks := map[string]*treeEndpointMock{
+			"node1": {address: "node1", err: unavailable},
+			"node2": {address: "node2"},
+		}
+		c := newServiceClientMultiMock(t, []TreeEndpoint{
+			{Address: "node1", Priority: 1, Weight: 1},
+			{Address: "node2", Priority: 2, Weight: 1},
+		}, mocks)
+
+		_, err := c.AddNode(ctx, &data.BucketInfo{}, "tree", 0, nil)
+		require.ErrorIs(t, err, unavailable)
+		require.Zero(t, mocks["node2"].calls)
+
+		_, err = c.AddNode(ctx, &data.BucketInfo{}, "tree", 0, nil)
+		require.NoError(t, err)
+		require.Equal(t, 1, mocks["node2"].calls)
+	})
+
+	t.Run("tree service errors aren't retried", func(t *testing.T) {
+		mocks := map[string]*treeEndpointMock{
+			"node1": {address: "node1", err: layer.ErrNodeNotFound},
+			"node2": {address: "node2"},
+		}
+		c := newServiceClientMultiMock(t, []TreeEndpoint{
+			{Address: "node1", Priority: 1, Weight: 1},
+			{Address: "node2", Priority: 2, Weight: 1},
+		}, mocks)
+
+		_, err := c.GetNodes(ctx, &GetNodesParams{})
+		require.ErrorIs(t, err, layer.ErrNodeNotFound)
+		require.Zero(t, mocks["node2"].calls)
+		require.True(t, c.Statistic()[0].Healthy)
+	})
+}
+
+func TestServiceClientMultiNoHealthy(t *testing.T) {
+	mocks := map[string]*treeEndpointMock{
+		"node1": {address: "node1", healthErr: errors.New("unavailable")},
+	}
+
+	_, err := newServiceClientMulti(context.Background(), PrmServiceClientMulti{
+		Endpoints: []TreeEndpoint{{Address: "node1", Priority: 1, Weight: 1}},
+	}, func(addr string) (treeEndpointClient, error) {
+		return mocks[addr], nil
+	})
+	require.ErrorIs(t, err, errNoHealthyTreeEndpoints)
+}
+
+// sampleCount returns the number of samples of the histogram or the value of the counter with the given labels.
+func sampleCount(t *testing.T, name string, labels map[string]string) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+	loop:
+		for _, m := range family.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if v, ok := labels[l.GetName()]; ok && v != l.GetValue() {
+					continue loop
+				}
+			}
+			if m.GetHistogram() != nil {
+				return m.GetHistogram().GetSampleCount()
+			}
+			return uint64(m.GetCounter().GetValue())
+		}
+	}
+
+	return 0
+}
+
+func TestServiceClientMultiMetrics(t *testing.T) {
+	ctx := context.Background()
+	mocks := map[string]*treeEndpointMock{
+		"metrics-node1": {address: "metrics-node1", err: status.Error(codes.Unavailable, "connection refused")},
+		"metrics-node2": {address: "metrics-node2", subTree: make([]NodeResponse, 3)},
+	}
+	c := newServiceClientMultiMock(t, []TreeEndpoint{
+		{Address: "metrics-node1", Priority: 1, Weight: 1},
+		{Address: "metrics-node2", Priority: 2, Weight: 1},
+	}, mocks)
+
+	res, err := c.GetSubTree(ctx, &data.BucketInfo{}, "tree", 0, 0)
+	require.NoError(t, err)
+	require.Len(t, res, 3)
+
+	mocks["metrics-node2"].err = layer.ErrNodeNotFound
+	_, err = c.GetNodes(ctx, &GetNodesParams{})
+	require.ErrorIs(t, err, layer.ErrNodeNotFound)
+
+	node1 := map[string]string{"node": "metrics-node1", "method": treeMethodGetSubTree}
+	require.EqualValues(t, 1, sampleCount(t, "neofs_s3_tree_request_seconds", node1))
+	require.EqualValues(t, 1, sampleCount(t, "neofs_s3_tree_errors_total", node1))
+
+	node2 := map[string]string{"node": "metrics-node2", "method": treeMethodGetSubTree}
+	require.EqualValues(t, 1, sampleCount(t, "neofs_s3_tree_request_seconds", node2))
+	require.Zero(t, sampleCount(t, "neofs_s3_tree_errors_total", node2))
+	require.EqualValues(t, 1, sampleCount(t, "neofs_s3_tree_subtree_nodes", map[string]string{"node": "metrics-node2"}))
+
+	// missing node isn't a failure of the endpoint
+	node2["method"] = treeMethodGetNodes
+	require.EqualValues(t, 1, sampleCount(t, "neofs_s3_tree_request_seconds", node2))
+	require.Zero(t, sampleCount(t, "neofs_s3_tree_errors_total", node2))
+}
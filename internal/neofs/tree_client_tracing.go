@@ -0,0 +1,76 @@
+package neofs
+
+import (
+	"context"
+	"io"
+
+	"github.com/nspcc-dev/neofs-s3-gw/api/data"
+	"github.com/nspcc-dev/neofs-s3-gw/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingServiceClient is a ServiceClient which starts a span for every tree service call.
+type tracingServiceClient struct {
+	service ServiceClient
+}
+
+func startTreeSpan(ctx context.Context, method string, bktInfo *data.BucketInfo, treeID string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		tracing.AttrBucket.String(bktInfo.Name),
+		tracing.AttrContainer.String(bktInfo.CID.EncodeToString()),
+		tracing.AttrTree.String(treeID),
+	)
+	return tracing.StartSpan(ctx, "tree."+method, attrs...)
+}
+
+func (c tracingServiceClient) GetNodes(ctx context.Context, p *GetNodesParams) (res []NodeResponse, err error) {
+	ctx, span := startTreeSpan(ctx, "GetNodes", p.BktInfo, p.TreeID)
+	defer func() {
+		span.SetAttributes(attribute.Int("tree.nodes", len(res)))
+		tracing.EndSpan(span, err)
+	}()
+	return c.service.GetNodes(ctx, p)
+}
+
+func (c tracingServiceClient) GetSubTree(ctx context.Context, bktInfo *data.BucketInfo, treeID string, rootID uint64, depth uint32) (res []NodeResponse, err error) {
+	ctx, span := startTreeSpan(ctx, "GetSubTree", bktInfo, treeID)
+	defer func() {
+		span.SetAttributes(attribute.Int("tree.nodes", len(res)))
+		tracing.EndSpan(span, err)
+	}()
+	return c.service.GetSubTree(ctx, bktInfo, treeID, rootID, depth)
+}
+
+func (c tracingServiceClient) AddNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, parent uint64, meta map[string]string) (id uint64, err error) {
+	ctx, span := startTreeSpan(ctx, "AddNode", bktInfo, treeID)
+	defer func() { tracing.EndSpan(span, err) }()
+	return c.service.AddNode(ctx, bktInfo, treeID, parent, meta)
+}
+
+func (c tracingServiceClient) AddNodeByPath(ctx context.Context, bktInfo *data.BucketInfo, treeID string, path []string, meta map[string]string) (id uint64, err error) {
+	ctx, span := startTreeSpan(ctx, "AddNodeByPath", bktInfo, treeID)
+	defer func() { tracing.EndSpan(span, err) }()
+	return c.service.AddNodeByPath(ctx, bktInfo, treeID, path, meta)
+}
+
+func (c tracingServiceClient) MoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID, parentID uint64, meta map[string]string) (err error) {
+	ctx, span := startTreeSpan(ctx, "MoveNode", bktInfo, treeID)
+	defer func() { tracing.EndSpan(span, err) }()
+	return c.service.MoveNode(ctx, bktInfo, treeID, nodeID, parentID, meta)
+}
+
+func (c tracingServiceClient) RemoveNode(ctx context.Context, bktInfo *data.BucketInfo, treeID string, nodeID uint64) (err error) {
+	ctx, span := startTreeSpan(ctx, "RemoveNode", bktInfo, treeID)
+	defer func() { tracing.EndSpan(span, err) }()
+	return c.service.RemoveNode(ctx, bktInfo, treeID, nodeID)
+}
+
+// Close closes the underlying service client if it's closable.
+func (c tracingServiceClient) Close() error {
+	if closer, ok := c.service.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
@@ -6,7 +6,7 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-func (c *TreeClient) signData(buf []byte, f func(key, sign []byte)) error {
+func (c *ServiceClientGRPC) signData(buf []byte, f func(key, sign []byte)) error {
 	// crypto package should not be used outside of API libraries (see neofs-node#491).
 	// For now tree service does not include into SDK Client nor SDK Pool, so there is no choice.
 	// When SDK library adopts Tree service client, this should be dropped.
@@ -19,7 +19,7 @@ func (c *TreeClient) signData(buf []byte, f func(key, sign []byte)) error {
 	return nil
 }
 
-func (c *TreeClient) signRequest(requestBody proto.Message, f func(key, sign []byte)) error {
+func (c *ServiceClientGRPC) signRequest(requestBody proto.Message, f func(key, sign []byte)) error {
 	buf, err := proto.Marshal(requestBody)
 	if err != nil {
 		return err
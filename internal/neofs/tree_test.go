@@ -121,3 +121,24 @@ func TestHandleError(t *testing.T) {
 		})
 	}
 }
+
+func TestQuotaEncoding(t *testing.T) {
+	quota := &data.BucketQuota{
+		MaxSize:        1024,
+		MaxObjects:     10,
+		SoftMaxSize:    512,
+		SoftMaxObjects: 5,
+	}
+
+	encoded := encodeQuota(quota)
+	require.Equal(t, "1024,10,512,5", encoded)
+
+	parsed, err := parseQuota(encoded)
+	require.NoError(t, err)
+	require.Equal(t, quota, parsed)
+
+	for _, invalid := range []string{"", "1,2,3", "1,2,3,a", "-1,0,0,0"} {
+		_, err = parseQuota(invalid)
+		require.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// fileClient writes OTLP export requests to a file or stdout as JSON lines.
+// It implements otlptrace.Client interface, so spans are transformed to OTLP by the exporter.
+type fileClient struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewFileExporter creates an exporter appending spans to the file, one OTLP ExportTraceServiceRequest
+// message in protobuf JSON encoding per exported batch. Spans are written to stdout if the path is empty.
+func NewFileExporter(ctx context.Context, path string) (*otlptrace.Exporter, error) {
+	c := &fileClient{w: os.Stdout}
+	if path != "" {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("open traces file: %w", err)
+		}
+		c.w, c.closer = f, f
+	}
+
+	return otlptrace.New(ctx, c)
+}
+
+// Start implements otlptrace.Client interface.
+func (c *fileClient) Start(context.Context) error {
+	return nil
+}
+
+// Stop implements otlptrace.Client interface.
+func (c *fileClient) Stop(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.w = nil
+	if c.closer != nil {
+		return c.closer.Close()
+	}
+
+	return nil
+}
+
+// UploadTraces implements otlptrace.Client interface.
+func (c *fileClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	if len(protoSpans) == 0 {
+		return nil
+	}
+
+	data, err := protojson.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	if err != nil {
+		return fmt.Errorf("encode spans: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.w == nil {
+		return nil
+	}
+
+	if _, err = c.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write spans: %w", err)
+	}
+
+	return nil
+}
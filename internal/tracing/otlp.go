@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+)
+
+// CompressionGzip enables gzip compression of export requests.
+const CompressionGzip = gzip.Name
+
+// DefaultTimeout is a default timeout of the export to the OTLP receiver including retries.
+const DefaultTimeout = 10 * time.Second
+
+// Intervals between retries of the failed exports.
+const (
+	otlpRetryInitialInterval = time.Second
+	otlpRetryMaxInterval     = 5 * time.Second
+)
+
+// NewOTLPExporter creates an exporter sending spans to the OTLP/gRPC receiver. Failed exports are
+// retried with exponential backoff until the timeout.
+func NewOTLPExporter(ctx context.Context, cfg Config) (*otlptrace.Exporter, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("otlp endpoint is not set")
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(cfg.Endpoint),
+		otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig{
+			Enabled:         true,
+			InitialInterval: otlpRetryInitialInterval,
+			MaxInterval:     otlpRetryMaxInterval,
+			MaxElapsedTime:  timeout,
+		}),
+		otlptracegrpc.WithTimeout(timeout),
+	}
+
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else {
+		tlsCfg, err := otlpTLSConfig(cfg.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+	}
+
+	if len(cfg.Headers) != 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+	}
+
+	switch cfg.Compression {
+	case "":
+	case CompressionGzip:
+		opts = append(opts, otlptracegrpc.WithCompressor(CompressionGzip))
+	default:
+		return nil, fmt.Errorf("unknown otlp compression: '%s'", cfg.Compression)
+	}
+
+	return otlptrace.New(ctx, otlptracegrpc.NewClient(opts...))
+}
+
+// otlpTLSConfig returns TLS config verifying the receiver with the certificates from CA file
+// or with the system roots if the file isn't set.
+func otlpTLSConfig(caFile string) (*tls.Config, error) {
+	if caFile == "" {
+		return &tls.Config{}, nil
+	}
+
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("read otlp ca file: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates in otlp ca file '%s'", caFile)
+	}
+
+	return &tls.Config{RootCAs: pool}, nil
+}
@@ -0,0 +1,143 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Supported span exporters.
+const (
+	ExporterOTLP = "otlp"
+	ExporterFile = "file"
+)
+
+// instrumentationName is a name of the tracer used by the gateway.
+const instrumentationName = "github.com/nspcc-dev/neofs-s3-gw"
+
+// Span attributes set by the gateway.
+const (
+	AttrBucket      = attribute.Key("s3.bucket")
+	AttrObject      = attribute.Key("s3.object")
+	AttrRequestID   = attribute.Key("s3.request_id")
+	AttrSize        = attribute.Key("s3.size")
+	AttrContainer   = attribute.Key("neofs.container")
+	AttrObjectID    = attribute.Key("neofs.object")
+	AttrTree        = attribute.Key("neofs.tree")
+	AttrCacheHit    = attribute.Key("cache.hit")
+	AttrAccessKeyID = attribute.Key("auth.access_key_id")
+)
+
+// ErrUnknownExporter is returned when the configured exporter isn't supported.
+var ErrUnknownExporter = errors.New("unknown tracing exporter")
+
+// Config contains parameters of the tracer provider.
+type Config struct {
+	// Exporter is one of ExporterOTLP or ExporterFile.
+	Exporter string
+
+	// Endpoint is an address of the OTLP/gRPC traces receiver, e.g. localhost:4317.
+	Endpoint string
+
+	// Insecure disables TLS of the connection to the receiver.
+	Insecure bool
+
+	// CAFile is a path of PEM file with certificates to verify the receiver.
+	// System roots are used if it's empty.
+	CAFile string
+
+	// Headers are sent with every export request, e.g. to authenticate the gateway.
+	Headers map[string]string
+
+	// Compression is CompressionGzip or empty to send export requests uncompressed.
+	Compression string
+
+	// Timeout limits every export including retries.
+	Timeout time.Duration
+
+	// FilePath is a path of the file which spans are written to by the file exporter.
+	// Spans are written to stdout if it's empty.
+	FilePath string
+
+	// SamplingRatio is a share of the root spans which are sampled.
+	// Spans with a parent follow the parent sampling decision.
+	SamplingRatio float64
+
+	// Service and Version describe the gateway in the exported resource.
+	Service string
+	Version string
+}
+
+// NewProvider creates a tracer provider which exports spans in batches with the configured exporter.
+func NewProvider(ctx context.Context, cfg Config) (*sdktrace.TracerProvider, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch cfg.Exporter {
+	case ExporterOTLP:
+		exporter, err = NewOTLPExporter(ctx, cfg)
+	case ExporterFile:
+		exporter, err = NewFileExporter(ctx, cfg.FilePath)
+	default:
+		return nil, fmt.Errorf("%w: '%s'", ErrUnknownExporter, cfg.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceNameKey.String(cfg.Service),
+		semconv.ServiceVersionKey.String(cfg.Version),
+	)
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SamplingRatio))),
+	), nil
+}
+
+// SetProvider sets the provider used by the gateway spans and enables W3C trace context propagation.
+func SetProvider(p trace.TracerProvider) {
+	otel.SetTracerProvider(p)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// StartSpan starts a span with the attributes as a child of the span from the context.
+// Spans aren't recorded until the provider is set.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartServerSpan starts a span of the request served by the gateway.
+func StartServerSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithAttributes(attrs...), trace.WithSpanKind(trace.SpanKindServer))
+}
+
+// EndSpan marks the span as failed if the error isn't nil and ends it.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// TraceCacheLookup records the lookup in the named cache as a span with the hit attribute.
+func TraceCacheLookup(ctx context.Context, cache string, hit bool) {
+	_, span := StartSpan(ctx, "cache."+cache, AttrCacheHit.Bool(hit))
+	span.End()
+}
@@ -0,0 +1,165 @@
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func recordSpans(t *testing.T) []sdktrace.ReadOnlySpan {
+	recorder := tracetest.NewSpanRecorder()
+	SetProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	// continue the trace of the traceparent header
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	ctx, span := StartServerSpan(ctx, "PutObject", AttrBucket.String("bucket"), AttrSize.Int64(10))
+	TraceCacheLookup(ctx, "objects", false)
+	_, child := StartSpan(ctx, "neofs.CreateObject")
+	EndSpan(child, errors.New("access denied"))
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	return spans
+}
+
+func spansByName(t *testing.T, resourceSpans []*tracepb.ResourceSpans) map[string]*tracepb.Span {
+	require.Len(t, resourceSpans, 1)
+	require.Len(t, resourceSpans[0].ScopeSpans, 1)
+	require.Equal(t, instrumentationName, resourceSpans[0].ScopeSpans[0].Scope.Name)
+
+	res := make(map[string]*tracepb.Span)
+	for _, span := range resourceSpans[0].ScopeSpans[0].Spans {
+		res[span.Name] = span
+	}
+	return res
+}
+
+func checkSpans(t *testing.T, spans map[string]*tracepb.Span) {
+	require.Len(t, spans, 3)
+
+	root := spans["PutObject"]
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", hex.EncodeToString(root.TraceId))
+	require.Equal(t, "00f067aa0ba902b7", hex.EncodeToString(root.ParentSpanId))
+	require.Equal(t, tracepb.Span_SPAN_KIND_SERVER, root.Kind)
+
+	cacheSpan := spans["cache.objects"]
+	require.Equal(t, root.SpanId, cacheSpan.ParentSpanId)
+	require.Equal(t, "cache.hit", cacheSpan.Attributes[0].Key)
+	require.False(t, cacheSpan.Attributes[0].Value.GetBoolValue())
+
+	child := spans["neofs.CreateObject"]
+	require.Equal(t, root.TraceId, child.TraceId)
+	require.Equal(t, tracepb.Status_STATUS_CODE_ERROR, child.Status.Code)
+	require.Equal(t, "access denied", child.Status.Message)
+	require.Len(t, child.Events, 1) // recorded error
+}
+
+func decodeLine(t *testing.T, line string) map[string]*tracepb.Span {
+	var req coltracepb.ExportTraceServiceRequest
+	require.NoError(t, protojson.Unmarshal([]byte(line), &req))
+	return spansByName(t, req.ResourceSpans)
+}
+
+func TestFileExporter(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "traces.json")
+	exporter, err := NewFileExporter(ctx, path)
+	require.NoError(t, err)
+
+	spans := recordSpans(t)
+	require.NoError(t, exporter.ExportSpans(ctx, spans[:1]))
+	require.NoError(t, exporter.ExportSpans(ctx, spans[1:]))
+	require.NoError(t, exporter.Shutdown(ctx))
+
+	// spans aren't written after shutdown
+	require.NoError(t, exporter.ExportSpans(ctx, spans))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	received := decodeLine(t, lines[0])
+	for name, span := range decodeLine(t, lines[1]) {
+		received[name] = span
+	}
+	checkSpans(t, received)
+}
+
+type traceReceiver struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	md            metadata.MD
+	resourceSpans []*tracepb.ResourceSpans
+}
+
+func (r *traceReceiver) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	r.md, _ = metadata.FromIncomingContext(ctx)
+	r.resourceSpans = append(r.resourceSpans, req.ResourceSpans...)
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestOTLPExporter(t *testing.T) {
+	ctx := context.Background()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	receiver := &traceReceiver{}
+	srv := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(srv, receiver)
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Stop()
+
+	_, err = NewOTLPExporter(ctx, Config{})
+	require.Error(t, err)
+
+	_, err = NewOTLPExporter(ctx, Config{Endpoint: ln.Addr().String(), Compression: "zstd"})
+	require.Error(t, err)
+
+	exporter, err := NewOTLPExporter(ctx, Config{
+		Endpoint:    ln.Addr().String(),
+		Insecure:    true,
+		Headers:     map[string]string{"authorization": "Bearer token"},
+		Compression: CompressionGzip,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, exporter.ExportSpans(ctx, recordSpans(t)))
+	require.NoError(t, exporter.Shutdown(ctx))
+
+	require.Equal(t, []string{"Bearer token"}, receiver.md.Get("authorization"))
+	checkSpans(t, spansByName(t, receiver.resourceSpans))
+}
+
+func TestNewProvider(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewProvider(ctx, Config{Exporter: "unknown"})
+	require.ErrorIs(t, err, ErrUnknownExporter)
+
+	p, err := NewProvider(ctx, Config{Exporter: ExporterFile, FilePath: filepath.Join(t.TempDir(), "traces.json"), SamplingRatio: 1})
+	require.NoError(t, err)
+	require.NoError(t, p.Shutdown(ctx))
+}